@@ -10,6 +10,16 @@ import (
 func main() {
 	config.ConnectRatesTable()
 	config.ConnectRouteMetricsTable()
+	config.ConnectCalendarsTable()
+	config.ConnectLotsTable()
+	config.ConnectProductsTable()
+	config.ConnectPromosTable()
+	config.ConnectOccupanciesTable()
+	config.ConnectSessionsTable()
+	config.ConnectReservationsTable()
+	config.ConnectPermitsTable()
+	config.ConnectMerchantsTable()
+	config.ConnectValidationsTable()
 	log.Infof("%s starting", config.Config.AppName)
 	seeder.Run()
 }
@@ -8,5 +8,15 @@ import (
 func main() {
 	config.ConnectRatesTable()
 	config.ConnectRouteMetricsTable()
+	config.ConnectCalendarsTable()
+	config.ConnectLotsTable()
+	config.ConnectProductsTable()
+	config.ConnectPromosTable()
+	config.ConnectOccupanciesTable()
+	config.ConnectSessionsTable()
+	config.ConnectReservationsTable()
+	config.ConnectPermitsTable()
+	config.ConnectMerchantsTable()
+	config.ConnectValidationsTable()
 	server.Start()
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"charlie-parker/internal/store"
 	"charlie-parker/pkg/types"
 	"os"
 
@@ -11,17 +12,67 @@ import (
 	"github.com/labstack/gommon/log"
 )
 
+// MemoryMode is the Mode that keeps all app data in process memory
+// rather than in DynamoDB, so that no Dynamo container is needed
+const MemoryMode = "memory"
+
 // Configuration contains relevant app environment variables
 type Configuration struct {
-	Mode                  string `default:"local"`
-	AppName               string `default:"charlie-parker"`
-	Region                string `default:"localhost"`
-	WebServerPort         string `default:"8554"`
-	DyDBEndpoint          string `default:"http://dynamo:8000"`
-	RatesTable            string `default:"cp-rates-local"`
-	RouteMetricsTable     string `default:"cp-route-metrics-local"`
-	RatesTableConn        dynamo.Table
-	RouteMetricsTableConn dynamo.Table
+	Mode                      string `default:"local"`
+	AppName                   string `default:"charlie-parker"`
+	Region                    string `default:"localhost"`
+	WebServerPort             string `default:"8554"`
+	DyDBEndpoint              string `default:"http://dynamo:8000"`
+	RatesTable                string `default:"cp-rates-local"`
+	RateSetsTable             string `default:"cp-rate-sets-local"`
+	RouteMetricsTable         string `default:"cp-route-metrics-local"`
+	CalendarsTable            string `default:"cp-calendars-local"`
+	LotsTable                 string `default:"cp-lots-local"`
+	ProductsTable             string `default:"cp-products-local"`
+	PromosTable               string `default:"cp-promos-local"`
+	OccupanciesTable          string `default:"cp-occupancies-local"`
+	SessionsTable             string `default:"cp-sessions-local"`
+	ReceiptCountersTable      string `default:"cp-receipt-counters-local"`
+	ActivePlatesTable         string `default:"cp-active-plates-local"`
+	ReservationsTable         string `default:"cp-reservations-local"`
+	ReservationSlotsTable     string `default:"cp-reservation-slots-local"`
+	PermitsTable              string `default:"cp-permits-local"`
+	ValidationsTable          string `default:"cp-validations-local"`
+	MerchantSpendTable        string `default:"cp-merchant-spend-local"`
+	MerchantsTable            string `default:"cp-merchants-local"`
+	RatesTableConn            dynamo.Table
+	RateSetsTableConn         dynamo.Table
+	RouteMetricsTableConn     dynamo.Table
+	CalendarsTableConn        dynamo.Table
+	LotsTableConn             dynamo.Table
+	ProductsTableConn         dynamo.Table
+	PromosTableConn           dynamo.Table
+	OccupanciesTableConn      dynamo.Table
+	SessionsTableConn         dynamo.Table
+	ReceiptCountersTableConn  dynamo.Table
+	ActivePlatesTableConn     dynamo.Table
+	ReservationsTableConn     dynamo.Table
+	ReservationSlotsTableConn dynamo.Table
+	PermitsTableConn          dynamo.Table
+	MerchantsTableConn        dynamo.Table
+	ValidationsTableConn      dynamo.Table
+	MerchantSpendTableConn    dynamo.Table
+	Rates                     store.RateStore         `ignored:"true"`
+	RouteMetrics              store.RouteMetricsStore `ignored:"true"`
+	Calendars                 store.CalendarStore     `ignored:"true"`
+	Lots                      store.LotStore          `ignored:"true"`
+	Products                  store.ProductStore      `ignored:"true"`
+	Promos                    store.PromoStore        `ignored:"true"`
+	Occupancies               store.OccupancyStore    `ignored:"true"`
+	Sessions                  store.SessionStore      `ignored:"true"`
+	Reservations              store.ReservationStore  `ignored:"true"`
+	Permits                   store.PermitStore       `ignored:"true"`
+	Merchants                 store.MerchantStore     `ignored:"true"`
+	Validations               store.ValidationStore   `ignored:"true"`
+
+	// CrossTimezoneOverlapCheck opts in to rejecting rates that overlap rates in other
+	// timezones at the same real-world instants, not just rates in the same timezone
+	CrossTimezoneOverlapCheck bool `default:"false"`
 }
 
 // Config is the app-wide Configuration
@@ -34,22 +85,180 @@ func init() {
 	}
 }
 
-// ConnectRatesTable connects to the rates table
+// ConnectRatesTable connects to the rates and rate sets tables, or to an in-memory
+// rate store when running in MemoryMode
 func ConnectRatesTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Rates store")
+		Config.Rates = store.NewMemoryRateStore()
+		return
+	}
 	log.Info("Connecting to Rates Table")
 	Config.RatesTableConn = connectDynamoDB(Config.RatesTable, types.Rate{})
+	log.Info("Connecting to Rate Sets Table")
+	Config.RateSetsTableConn = connectDynamoDB(Config.RateSetsTable, types.RateSet{})
+	Config.Rates = store.NewDynamoRateStore(dynamoDB(), Config.RatesTableConn, Config.RateSetsTableConn)
 }
 
-// ConnectRouteMetricsTable connects to the route metrics table
+// ConnectRouteMetricsTable connects to the route metrics table, or to an
+// in-memory route metrics store when running in MemoryMode
 func ConnectRouteMetricsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Route Metrics store")
+		Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+		return
+	}
 	log.Info("Connecting to Route Metrics Table")
 	Config.RouteMetricsTableConn = connectDynamoDB(Config.RouteMetricsTable, types.RouteMetrics{})
+	Config.RouteMetrics = store.NewDynamoRouteMetricsStore(Config.RouteMetricsTableConn)
+}
+
+// ConnectCalendarsTable connects to the calendars table, or to an
+// in-memory calendar store when running in MemoryMode
+func ConnectCalendarsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Calendars store")
+		Config.Calendars = store.NewMemoryCalendarStore()
+		return
+	}
+	log.Info("Connecting to Calendars Table")
+	Config.CalendarsTableConn = connectDynamoDB(Config.CalendarsTable, types.Calendar{})
+	Config.Calendars = store.NewDynamoCalendarStore(Config.CalendarsTableConn)
+}
+
+// ConnectLotsTable connects to the lots table, or to an
+// in-memory lot store when running in MemoryMode
+func ConnectLotsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Lots store")
+		Config.Lots = store.NewMemoryLotStore()
+		return
+	}
+	log.Info("Connecting to Lots Table")
+	Config.LotsTableConn = connectDynamoDB(Config.LotsTable, types.Lot{})
+	Config.Lots = store.NewDynamoLotStore(Config.LotsTableConn)
+}
+
+// ConnectProductsTable connects to the products table, or to an
+// in-memory product store when running in MemoryMode
+func ConnectProductsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Products store")
+		Config.Products = store.NewMemoryProductStore()
+		return
+	}
+	log.Info("Connecting to Products Table")
+	Config.ProductsTableConn = connectDynamoDB(Config.ProductsTable, types.Product{})
+	Config.Products = store.NewDynamoProductStore(Config.ProductsTableConn)
+}
+
+// ConnectPromosTable connects to the promos table, or to an
+// in-memory promo store when running in MemoryMode
+func ConnectPromosTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Promos store")
+		Config.Promos = store.NewMemoryPromoStore()
+		return
+	}
+	log.Info("Connecting to Promos Table")
+	Config.PromosTableConn = connectDynamoDB(Config.PromosTable, types.Promo{})
+	Config.Promos = store.NewDynamoPromoStore(Config.PromosTableConn)
+}
+
+// ConnectOccupanciesTable connects to the lot occupancies table, or to an
+// in-memory lot occupancy store when running in MemoryMode
+func ConnectOccupanciesTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Occupancies store")
+		Config.Occupancies = store.NewMemoryOccupancyStore()
+		return
+	}
+	log.Info("Connecting to Occupancies Table")
+	Config.OccupanciesTableConn = connectDynamoDB(Config.OccupanciesTable, types.Occupancy{})
+	Config.Occupancies = store.NewDynamoOccupancyStore(Config.OccupanciesTableConn)
+}
+
+// ConnectSessionsTable connects to the sessions, receipt counters and active plates tables, or to an
+// in-memory session store when running in MemoryMode
+func ConnectSessionsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Sessions store")
+		Config.Sessions = store.NewMemorySessionStore()
+		return
+	}
+	log.Info("Connecting to Sessions Table")
+	Config.SessionsTableConn = connectDynamoDB(Config.SessionsTable, types.Session{})
+	log.Info("Connecting to Receipt Counters Table")
+	Config.ReceiptCountersTableConn = connectDynamoDB(Config.ReceiptCountersTable, types.ReceiptCounter{})
+	log.Info("Connecting to Active Plates Table")
+	Config.ActivePlatesTableConn = connectDynamoDB(Config.ActivePlatesTable, types.ActivePlate{})
+	Config.Sessions = store.NewDynamoSessionStore(dynamoDB(), Config.SessionsTableConn, Config.ReceiptCountersTableConn, Config.ActivePlatesTableConn)
+}
+
+// ConnectReservationsTable connects to the reservations and reservation slots tables, or to an
+// in-memory reservation store when running in MemoryMode
+func ConnectReservationsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Reservations store")
+		Config.Reservations = store.NewMemoryReservationStore()
+		return
+	}
+	log.Info("Connecting to Reservations Table")
+	Config.ReservationsTableConn = connectDynamoDB(Config.ReservationsTable, types.Reservation{})
+	log.Info("Connecting to Reservation Slots Table")
+	Config.ReservationSlotsTableConn = connectDynamoDB(Config.ReservationSlotsTable, types.ReservationSlotCount{})
+	Config.Reservations = store.NewDynamoReservationStore(dynamoDB(), Config.ReservationsTableConn, Config.ReservationSlotsTableConn)
+}
+
+// ConnectPermitsTable connects to the permits table, or to an
+// in-memory permit store when running in MemoryMode
+func ConnectPermitsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Permits store")
+		Config.Permits = store.NewMemoryPermitStore()
+		return
+	}
+	log.Info("Connecting to Permits Table")
+	Config.PermitsTableConn = connectDynamoDB(Config.PermitsTable, types.Permit{})
+	Config.Permits = store.NewDynamoPermitStore(Config.PermitsTableConn)
+}
+
+// ConnectMerchantsTable connects to the merchants table, or to an
+// in-memory merchant store when running in MemoryMode
+func ConnectMerchantsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Merchants store")
+		Config.Merchants = store.NewMemoryMerchantStore()
+		return
+	}
+	log.Info("Connecting to Merchants Table")
+	Config.MerchantsTableConn = connectDynamoDB(Config.MerchantsTable, types.Merchant{})
+	Config.Merchants = store.NewDynamoMerchantStore(Config.MerchantsTableConn)
+}
+
+// ConnectValidationsTable connects to the validations and merchant spend tables, or to an
+// in-memory validation store when running in MemoryMode
+func ConnectValidationsTable() {
+	if Config.Mode == MemoryMode {
+		log.Info("Using in-memory Validations store")
+		Config.Validations = store.NewMemoryValidationStore()
+		return
+	}
+	log.Info("Connecting to Validations Table")
+	Config.ValidationsTableConn = connectDynamoDB(Config.ValidationsTable, types.Validation{})
+	log.Info("Connecting to Merchant Spend Table")
+	Config.MerchantSpendTableConn = connectDynamoDB(Config.MerchantSpendTable, types.MerchantSpend{})
+	Config.Validations = store.NewDynamoValidationStore(dynamoDB(), Config.ValidationsTableConn, Config.MerchantSpendTableConn)
+}
+
+// dynamoDB sets up a session to DynamoDB
+func dynamoDB() *dynamo.DB {
+	return dynamo.New(session.New(), &aws.Config{Endpoint: aws.String(Config.DyDBEndpoint), Region: aws.String(Config.Region)})
 }
 
 // connectDynamoDB connects to tableName in dynamodb
 func connectDynamoDB(tableName string, tableDataType interface{}) dynamo.Table {
-	// Setup a session to DynamoDB
-	dy := dynamo.New(session.New(), &aws.Config{Endpoint: aws.String(Config.DyDBEndpoint), Region: aws.String(Config.Region)})
+	dy := dynamoDB()
 	// Get all existing tables from DynamoDB.  Panic and exit if
 	// unable to communicate with Dynamo and check for tables
 	dynamoTables, err := dy.ListTables().All()
@@ -0,0 +1,190 @@
+package helpers
+
+import (
+	"bufio"
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetCalendars gets all of the calendars from the DB
+func GetCalendars() ([]types.Calendar, error) {
+	return config.Config.Calendars.All()
+}
+
+// GetCalendar gets the calendar with the given name from the DB
+func GetCalendar(name string) (types.Calendar, error) {
+	return config.Config.Calendars.Get(name)
+}
+
+// PutCalendar creates the calendar with the given name, or replaces its dates if it exists
+func PutCalendar(name string, in *types.PutCalendarInput) (types.Calendar, error) {
+	if in.Dates == nil {
+		return types.Calendar{}, errors.New("specify the calendar's dates")
+	}
+	return saveCalendar(name, in.Dates)
+}
+
+// ImportCalendar adds the dates in a calendar file to the calendar with the given name,
+// creating it if it does not exist. See parseCalendarFile for the formats that are understood
+func ImportCalendar(name string, file string) (types.Calendar, error) {
+	var (
+		err      error
+		dates    []string
+		calendar types.Calendar
+	)
+
+	if dates, err = parseCalendarFile(file); err != nil {
+		return calendar, err
+	}
+
+	if calendar, err = GetCalendar(name); err == nil {
+		dates = append(dates, calendar.Dates...)
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return calendar, err
+	}
+
+	return saveCalendar(name, dates)
+}
+
+// DeleteCalendar removes the calendar with the given name from the DB and returns it.
+// A calendar cannot be deleted while rates target it
+func DeleteCalendar(name string) (types.Calendar, error) {
+	var (
+		err      error
+		calendar types.Calendar
+		rates    []types.Rate
+	)
+
+	if calendar, err = GetCalendar(name); err != nil {
+		return calendar, err
+	}
+
+	if rates, err = GetRates(); err != nil {
+		return calendar, err
+	}
+
+	for _, rate := range rates {
+		if rate.Calendar == name {
+			return calendar, fmt.Errorf("calendar %s is used by rate %s", name, rate.UUID)
+		}
+	}
+
+	err = config.Config.Calendars.Delete(name)
+	return calendar, err
+}
+
+// saveCalendar validates and stores the calendar with the given name and dates. The override
+// rates that target the calendar must not overlap other override rates on its new dates
+func saveCalendar(name string, dates []string) (types.Calendar, error) {
+	var (
+		err       error
+		rates     []types.Rate
+		calendars []types.Calendar
+	)
+
+	calendar := types.Calendar{Name: name, Dates: getSortedDates(dates), UpdatedAt: time.Now().Unix()}
+	if err = validateCalendar(calendar); err != nil {
+		return calendar, err
+	}
+
+	if rates, err = GetRates(); err != nil {
+		return calendar, err
+	}
+
+	if calendars, err = GetCalendars(); err != nil {
+		return calendar, err
+	}
+	calendars = append(calendarsExcept(calendars, name), calendar)
+
+	for _, rate := range rates {
+		if rate.Calendar != name {
+			continue
+		}
+		if err = validateAgainstExistingRates(ratesExcept(rates, rate.UUID), calendars, getRateInput(rate)); err != nil {
+			return calendar, err
+		}
+	}
+
+	err = config.Config.Calendars.Put(calendar)
+	return calendar, err
+}
+
+// parseCalendarFile reads the dates out of a calendar file, which is either an iCalendar
+// (.ics) file, whose events' DTSTART dates are used, or a text or CSV file with a
+// YYYY-MM-DD date at the start of each line. Blank lines and lines starting with # are skipped
+func parseCalendarFile(file string) ([]string, error) {
+	var dates []string
+	iCalendar := strings.Contains(file, "BEGIN:VCALENDAR")
+
+	scanner := bufio.NewScanner(strings.NewReader(file))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if iCalendar {
+			// i.e. "DTSTART;VALUE=DATE:20171123"
+			if !strings.HasPrefix(text, "DTSTART") {
+				continue
+			}
+			value := text[strings.LastIndex(text, ":")+1:]
+			if len(value) < 8 {
+				return nil, fmt.Errorf("line %d: invalid DTSTART: %s", line, text)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTSTART: %s", line, text)
+			}
+			dates = append(dates, date.Format(effectiveDateLayout))
+			continue
+		}
+
+		date := strings.TrimSpace(strings.Split(text, ",")[0])
+		if _, err := time.Parse(effectiveDateLayout, date); err != nil {
+			return nil, fmt.Errorf("line %d: dates must be in the format YYYY-MM-DD: %s", line, date)
+		}
+		dates = append(dates, date)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(dates) == 0 {
+		return nil, errors.New("no dates found in calendar file")
+	}
+
+	return dates, nil
+}
+
+// getSortedDates returns dates in order without repeats
+func getSortedDates(dates []string) []string {
+	seen := make(map[string]bool, len(dates))
+	sorted := []string{}
+	for _, date := range dates {
+		if !seen[date] {
+			seen[date] = true
+			sorted = append(sorted, date)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// calendarsExcept returns calendars without the calendar with the given name
+func calendarsExcept(calendars []types.Calendar, name string) []types.Calendar {
+	var others []types.Calendar
+	for _, calendar := range calendars {
+		if calendar.Name != name {
+			others = append(others, calendar)
+		}
+	}
+	return others
+}
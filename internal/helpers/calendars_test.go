@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func Test_PutCalendar(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.PutCalendarInput
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Put",
+			in:   types.PutCalendarInput{Dates: []string{"2017-11-23", "2017-07-04", "2017-07-04"}},
+			want: []string{"2017-07-04", "2017-11-23"},
+		},
+		{
+			name:    "Invalid Date Error",
+			in:      types.PutCalendarInput{Dates: []string{"July 4th"}},
+			want:    []string{"2017-12-25"},
+			wantErr: true,
+		},
+		{
+			name:    "Overlapping Override Error",
+			in:      types.PutCalendarInput{Dates: []string{"2017-12-25", "2017-12-31"}},
+			want:    []string{"2017-12-25"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Dates Error",
+			in:      types.PutCalendarInput{},
+			want:    []string{"2017-12-25"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if _, err := PutCalendar("holidays", &types.PutCalendarInput{Dates: []string{"2017-12-25"}}); err != nil {
+				t.Fatalf("PutCalendar() setup error = %v", err)
+			}
+			overrides := []types.CreateRateInput{
+				{Calendar: "holidays", Times: "0000-2400", TZ: "America/Chicago", Price: 100},
+				{Dates: "2017-12-31", Times: "1800-2400", TZ: "America/Chicago", Price: 2000},
+			}
+			for _, override := range overrides {
+				if _, err := CreateRate(&override, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+
+			if _, err := PutCalendar("holidays", &test.in); (err != nil) != test.wantErr {
+				t.Errorf("PutCalendar() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if got, _ := GetCalendar("holidays"); !reflect.DeepEqual(got.Dates, test.want) {
+				t.Errorf("PutCalendar() left dates %v, want %v", got.Dates, test.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteCalendar(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   []types.CreateRateInput
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Delete",
+		},
+		{
+			name:    "Calendar In Use Error",
+			rates:   []types.CreateRateInput{{Calendar: "holidays", Times: "0000-2400", TZ: "America/Chicago", Price: 100}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if _, err := PutCalendar("holidays", &types.PutCalendarInput{Dates: []string{"2017-12-25"}}); err != nil {
+				t.Fatalf("PutCalendar() setup error = %v", err)
+			}
+			for _, rate := range test.rates {
+				if _, err := CreateRate(&rate, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+
+			if _, err := DeleteCalendar("holidays"); (err != nil) != test.wantErr {
+				t.Errorf("DeleteCalendar() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if _, err := GetCalendar("holidays"); (err == nil) != test.wantErr {
+				t.Errorf("DeleteCalendar() calendar exists = %v, want %v", err == nil, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseCalendarFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Text File",
+			file: "# 2017 holidays\n2017-07-04\n\n2017-11-23\n",
+			want: []string{"2017-07-04", "2017-11-23"},
+		},
+		{
+			name: "CSV File",
+			file: "2017-07-04,Independence Day\n2017-11-23,Thanksgiving\n",
+			want: []string{"2017-07-04", "2017-11-23"},
+		},
+		{
+			name: "iCalendar File",
+			file: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20170704\r\nSUMMARY:Independence Day\r\nEND:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\nDTSTART:20171123T000000Z\r\nSUMMARY:Thanksgiving\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			want: []string{"2017-07-04", "2017-11-23"},
+		},
+		{
+			name:    "Invalid Date Error",
+			file:    "07/04/2017\n",
+			wantErr: true,
+		},
+		{
+			name:    "Empty File Error",
+			file:    "# nothing yet\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseCalendarFile(test.file)
+			if (err != nil) != test.wantErr {
+				t.Errorf("parseCalendarFile() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseCalendarFile() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
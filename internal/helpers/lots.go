@@ -0,0 +1,253 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// GetLots gets all of the lots from the DB
+func GetLots() ([]types.Lot, error) {
+	return config.Config.Lots.All()
+}
+
+// GetLot gets the lot with the given uuid from the DB
+func GetLot(uuid string) (types.Lot, error) {
+	return config.Config.Lots.Get(uuid)
+}
+
+// CreateLot creates a lot in the DB
+func CreateLot(in *types.LotInput) (types.Lot, error) {
+	var (
+		err error
+		lot types.Lot
+	)
+
+	if err = validateLotInput(in); err != nil {
+		return lot, err
+	}
+
+	uu, _ := uuid.NewV4()
+	lot = types.Lot{
+		UUID:        uu.String(),
+		Name:        in.Name,
+		Address:     in.Address,
+		DailyMax:    in.DailyMax,
+		MinCharge:   in.MinCharge,
+		EntryGrace:  in.EntryGrace,
+		ExitGrace:   in.ExitGrace,
+		FreeMinutes: in.FreeMinutes,
+		Capacity:    in.Capacity,
+		SurgeRules:  in.SurgeRules,
+		MaxSurge:    in.MaxSurge,
+
+		ReservationCapacity: in.ReservationCapacity,
+		ReservationSlots:    in.ReservationSlots,
+		CancellationRules:   in.CancellationRules,
+		Taxes:               in.Taxes,
+		CreatedAt:           time.Now().Unix(),
+	}
+
+	err = config.Config.Lots.Put(lot)
+	return lot, err
+}
+
+// UpdateLot replaces the name, address, price limits, grace periods, capacity, surge
+// rules, reservation rules and taxes of the lot with the given uuid
+func UpdateLot(uuid string, in *types.LotInput) (types.Lot, error) {
+	var (
+		err error
+		lot types.Lot
+	)
+
+	if lot, err = GetLot(uuid); err != nil {
+		return lot, err
+	}
+
+	if err = validateLotInput(in); err != nil {
+		return lot, err
+	}
+
+	lot.Name = in.Name
+	lot.Address = in.Address
+	lot.DailyMax = in.DailyMax
+	lot.MinCharge = in.MinCharge
+	lot.EntryGrace = in.EntryGrace
+	lot.ExitGrace = in.ExitGrace
+	lot.FreeMinutes = in.FreeMinutes
+	lot.Capacity = in.Capacity
+	lot.SurgeRules = in.SurgeRules
+	lot.MaxSurge = in.MaxSurge
+	lot.ReservationCapacity = in.ReservationCapacity
+	lot.ReservationSlots = in.ReservationSlots
+	lot.CancellationRules = in.CancellationRules
+	lot.Taxes = in.Taxes
+
+	err = config.Config.Lots.Put(lot)
+	return lot, err
+}
+
+// ReportOccupancy stores the number of spaces occupied in the lot with the given uuid,
+// replacing the occupancy reported before
+func ReportOccupancy(uuid string, in *types.ReportOccupancyInput) (types.Occupancy, error) {
+	var (
+		err       error
+		lot       types.Lot
+		occupancy types.Occupancy
+	)
+
+	if lot, err = GetLot(uuid); err != nil {
+		return occupancy, err
+	}
+
+	if err = validateReportOccupancyInput(in, lot); err != nil {
+		return occupancy, err
+	}
+
+	occupancy = types.Occupancy{
+		LotID:      lot.UUID,
+		Occupied:   *in.Occupied,
+		Capacity:   lot.Capacity,
+		Percent:    *in.Occupied * 100 / lot.Capacity,
+		ReportedAt: time.Now().Unix(),
+	}
+
+	err = config.Config.Occupancies.Put(occupancy)
+	return occupancy, err
+}
+
+// GetOccupancy gets the most recently reported occupancy of the lot with the given uuid from the DB
+func GetOccupancy(uuid string) (types.Occupancy, error) {
+	return config.Config.Occupancies.Get(uuid)
+}
+
+// DeleteLot removes the lot with the given uuid from the DB and returns it.
+// A lot cannot be deleted while it has rates, products, promos, active sessions, booked
+// reservations, permits or merchants. Its reported occupancy is removed with it
+func DeleteLot(uuid string) (types.Lot, error) {
+	var (
+		err          error
+		lot          types.Lot
+		rates        []types.Rate
+		products     []types.Product
+		promos       []types.Promo
+		sessions     []types.Session
+		reservations []types.Reservation
+		permits      []types.Permit
+		merchants    []types.Merchant
+	)
+
+	if lot, err = GetLot(uuid); err != nil {
+		return lot, err
+	}
+
+	if rates, err = GetRates(); err != nil {
+		return lot, err
+	}
+
+	if lotRates := ratesForLot(rates, uuid); len(lotRates) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d rates", uuid, len(lotRates))
+	}
+
+	if products, err = GetProducts(); err != nil {
+		return lot, err
+	}
+
+	if lotProducts := productsForLot(products, uuid); len(lotProducts) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d products", uuid, len(lotProducts))
+	}
+
+	if promos, err = GetPromos(); err != nil {
+		return lot, err
+	}
+
+	if lotPromos := promosForLot(promos, uuid); len(lotPromos) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d promos", uuid, len(lotPromos))
+	}
+
+	if sessions, err = GetSessions(); err != nil {
+		return lot, err
+	}
+
+	if active := activeSessionsForLot(sessions, uuid); len(active) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d active sessions", uuid, len(active))
+	}
+
+	if reservations, err = GetReservations(); err != nil {
+		return lot, err
+	}
+
+	if booked := bookedReservationsForLot(reservations, uuid); len(booked) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d booked reservations", uuid, len(booked))
+	}
+
+	if permits, err = GetPermits(); err != nil {
+		return lot, err
+	}
+
+	if lotPermits := permitsForLot(permits, uuid); len(lotPermits) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d permits", uuid, len(lotPermits))
+	}
+
+	if merchants, err = GetMerchants(); err != nil {
+		return lot, err
+	}
+
+	if lotMerchants := merchantsForLot(merchants, uuid); len(lotMerchants) > 0 {
+		return lot, fmt.Errorf("lot %s still has %d merchants", uuid, len(lotMerchants))
+	}
+
+	if err = config.Config.Lots.Delete(uuid); err != nil {
+		return lot, err
+	}
+
+	err = config.Config.Occupancies.Delete(uuid)
+	return lot, err
+}
+
+// getSurge returns the surge that the surge rules of lot set for its reported occupancy:
+// the increase of the rule with the highest occupancy that the lot is above, lowered to
+// the lot's MaxSurge if it is higher. It returns false when no rule applies
+func getSurge(lot types.Lot, occupancy types.Occupancy) (types.QuotedSurge, bool) {
+	surge := types.QuotedSurge{Occupancy: occupancy.Percent}
+	for _, rule := range lot.SurgeRules {
+		if occupancy.Percent > rule.Above {
+			surge.Increase = rule.Increase
+		}
+	}
+	if surge.Increase == 0 {
+		return surge, false
+	}
+
+	if lot.MaxSurge > 0 && surge.Increase > lot.MaxSurge {
+		surge.Increase, surge.Capped = lot.MaxSurge, true
+	}
+	surge.Multiplier = float64(100+surge.Increase) / 100
+	return surge, true
+}
+
+// surgeRates returns rates with their prices and the prices of their tiers raised by
+// increase percent, rounded to the nearest cent
+func surgeRates(rates []types.Rate, increase int) []types.Rate {
+	raise := func(price int) int {
+		return (price*(100+increase) + 50) / 100
+	}
+
+	surged := make([]types.Rate, len(rates))
+	for i, rate := range rates {
+		rate.Price = raise(rate.Price)
+		if len(rate.Tiers) > 0 {
+			tiers := make([]types.PriceTier, len(rate.Tiers))
+			for j, tier := range rate.Tiers {
+				tier.Price = raise(tier.Price)
+				tiers[j] = tier
+			}
+			rate.Tiers = tiers
+		}
+		surged[i] = rate
+	}
+	return surged
+}
@@ -0,0 +1,308 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func Test_CreateLot(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.LotInput
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Create",
+			in:   types.LotInput{Name: "Main Street Garage", Address: "100 Main St"},
+		},
+		{
+			name: "Surge Rules Passing Create",
+			in:   types.LotInput{Name: "Main Street Garage", Capacity: 200, SurgeRules: []types.SurgeRule{{Above: 80, Increase: 25}, {Above: 95, Increase: 50}}, MaxSurge: 40},
+		},
+		{
+			name:    "Missing Name Error",
+			in:      types.LotInput{Name: " ", Address: "100 Main St"},
+			wantErr: true,
+		},
+		{
+			name:    "Surge Rules Without Capacity Error",
+			in:      types.LotInput{Name: "Main Street Garage", SurgeRules: []types.SurgeRule{{Above: 80, Increase: 25}}},
+			wantErr: true,
+		},
+		{
+			name:    "Surge Rules Out Of Order Error",
+			in:      types.LotInput{Name: "Main Street Garage", Capacity: 200, SurgeRules: []types.SurgeRule{{Above: 95, Increase: 50}, {Above: 80, Increase: 25}}},
+			wantErr: true,
+		},
+		{
+			name:    "Surge Rule Without Increase Error",
+			in:      types.LotInput{Name: "Main Street Garage", Capacity: 200, SurgeRules: []types.SurgeRule{{Above: 80}}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot, err := CreateLot(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreateLot() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			lots, _ := GetLots()
+			if !test.wantErr {
+				if len(lots) != 1 || !reflect.DeepEqual(lots[0], lot) {
+					t.Errorf("CreateLot() stored = %v, want %v", lots, lot)
+				}
+			} else if len(lots) != 0 {
+				t.Errorf("CreateLot() stored %d lots, want 0", len(lots))
+			}
+		})
+	}
+}
+
+func Test_ReportOccupancy(t *testing.T) {
+	tests := []struct {
+		name        string
+		capacity    int
+		occupied    *int
+		wantPercent int
+		wantErr     bool
+	}{
+		{
+			name:        "Simple Passing Report",
+			capacity:    200,
+			occupied:    intPtr(170),
+			wantPercent: 85,
+		},
+		{
+			name:        "Over Capacity Passing Report",
+			capacity:    200,
+			occupied:    intPtr(210),
+			wantPercent: 105,
+		},
+		{
+			name:     "Missing Occupied Error",
+			capacity: 200,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative Occupied Error",
+			capacity: 200,
+			occupied: intPtr(-1),
+			wantErr:  true,
+		},
+		{
+			name:     "Missing Capacity Error",
+			occupied: intPtr(170),
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot, err := CreateLot(&types.LotInput{Name: "Main Street Garage", Capacity: test.capacity})
+			if err != nil {
+				t.Fatalf("CreateLot() setup error = %v", err)
+			}
+
+			got, err := ReportOccupancy(lot.UUID, &types.ReportOccupancyInput{Occupied: test.occupied})
+			if (err != nil) != test.wantErr {
+				t.Errorf("ReportOccupancy() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := GetOccupancy(lot.UUID); !test.wantErr && (got.Percent != test.wantPercent || stored != got) {
+				t.Errorf("ReportOccupancy() = %v, stored %v, want percent %d", got, stored, test.wantPercent)
+			}
+		})
+	}
+}
+
+func Test_DeleteLot(t *testing.T) {
+	tests := []struct {
+		name         string
+		rates        []types.CreateRateInput
+		products     []types.ProductInput
+		promos       []types.PromoInput
+		sessions     []types.StartSessionInput
+		reservations []types.Reservation
+		permits      []types.PermitInput
+		merchants    []types.MerchantInput
+		wantErr      bool
+	}{
+		{
+			name: "Simple Passing Delete",
+		},
+		{
+			name:    "Lot In Use Error",
+			rates:   []types.CreateRateInput{{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}},
+			wantErr: true,
+		},
+		{
+			name:     "Lot Has Product Error",
+			products: []types.ProductInput{{Name: "Early Bird", Days: "fri", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200}},
+			wantErr:  true,
+		},
+		{
+			name:    "Lot Has Promo Error",
+			promos:  []types.PromoInput{{Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago"}},
+			wantErr: true,
+		},
+		{
+			name:     "Lot Has Active Session Error",
+			sessions: []types.StartSessionInput{{Plate: "ABC123", EntryTime: "2017-01-06T17:00:00-06:00"}},
+			wantErr:  true,
+		},
+		{
+			name:         "Lot Has Reservation Error",
+			reservations: []types.Reservation{{UUID: "booked", Plate: "ABC123", State: "booked"}},
+			wantErr:      true,
+		},
+		{
+			name:         "Cancelled Reservation Passing Delete",
+			reservations: []types.Reservation{{UUID: "cancelled", Plate: "ABC123", State: "cancelled"}},
+		},
+		{
+			name:    "Lot Has Permit Error",
+			permits: []types.PermitInput{{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"}},
+			wantErr: true,
+		},
+		{
+			name:      "Lot Has Merchant Error",
+			merchants: []types.MerchantInput{{Name: "Cafe", TZ: "America/Chicago"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot, err := CreateLot(&types.LotInput{Name: "Main Street Garage"})
+			if err != nil {
+				t.Fatalf("CreateLot() setup error = %v", err)
+			}
+			for _, rate := range test.rates {
+				rate.LotID = lot.UUID
+				if _, err := CreateRate(&rate, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+			for _, product := range test.products {
+				product.LotID = lot.UUID
+				if _, err := CreateProduct(&product); err != nil {
+					t.Fatalf("CreateProduct() setup error = %v", err)
+				}
+			}
+			for _, promo := range test.promos {
+				promo.LotID = lot.UUID
+				if _, err := CreatePromo(&promo); err != nil {
+					t.Fatalf("CreatePromo() setup error = %v", err)
+				}
+			}
+			for _, session := range test.sessions {
+				session.LotID = lot.UUID
+				if _, err := StartSession(&session); err != nil {
+					t.Fatalf("StartSession() setup error = %v", err)
+				}
+			}
+			for _, reservation := range test.reservations {
+				reservation.LotID = lot.UUID
+				if err := config.Config.Reservations.Write(reservation, 0, nil, nil); err != nil {
+					t.Fatalf("Reservations.Write() setup error = %v", err)
+				}
+			}
+			for _, permit := range test.permits {
+				permit.LotIDs = []string{lot.UUID}
+				if _, err := CreatePermit(&permit); err != nil {
+					t.Fatalf("CreatePermit() setup error = %v", err)
+				}
+			}
+			for _, merchant := range test.merchants {
+				merchant.LotIDs = []string{lot.UUID}
+				if _, err := CreateMerchant(&merchant); err != nil {
+					t.Fatalf("CreateMerchant() setup error = %v", err)
+				}
+			}
+
+			if _, err := DeleteLot(lot.UUID); (err != nil) != test.wantErr {
+				t.Errorf("DeleteLot() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if _, err := GetLot(lot.UUID); (err == nil) != test.wantErr {
+				t.Errorf("DeleteLot() lot exists = %v, want %v", err == nil, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_getSurge(t *testing.T) {
+	rules := []types.SurgeRule{{Above: 80, Increase: 25}, {Above: 95, Increase: 50}}
+	tests := []struct {
+		name      string
+		lot       types.Lot
+		percent   int
+		want      types.QuotedSurge
+		wantSurge bool
+	}{
+		{
+			name:    "Below Every Rule",
+			lot:     types.Lot{SurgeRules: rules},
+			percent: 80,
+			want:    types.QuotedSurge{Occupancy: 80},
+		},
+		{
+			name:      "Above First Rule",
+			lot:       types.Lot{SurgeRules: rules},
+			percent:   85,
+			want:      types.QuotedSurge{Occupancy: 85, Increase: 25, Multiplier: 1.25},
+			wantSurge: true,
+		},
+		{
+			name:      "Above Every Rule",
+			lot:       types.Lot{SurgeRules: rules},
+			percent:   100,
+			want:      types.QuotedSurge{Occupancy: 100, Increase: 50, Multiplier: 1.5},
+			wantSurge: true,
+		},
+		{
+			name:      "Capped By Max Surge",
+			lot:       types.Lot{SurgeRules: rules, MaxSurge: 40},
+			percent:   100,
+			want:      types.QuotedSurge{Occupancy: 100, Increase: 40, Multiplier: 1.4, Capped: true},
+			wantSurge: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, surge := getSurge(test.lot, types.Occupancy{Percent: test.percent})
+			if surge != test.wantSurge || got != test.want {
+				t.Errorf("getSurge() = %v, %v, want %v, %v", got, surge, test.want, test.wantSurge)
+			}
+		})
+	}
+}
+
+func Test_surgeRates(t *testing.T) {
+	rates := []types.Rate{
+		{UUID: "a", Price: 1000},
+		{UUID: "b", Price: 333, Tiers: []types.PriceTier{{From: 0, Until: 60, Price: 400}}},
+	}
+	want := []types.Rate{
+		{UUID: "a", Price: 1250},
+		{UUID: "b", Price: 416, Tiers: []types.PriceTier{{From: 0, Until: 60, Price: 500}}},
+	}
+
+	if got := surgeRates(rates, 25); !reflect.DeepEqual(got, want) {
+		t.Errorf("surgeRates() = %v, want %v", got, want)
+	}
+	if rates[1].Tiers[0].Price != 400 {
+		t.Errorf("surgeRates() changed the given tiers to %v", rates[1].Tiers)
+	}
+}
@@ -0,0 +1,552 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// validation types and states, and the type and source of the adjustments that validations make
+const (
+	validationMinutes  = "minutes"
+	validationAmount   = "amount"
+	validationIssued   = "issued"
+	validationRedeemed = "redeemed"
+	validationApplied  = "applied"
+	adjustValidation   = "validation"
+	sourceMerchant     = "merchant"
+)
+
+// validationCodeChars are the characters validation codes are made of, leaving out the
+// ones that are easily mistaken for each other. There are 32 so that each byte of a
+// random uuid picks one evenly
+const validationCodeChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// validationCodeLength is the number of characters in a validation code
+const validationCodeLength = 8
+
+// statementMonthLayout is the format of the months that merchants are billed for
+const statementMonthLayout = "2006-01"
+
+// GetMerchants gets all of the merchants from the DB
+func GetMerchants() ([]types.Merchant, error) {
+	return config.Config.Merchants.All()
+}
+
+// GetMerchant gets the merchant with the given uuid from the DB
+func GetMerchant(uuid string) (types.Merchant, error) {
+	return config.Config.Merchants.Get(uuid)
+}
+
+// CreateMerchant creates a merchant in the DB
+func CreateMerchant(in *types.MerchantInput) (types.Merchant, error) {
+	var (
+		err      error
+		merchant types.Merchant
+	)
+
+	if err = validateMerchantInput(in); err != nil {
+		return merchant, err
+	}
+
+	uu, _ := uuid.NewV4()
+	merchant = getMerchant(in)
+	merchant.UUID = uu.String()
+	merchant.CreatedAt = time.Now().Unix()
+
+	err = config.Config.Merchants.Put(merchant)
+	return merchant, err
+}
+
+// UpdateMerchant replaces every field of the merchant with the given uuid
+func UpdateMerchant(uuid string, in *types.MerchantInput) (types.Merchant, error) {
+	var (
+		err      error
+		merchant types.Merchant
+	)
+
+	if merchant, err = GetMerchant(uuid); err != nil {
+		return merchant, err
+	}
+
+	if err = validateMerchantInput(in); err != nil {
+		return merchant, err
+	}
+
+	updated := getMerchant(in)
+	updated.UUID = merchant.UUID
+	updated.CreatedAt = merchant.CreatedAt
+
+	err = config.Config.Merchants.Put(updated)
+	return updated, err
+}
+
+// DeleteMerchant removes the merchant with the given uuid from the DB and returns it. A
+// merchant cannot be deleted while it has validations that are issued or redeemed, since
+// the sessions that redeem them are billed to it when they are ended
+func DeleteMerchant(uuid string) (types.Merchant, error) {
+	var (
+		err         error
+		merchant    types.Merchant
+		validations []types.Validation
+	)
+
+	if merchant, err = GetMerchant(uuid); err != nil {
+		return merchant, err
+	}
+
+	if validations, err = config.Config.Validations.All(); err != nil {
+		return merchant, err
+	}
+
+	if outstanding := getOutstandingValidations(validations, uuid); len(outstanding) > 0 {
+		return merchant, fmt.Errorf("merchant %s still has %d validations that are issued or redeemed", uuid, len(outstanding))
+	}
+
+	err = config.Config.Merchants.Delete(uuid)
+	return merchant, err
+}
+
+// IssueValidations gives the merchant with the given uuid new validation codes
+func IssueValidations(uuid string, in *types.IssueValidationsInput) ([]types.Validation, error) {
+	var (
+		err         error
+		merchant    types.Merchant
+		validations []types.Validation
+	)
+
+	if merchant, err = GetMerchant(uuid); err != nil {
+		return validations, err
+	}
+
+	if err = validateIssueValidationsInput(in); err != nil {
+		return validations, err
+	}
+
+	count := in.Count
+	if count == 0 {
+		count = 1
+	}
+
+	for len(validations) < count {
+		validation := types.Validation{
+			Code:       newValidationCode(),
+			MerchantID: merchant.UUID,
+			Type:       in.Type,
+			Value:      in.Value,
+			ValidUntil: in.ValidUntil,
+			State:      validationIssued,
+			CreatedAt:  time.Now().Unix(),
+		}
+		// a code that is already taken is drawn again
+		if err = config.Config.Validations.Create(validation); errors.Is(err, store.ErrConflict) {
+			continue
+		} else if err != nil {
+			return validations, err
+		}
+		validations = append(validations, validation)
+	}
+	return validations, nil
+}
+
+// GetMerchantValidations gets all of the validations issued to the merchant with the given uuid
+func GetMerchantValidations(uuid string) ([]types.Validation, error) {
+	var (
+		err         error
+		validations []types.Validation
+	)
+
+	if _, err = GetMerchant(uuid); err != nil {
+		return nil, err
+	}
+
+	if validations, err = config.Config.Validations.All(); err != nil {
+		return nil, err
+	}
+
+	issued := []types.Validation{}
+	for _, validation := range validations {
+		if validation.MerchantID == uuid {
+			issued = append(issued, validation)
+		}
+	}
+	return issued, nil
+}
+
+// GetMerchantStatement bills the merchant with the given uuid for what its validations
+// were worth in month ("YYYY-MM"), which defaults to the current month in the merchant's timezone
+func GetMerchantStatement(uuid, month string) (types.MerchantStatement, error) {
+	var (
+		err         error
+		statement   types.MerchantStatement
+		merchant    types.Merchant
+		validations []types.Validation
+	)
+
+	if merchant, err = GetMerchant(uuid); err != nil {
+		return statement, err
+	}
+
+	if month == "" {
+		loc, _ := time.LoadLocation(merchant.TZ)
+		month = time.Now().In(loc).Format(statementMonthLayout)
+	} else if _, err = time.Parse(statementMonthLayout, month); err != nil {
+		return statement, fmt.Errorf("month must be in the format YYYY-MM: %s", month)
+	}
+
+	if validations, err = config.Config.Validations.All(); err != nil {
+		return statement, err
+	}
+
+	return buildStatement(merchant, month, validations), nil
+}
+
+// RedeemValidation redeems the validation with the code in in for the active session with
+// the given uuid. It takes its value off the session's fee when the session is ended
+func RedeemValidation(uuid string, in *types.RedeemValidationInput) (types.Validation, error) {
+	var (
+		err        error
+		session    types.Session
+		validation types.Validation
+		merchant   types.Merchant
+	)
+
+	if session, err = GetSession(uuid); err != nil {
+		return validation, err
+	}
+
+	code := normalizeValidationCode(in.Code)
+	if code == "" {
+		return validation, errors.New("specify a validation code")
+	}
+
+	if validation, err = config.Config.Validations.Get(code); err != nil {
+		return validation, fmt.Errorf("could not find validation %s: %w", code, err)
+	}
+
+	if merchant, err = GetMerchant(validation.MerchantID); err != nil {
+		return validation, fmt.Errorf("could not find merchant %s: %v", validation.MerchantID, err)
+	}
+
+	now := time.Now()
+	if err = validateRedemption(validation, merchant, session, now); err != nil {
+		return validation, err
+	}
+
+	redeemed := validation
+	redeemed.State = validationRedeemed
+	redeemed.SessionID = session.UUID
+	redeemed.RedeemedAt = now.Unix()
+
+	if err = config.Config.Validations.Transition(redeemed, validationIssued); err != nil {
+		return validation, err
+	}
+	return redeemed, nil
+}
+
+// getValidatedQuote takes the validations that session has redeemed off quote, the price of
+// its stay until exitTime, in the order they were redeemed. Each is worth its amount, or
+// the price of that many minutes from the start of the stay, no more than is left to pay
+// and no more than is left of its merchant's budget for the month the stay ends in. The
+// validations are returned applied with what they were worth, ready to be stored
+func getValidatedQuote(session types.Session, exitTime string, quote types.Quote) (types.Quote, []types.Validation, error) {
+	var (
+		err         error
+		validations []types.Validation
+		applied     []types.Validation
+	)
+
+	if validations, err = config.Config.Validations.All(); err != nil {
+		return quote, nil, err
+	}
+
+	endTime, _ := time.Parse(time.RFC3339, exitTime)
+	spent := make(map[string]int)
+	for _, validation := range getSessionValidations(validations, session.UUID) {
+		var (
+			merchant types.Merchant
+			worth    int
+		)
+
+		if merchant, err = GetMerchant(validation.MerchantID); err != nil {
+			return quote, nil, fmt.Errorf("could not find merchant %s: %v", validation.MerchantID, err)
+		}
+
+		loc, _ := time.LoadLocation(merchant.TZ)
+		month := endTime.In(loc).Format(statementMonthLayout)
+		key := merchant.UUID + "#" + month
+		if _, counted := spent[key]; !counted {
+			if spent[key], err = config.Config.Validations.Spent(merchant.UUID, month); err != nil {
+				return quote, nil, err
+			}
+		}
+
+		if worth, err = getValidationWorth(validation, session, exitTime); err != nil {
+			return quote, nil, err
+		}
+		if worth > quote.Total {
+			worth = quote.Total
+		}
+		capped := capToBudget(worth, merchant.Budget, spent[key])
+
+		quote = applyValidation(quote, validation, capped)
+		if capped < worth {
+			last := len(quote.Validations) - 1
+			quote.Validations[last].Rejected = fmt.Sprintf("merchant %s has used up its budget for %s", merchant.Name, month)
+		}
+		spent[key] += capped
+
+		validation.State = validationApplied
+		validation.Worth = capped
+		validation.Month = month
+		validation.AppliedAt = time.Now().Unix()
+		applied = append(applied, validation)
+	}
+	return quote, applied, nil
+}
+
+// getValidationWorth returns what validation is worth to session, before it is limited to
+// what is left to pay. A minutes validation is worth the price of that many minutes from
+// the start of the stay until exitTime
+func getValidationWorth(validation types.Validation, session types.Session, exitTime string) (int, error) {
+	if validation.Type == validationAmount {
+		return validation.Value, nil
+	}
+
+	entryTime, _ := time.Parse(time.RFC3339, session.EntryTime)
+	endTime, _ := time.Parse(time.RFC3339, exitTime)
+	if validated := entryTime.Add(time.Duration(validation.Value) * time.Minute); validated.Before(endTime) {
+		endTime = validated
+	}
+	end := endTime.Format(time.RFC3339)
+
+	quote, err := GetTimespanPrice(&types.GetTimespanPriceInput{
+		Start:        &session.EntryTime,
+		End:          &end,
+		LotID:        session.LotID,
+		VehicleClass: session.VehicleClass,
+		Plate:        session.Plate,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not price validation %s: %v", validation.Code, err)
+	}
+	return quote.Total, nil
+}
+
+// applyValidations stores the validations that a session is being closed with as applied,
+// billing what they were worth to their merchants, and returns them as they were billed. A
+// validation whose merchant's budget was spent by another session since it was quoted is
+// billed for what is left of the budget. When one cannot be billed, the validations billed
+// before it are returned with the error so that they can be given back
+func applyValidations(validations []types.Validation) ([]types.Validation, error) {
+	var billed []types.Validation
+	for _, validation := range validations {
+		applied, err := billValidation(validation)
+		if err != nil {
+			return billed, fmt.Errorf("could not apply validation %s: %w", validation.Code, err)
+		}
+		billed = append(billed, applied)
+	}
+	return billed, nil
+}
+
+// billValidation stores validation as applied and bills it to its merchant, lowering
+// what it is worth to what is left of the merchant's budget until it fits
+func billValidation(validation types.Validation) (types.Validation, error) {
+	merchant, err := GetMerchant(validation.MerchantID)
+	if err != nil {
+		return validation, fmt.Errorf("could not find merchant %s: %w", validation.MerchantID, err)
+	}
+
+	for {
+		err = config.Config.Validations.Apply(validation, validationRedeemed, merchant.Budget)
+		if !errors.Is(err, store.ErrLimitReached) {
+			return validation, err
+		}
+
+		var spent int
+		if spent, err = config.Config.Validations.Spent(merchant.UUID, validation.Month); err != nil {
+			return validation, err
+		}
+		worth := capToBudget(validation.Worth, merchant.Budget, spent)
+		if worth == validation.Worth {
+			return validation, fmt.Errorf("could not fit validation %s in the budget of merchant %s: %w", validation.Code, merchant.UUID, store.ErrLimitReached)
+		}
+		validation.Worth = worth
+	}
+}
+
+// unapplyValidations gives back the validations that applyValidations billed for a
+// session that could not be closed, so that they are redeemed again and no longer billed
+func unapplyValidations(billed []types.Validation) error {
+	var failed []string
+	for _, applied := range billed {
+		redeemed := applied
+		redeemed.State = validationRedeemed
+		redeemed.Worth = 0
+		redeemed.Month = ""
+		redeemed.AppliedAt = 0
+		if err := config.Config.Validations.Unapply(redeemed, applied); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", applied.Code, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not give back validations %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// getBilledQuote takes the validations off quote, the price of a stay before them, for what
+// they were billed, which is less than validated took off for them when their merchant's
+// budget was spent by another session since the stay was validated
+func getBilledQuote(quote, validated types.Quote, billed []types.Validation) types.Quote {
+	for i, validation := range billed {
+		quote = applyValidation(quote, validation, validation.Worth)
+		quote.Validations[i].Rejected = validated.Validations[i].Rejected
+		if quote.Validations[i].Discount < validated.Validations[i].Discount {
+			quote.Validations[i].Rejected = fmt.Sprintf("merchant %s used up its budget for %s while the session was ended", validation.MerchantID, validation.Month)
+		}
+	}
+	return quote
+}
+
+// getMerchant returns the merchant that in describes, without a uuid
+func getMerchant(in *types.MerchantInput) types.Merchant {
+	return types.Merchant{
+		Name:   strings.TrimSpace(in.Name),
+		LotIDs: in.LotIDs,
+		Budget: in.Budget,
+		TZ:     in.TZ,
+	}
+}
+
+// newValidationCode returns a random validation code
+func newValidationCode() string {
+	uu, _ := uuid.NewV4()
+	code := make([]byte, validationCodeLength)
+	for i := range code {
+		code[i] = validationCodeChars[int(uu[i])%len(validationCodeChars)]
+	}
+	return string(code)
+}
+
+// normalizeValidationCode returns code in upper case without spaces or dashes, so that
+// a code matches however it was entered
+func normalizeValidationCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// getSessionValidations returns the validations that the session with the given
+// sessionID has redeemed and that have not been applied, in the order they were redeemed
+func getSessionValidations(validations []types.Validation, sessionID string) []types.Validation {
+	var redeemed []types.Validation
+	for _, validation := range validations {
+		if validation.State == validationRedeemed && validation.SessionID == sessionID {
+			redeemed = append(redeemed, validation)
+		}
+	}
+	sort.SliceStable(redeemed, func(i, j int) bool {
+		if redeemed[i].RedeemedAt != redeemed[j].RedeemedAt {
+			return redeemed[i].RedeemedAt < redeemed[j].RedeemedAt
+		}
+		return redeemed[i].Code < redeemed[j].Code
+	})
+	return redeemed
+}
+
+// merchantsForLot returns the merchants whose validations are good in the lot with the given lotID
+func merchantsForLot(merchants []types.Merchant, lotID string) []types.Merchant {
+	var lotMerchants []types.Merchant
+	for _, merchant := range merchants {
+		if containsString(merchant.LotIDs, lotID) {
+			lotMerchants = append(lotMerchants, merchant)
+		}
+	}
+	return lotMerchants
+}
+
+// getOutstandingValidations returns the validations of the merchant with the given
+// merchantID that are still issued or redeemed, which a session may yet be ended with
+func getOutstandingValidations(validations []types.Validation, merchantID string) []types.Validation {
+	var outstanding []types.Validation
+	for _, validation := range validations {
+		if validation.MerchantID == merchantID && (validation.State == validationIssued || validation.State == validationRedeemed) {
+			outstanding = append(outstanding, validation)
+		}
+	}
+	return outstanding
+}
+
+// applyValidation takes discount off the days of quote in order, as what validation was
+// worth, and lists it in the quote's validations
+func applyValidation(quote types.Quote, validation types.Validation, discount int) types.Quote {
+	quoted := types.QuotedValidation{Code: validation.Code, MerchantID: validation.MerchantID}
+
+	quote.Days = append([]types.DaySubtotal(nil), quote.Days...)
+	for i, day := range quote.Days {
+		take := discount - quoted.Discount
+		if take > day.Price {
+			take = day.Price
+		}
+		if take == 0 {
+			continue
+		}
+
+		quote.Days[i].Price -= take
+		quote.Total -= take
+		quoted.Discount += take
+		quote.Adjustments = append(quote.Adjustments, types.PriceAdjustment{Type: adjustValidation, Source: sourceMerchant, Code: validation.Code, Date: day.Date, Amount: -take})
+	}
+
+	quote.Validations = append(quote.Validations, quoted)
+	return quote
+}
+
+// capToBudget returns worth lowered to what is left of budget once spent has been spent
+// from it, where a budget of 0 has no limit
+func capToBudget(worth, budget, spent int) int {
+	if budget == 0 {
+		return worth
+	}
+	if left := budget - spent; worth > left {
+		if left < 0 {
+			return 0
+		}
+		return left
+	}
+	return worth
+}
+
+// buildStatement bills merchant for what its validations that were applied in month were
+// worth, in the order they were applied
+func buildStatement(merchant types.Merchant, month string, validations []types.Validation) types.MerchantStatement {
+	statement := types.MerchantStatement{MerchantID: merchant.UUID, MerchantName: merchant.Name, Month: month, Lines: []types.StatementLine{}}
+
+	var billed []types.Validation
+	for _, validation := range validations {
+		if validation.MerchantID == merchant.UUID && validation.State == validationApplied && validation.Month == month && validation.Worth > 0 {
+			billed = append(billed, validation)
+		}
+	}
+	sort.SliceStable(billed, func(i, j int) bool { return billed[i].AppliedAt < billed[j].AppliedAt })
+
+	loc, _ := time.LoadLocation(merchant.TZ)
+	for _, validation := range billed {
+		statement.Lines = append(statement.Lines, types.StatementLine{
+			Code:      validation.Code,
+			SessionID: validation.SessionID,
+			AppliedAt: time.Unix(validation.AppliedAt, 0).In(loc).Format(time.RFC3339),
+			Amount:    validation.Worth,
+		})
+		statement.Total += validation.Worth
+	}
+	return statement
+}
@@ -0,0 +1,705 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+)
+
+// createMerchantSession creates a fri rate, a merchant with budget, and an active
+// session that has redeemed a validation of the given type and value
+func createMerchantSession(t *testing.T, budget int, validationType string, value int) (types.Merchant, types.Session) {
+	if _, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+		t.Fatalf("CreateRate() setup error = %v", err)
+	}
+	merchant, err := CreateMerchant(&types.MerchantInput{Name: "Cafe", Budget: budget, TZ: "America/Chicago"})
+	if err != nil {
+		t.Fatalf("CreateMerchant() setup error = %v", err)
+	}
+	session, err := StartSession(&types.StartSessionInput{Plate: "ABC123", EntryTime: "2017-01-06T16:00:00-06:00"})
+	if err != nil {
+		t.Fatalf("StartSession() setup error = %v", err)
+	}
+	validations, err := IssueValidations(merchant.UUID, &types.IssueValidationsInput{Type: validationType, Value: value})
+	if err != nil {
+		t.Fatalf("IssueValidations() setup error = %v", err)
+	}
+	if _, err = RedeemValidation(session.UUID, &types.RedeemValidationInput{Code: validations[0].Code}); err != nil {
+		t.Fatalf("RedeemValidation() setup error = %v", err)
+	}
+	return merchant, session
+}
+
+func Test_CreateMerchant(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.MerchantInput
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Create",
+			in:   types.MerchantInput{Name: "Cafe", TZ: "America/Chicago"},
+		},
+		{
+			name: "Budget Passing Create",
+			in:   types.MerchantInput{Name: "Cafe", Budget: 50000, TZ: "America/Chicago"},
+		},
+		{
+			name:    "Missing Name Error",
+			in:      types.MerchantInput{TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.MerchantInput{Name: "Cafe", LotIDs: []string{"missing"}, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Budget Error",
+			in:      types.MerchantInput{Name: "Cafe", Budget: -1, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Timezone Error",
+			in:      types.MerchantInput{Name: "Cafe"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, err := CreateMerchant(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreateMerchant() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			merchants, _ := GetMerchants()
+			if !test.wantErr {
+				if len(merchants) != 1 || merchants[0].UUID != merchant.UUID || merchants[0].Budget != test.in.Budget {
+					t.Errorf("CreateMerchant() stored = %v, want %v", merchants, merchant)
+				}
+			} else if len(merchants) != 0 {
+				t.Errorf("CreateMerchant() stored %d merchants, want 0", len(merchants))
+			}
+		})
+	}
+}
+
+func Test_DeleteMerchant(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Delete",
+		},
+		{
+			name:  "Applied Validation Passing Delete",
+			state: validationApplied,
+		},
+		{
+			name:    "Issued Validation Error",
+			state:   validationIssued,
+			wantErr: true,
+		},
+		{
+			name:    "Redeemed Validation Error",
+			state:   validationRedeemed,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, err := CreateMerchant(&types.MerchantInput{Name: "Cafe", TZ: "America/Chicago"})
+			if err != nil {
+				t.Fatalf("CreateMerchant() setup error = %v", err)
+			}
+			if test.state != "" {
+				if err = config.Config.Validations.Create(types.Validation{Code: "CODE", MerchantID: merchant.UUID, State: test.state}); err != nil {
+					t.Fatalf("Validations.Create() setup error = %v", err)
+				}
+			}
+
+			_, err = DeleteMerchant(merchant.UUID)
+			if (err != nil) != test.wantErr {
+				t.Errorf("DeleteMerchant() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if merchants, _ := GetMerchants(); (len(merchants) == 1) != test.wantErr {
+				t.Errorf("DeleteMerchant() left %d merchants, wantErr %v", len(merchants), test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_IssueValidations(t *testing.T) {
+	tests := []struct {
+		name      string
+		merchant  string
+		in        types.IssueValidationsInput
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:      "Minutes Passing Issue",
+			in:        types.IssueValidationsInput{Type: "minutes", Value: 120, Count: 3},
+			wantCount: 3,
+		},
+		{
+			name:      "Single Amount Passing Issue",
+			in:        types.IssueValidationsInput{Type: "amount", Value: 500, ValidUntil: "2017-02-01"},
+			wantCount: 1,
+		},
+		{
+			name:     "Missing Merchant Error",
+			merchant: "missing",
+			in:       types.IssueValidationsInput{Type: "minutes", Value: 120},
+			wantErr:  true,
+		},
+		{
+			name:    "Invalid Type Error",
+			in:      types.IssueValidationsInput{Type: "hours", Value: 2},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Value Error",
+			in:      types.IssueValidationsInput{Type: "amount"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Count Error",
+			in:      types.IssueValidationsInput{Type: "minutes", Value: 120, Count: -1},
+			wantErr: true,
+		},
+		{
+			name:    "Too Many Error",
+			in:      types.IssueValidationsInput{Type: "minutes", Value: 120, Count: maxValidationsPerIssue + 1},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Date Error",
+			in:      types.IssueValidationsInput{Type: "minutes", Value: 120, ValidUntil: "02/01/2017"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, err := CreateMerchant(&types.MerchantInput{Name: "Cafe", TZ: "America/Chicago"})
+			if err != nil {
+				t.Fatalf("CreateMerchant() setup error = %v", err)
+			}
+			if test.merchant == "" {
+				test.merchant = merchant.UUID
+			}
+
+			got, err := IssueValidations(test.merchant, &test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("IssueValidations() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			stored, _ := GetMerchantValidations(merchant.UUID)
+			if len(got) != test.wantCount || len(stored) != test.wantCount {
+				t.Errorf("IssueValidations() = %v, stored %v, want %d validations", got, stored, test.wantCount)
+			}
+			for _, validation := range got {
+				if len(validation.Code) != validationCodeLength || validation.State != validationIssued || validation.MerchantID != merchant.UUID {
+					t.Errorf("IssueValidations() issued %v, want an issued validation of merchant %s", validation, merchant.UUID)
+				}
+			}
+		})
+	}
+}
+
+func Test_RedeemValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		lotIDs     bool
+		validUntil string
+		redeemed   bool
+		closed     bool
+		code       string
+		wantErr    bool
+	}{
+		{
+			name: "Simple Passing Redeem",
+		},
+		{
+			name: "Entered Loosely Passing Redeem",
+			code: "loose",
+		},
+		{
+			name:       "Not Yet Expired Passing Redeem",
+			validUntil: "2999-01-01",
+		},
+		{
+			name:     "Already Redeemed Error",
+			redeemed: true,
+			wantErr:  true,
+		},
+		{
+			name:       "Expired Error",
+			validUntil: "2017-01-01",
+			wantErr:    true,
+		},
+		{
+			name:    "Other Lot Error",
+			lotIDs:  true,
+			wantErr: true,
+		},
+		{
+			name:    "Closed Session Error",
+			closed:  true,
+			wantErr: true,
+		},
+		{
+			name:    "Missing Code Error",
+			code:    "NOSUCHCODE",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if _, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+				t.Fatalf("CreateRate() setup error = %v", err)
+			}
+			in := types.MerchantInput{Name: "Cafe", TZ: "America/Chicago"}
+			if test.lotIDs {
+				lot, err := CreateLot(&types.LotInput{Name: "Garage"})
+				if err != nil {
+					t.Fatalf("CreateLot() setup error = %v", err)
+				}
+				in.LotIDs = []string{lot.UUID}
+			}
+			merchant, err := CreateMerchant(&in)
+			if err != nil {
+				t.Fatalf("CreateMerchant() setup error = %v", err)
+			}
+			validations, err := IssueValidations(merchant.UUID, &types.IssueValidationsInput{Type: "minutes", Value: 60, ValidUntil: test.validUntil})
+			if err != nil {
+				t.Fatalf("IssueValidations() setup error = %v", err)
+			}
+			session, err := StartSession(&types.StartSessionInput{Plate: "ABC123", EntryTime: "2017-01-06T16:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+			if test.redeemed {
+				other, err := StartSession(&types.StartSessionInput{Plate: "XYZ789", EntryTime: "2017-01-06T16:00:00-06:00"})
+				if err != nil {
+					t.Fatalf("StartSession() setup error = %v", err)
+				}
+				if _, err = RedeemValidation(other.UUID, &types.RedeemValidationInput{Code: validations[0].Code}); err != nil {
+					t.Fatalf("RedeemValidation() setup error = %v", err)
+				}
+			}
+			if test.closed {
+				if _, err = EndSession(session.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T17:00:00-06:00"}); err != nil {
+					t.Fatalf("EndSession() setup error = %v", err)
+				}
+			}
+
+			code := validations[0].Code
+			switch test.code {
+			case "":
+			case "loose":
+				code = " " + code[:4] + "-" + code[4:] + " "
+			default:
+				code = test.code
+			}
+
+			got, err := RedeemValidation(session.UUID, &types.RedeemValidationInput{Code: code})
+			if (err != nil) != test.wantErr {
+				t.Errorf("RedeemValidation() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.State != validationRedeemed || got.SessionID != session.UUID || got.RedeemedAt == 0) {
+				t.Errorf("RedeemValidation() = %v, want a validation redeemed by session %s", got, session.UUID)
+			}
+		})
+	}
+}
+
+func Test_getValidatedQuote(t *testing.T) {
+	tests := []struct {
+		name           string
+		budget         int
+		spent          int
+		validationType string
+		value          int
+		wantTotal      int
+		wantWorth      int
+		wantRejected   bool
+	}{
+		{
+			name:           "Minutes Validation",
+			validationType: "minutes",
+			value:          60,
+			wantTotal:      1800,
+			wantWorth:      1800,
+		},
+		{
+			name:           "Started Hour Minutes Validation",
+			validationType: "minutes",
+			value:          30,
+			wantTotal:      1800,
+			wantWorth:      1800,
+		},
+		{
+			name:           "Amount Validation",
+			validationType: "amount",
+			value:          500,
+			wantTotal:      3100,
+			wantWorth:      500,
+		},
+		{
+			name:           "More Than The Fee Validation",
+			validationType: "amount",
+			value:          5000,
+			wantTotal:      0,
+			wantWorth:      3600,
+		},
+		{
+			name:           "Over Budget Validation",
+			budget:         1000,
+			spent:          600,
+			validationType: "amount",
+			value:          500,
+			wantTotal:      3200,
+			wantWorth:      400,
+			wantRejected:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, session := createMerchantSession(t, test.budget, test.validationType, test.value)
+			if test.spent > 0 {
+				spent := types.Validation{Code: "SPENT", MerchantID: merchant.UUID, State: validationRedeemed}
+				if err := config.Config.Validations.Create(spent); err != nil {
+					t.Fatalf("Validations.Create() setup error = %v", err)
+				}
+				spent.State, spent.Worth, spent.Month = validationApplied, test.spent, "2017-01"
+				if _, err := applyValidations([]types.Validation{spent}); err != nil {
+					t.Fatalf("applyValidations() setup error = %v", err)
+				}
+			}
+			quote, err := GetTimespanPrice(&types.GetTimespanPriceInput{Start: strPtr("2017-01-06T16:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00")})
+			if err != nil {
+				t.Fatalf("GetTimespanPrice() setup error = %v", err)
+			}
+
+			got, applied, err := getValidatedQuote(session, "2017-01-06T18:00:00-06:00", quote)
+			if err != nil {
+				t.Errorf("getValidatedQuote() error = %v", err)
+				return
+			}
+
+			if got.Total != test.wantTotal || len(applied) != 1 || applied[0].Worth != test.wantWorth || applied[0].Month != "2017-01" || len(got.Validations) != 1 || got.Validations[0].Discount != test.wantWorth || (got.Validations[0].Rejected != "") != test.wantRejected {
+				t.Errorf("getValidatedQuote() = %+v, %v, want total %d and a validation worth %d", got, applied, test.wantTotal, test.wantWorth)
+			}
+		})
+	}
+}
+
+func Test_applyValidations(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    int
+		spent     int
+		worth     int
+		wantWorth int
+		wantSpent int
+	}{
+		{
+			name:      "Within Budget Validation",
+			budget:    1000,
+			worth:     500,
+			wantWorth: 500,
+			wantSpent: 500,
+		},
+		{
+			name:      "Budget Spent Since Quote Validation",
+			budget:    1000,
+			spent:     800,
+			worth:     500,
+			wantWorth: 200,
+			wantSpent: 1000,
+		},
+		{
+			name:      "Budget Used Up Since Quote Validation",
+			budget:    1000,
+			spent:     1000,
+			worth:     500,
+			wantWorth: 0,
+			wantSpent: 1000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, session := createMerchantSession(t, test.budget, "amount", test.worth)
+			if test.spent > 0 {
+				spent := types.Validation{Code: "SPENT", MerchantID: merchant.UUID, State: validationRedeemed}
+				if err := config.Config.Validations.Create(spent); err != nil {
+					t.Fatalf("Validations.Create() setup error = %v", err)
+				}
+				spent.State, spent.Worth, spent.Month = validationApplied, test.spent, "2017-01"
+				if _, err := applyValidations([]types.Validation{spent}); err != nil {
+					t.Fatalf("applyValidations() setup error = %v", err)
+				}
+			}
+			all, err := config.Config.Validations.All()
+			if err != nil {
+				t.Fatalf("Validations.All() setup error = %v", err)
+			}
+			redeemed := getSessionValidations(all, session.UUID)
+			if len(redeemed) != 1 {
+				t.Fatalf("getSessionValidations() setup = %v, want 1 validation", redeemed)
+			}
+			// quoted before the budget was spent
+			validation := redeemed[0]
+			validation.State, validation.Worth, validation.Month = validationApplied, test.worth, "2017-01"
+
+			billed, err := applyValidations([]types.Validation{validation})
+			if err != nil {
+				t.Errorf("applyValidations() error = %v", err)
+				return
+			}
+
+			stored, _ := config.Config.Validations.Get(validation.Code)
+			if spent, _ := config.Config.Validations.Spent(merchant.UUID, "2017-01"); len(billed) != 1 || billed[0].Worth != test.wantWorth || stored.State != validationApplied || stored.Worth != test.wantWorth || spent != test.wantSpent {
+				t.Errorf("applyValidations() stored %+v and spent %d, want a validation worth %d and %d spent", stored, spent, test.wantWorth, test.wantSpent)
+			}
+		})
+	}
+}
+
+func Test_getBilledQuote(t *testing.T) {
+	quote := types.Quote{Total: 1800, Days: []types.DaySubtotal{{Date: "2017-01-06", Price: 1800}}}
+	validation := types.Validation{Code: "CAFE", MerchantID: "cafe", Month: "2017-01"}
+	validated := applyValidation(quote, validation, 500)
+	tests := []struct {
+		name         string
+		worth        int
+		wantTotal    int
+		wantRejected bool
+	}{
+		{
+			name:      "Billed As Quoted",
+			worth:     500,
+			wantTotal: 1300,
+		},
+		{
+			name:         "Billed Less Than Quoted",
+			worth:        200,
+			wantTotal:    1600,
+			wantRejected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			billed := validation
+			billed.Worth = test.worth
+
+			got := getBilledQuote(quote, validated, []types.Validation{billed})
+			if got.Total != test.wantTotal || len(got.Validations) != 1 || got.Validations[0].Discount != test.worth || (got.Validations[0].Rejected != "") != test.wantRejected {
+				t.Errorf("getBilledQuote() = %+v, want total %d and a validation worth %d", got, test.wantTotal, test.worth)
+			}
+		})
+	}
+}
+
+func Test_GetMerchantStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		month     string
+		wantLines int
+		wantTotal int
+		wantErr   bool
+	}{
+		{
+			name:      "Simple Passing Statement",
+			month:     "2017-01",
+			wantLines: 1,
+			wantTotal: 1800,
+		},
+		{
+			name:  "Other Month Passing Statement",
+			month: "2017-02",
+		},
+		{
+			name:    "Invalid Month Error",
+			month:   "January",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			merchant, session := createMerchantSession(t, 0, "minutes", 60)
+			closed, err := EndSession(session.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("EndSession() setup error = %v", err)
+			}
+			if closed.Fee != 1800 {
+				t.Fatalf("EndSession() setup fee = %d, want 1800", closed.Fee)
+			}
+
+			got, err := GetMerchantStatement(merchant.UUID, test.month)
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetMerchantStatement() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (len(got.Lines) != test.wantLines || got.Total != test.wantTotal || got.Month != test.month) {
+				t.Errorf("GetMerchantStatement() = %+v, want %d lines for a total of %d", got, test.wantLines, test.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_applyValidation(t *testing.T) {
+	quote := types.Quote{Total: 2500, Days: []types.DaySubtotal{{Date: "2017-01-06", Price: 700}, {Date: "2017-01-07", Price: 1800}}}
+	validation := types.Validation{Code: "CAFE2HRS", MerchantID: "cafe"}
+	want := types.Quote{
+		Total: 1500,
+		Days:  []types.DaySubtotal{{Date: "2017-01-06", Price: 0}, {Date: "2017-01-07", Price: 1500}},
+		Adjustments: []types.PriceAdjustment{
+			{Type: adjustValidation, Source: sourceMerchant, Code: "CAFE2HRS", Date: "2017-01-06", Amount: -700},
+			{Type: adjustValidation, Source: sourceMerchant, Code: "CAFE2HRS", Date: "2017-01-07", Amount: -300},
+		},
+		Validations: []types.QuotedValidation{{Code: "CAFE2HRS", MerchantID: "cafe", Discount: 1000}},
+	}
+
+	if got := applyValidation(quote, validation, 1000); !reflect.DeepEqual(got, want) {
+		t.Errorf("applyValidation() = %+v, want %+v", got, want)
+	}
+	if quote.Days[0].Price != 700 {
+		t.Errorf("applyValidation() changed the days of the quote it was given: %v", quote.Days)
+	}
+}
+
+func Test_getOutstandingValidations(t *testing.T) {
+	validations := []types.Validation{
+		{Code: "ISSUED", MerchantID: "cafe", State: validationIssued},
+		{Code: "REDEEMED", MerchantID: "cafe", State: validationRedeemed},
+		{Code: "APPLIED", MerchantID: "cafe", State: validationApplied},
+		{Code: "OTHER", MerchantID: "deli", State: validationIssued},
+	}
+
+	tests := []struct {
+		name       string
+		merchantID string
+		want       []string
+	}{
+		{
+			name:       "Issued And Redeemed",
+			merchantID: "cafe",
+			want:       []string{"ISSUED", "REDEEMED"},
+		},
+		{
+			name:       "No Validations",
+			merchantID: "bakery",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, validation := range getOutstandingValidations(validations, test.merchantID) {
+				got = append(got, validation.Code)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getOutstandingValidations() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_capToBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		worth  int
+		budget int
+		spent  int
+		want   int
+	}{
+		{
+			name:  "No Budget",
+			worth: 1800,
+			spent: 100000,
+			want:  1800,
+		},
+		{
+			name:   "Within Budget",
+			worth:  1800,
+			budget: 5000,
+			spent:  3000,
+			want:   1800,
+		},
+		{
+			name:   "Rest Of Budget",
+			worth:  1800,
+			budget: 5000,
+			spent:  4000,
+			want:   1000,
+		},
+		{
+			name:   "Budget Used Up",
+			worth:  1800,
+			budget: 5000,
+			spent:  5000,
+			want:   0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := capToBudget(test.worth, test.budget, test.spent); got != test.want {
+				t.Errorf("capToBudget() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_buildStatement(t *testing.T) {
+	merchant := types.Merchant{UUID: "cafe", Name: "Cafe", TZ: "America/Chicago"}
+	validations := []types.Validation{
+		{Code: "LATER", MerchantID: "cafe", State: validationApplied, SessionID: "b", Worth: 500, Month: "2017-01", AppliedAt: 1483826400},
+		{Code: "EARLIER", MerchantID: "cafe", State: validationApplied, SessionID: "a", Worth: 1800, Month: "2017-01", AppliedAt: 1483740000},
+		{Code: "WORTHLESS", MerchantID: "cafe", State: validationApplied, SessionID: "a", Month: "2017-01", AppliedAt: 1483740000},
+		{Code: "FEBRUARY", MerchantID: "cafe", State: validationApplied, SessionID: "a", Worth: 700, Month: "2017-02", AppliedAt: 1486418400},
+		{Code: "REDEEMED", MerchantID: "cafe", State: validationRedeemed, SessionID: "d"},
+		{Code: "OTHER", MerchantID: "shop", State: validationApplied, SessionID: "a", Worth: 300, Month: "2017-01", AppliedAt: 1483740000},
+	}
+	want := types.MerchantStatement{
+		MerchantID:   "cafe",
+		MerchantName: "Cafe",
+		Month:        "2017-01",
+		Lines: []types.StatementLine{
+			{Code: "EARLIER", SessionID: "a", AppliedAt: "2017-01-06T16:00:00-06:00", Amount: 1800},
+			{Code: "LATER", SessionID: "b", AppliedAt: "2017-01-07T16:00:00-06:00", Amount: 500},
+		},
+		Total: 2300,
+	}
+
+	if got := buildStatement(merchant, "2017-01", validations); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildStatement() = %+v, want %+v", got, want)
+	}
+}
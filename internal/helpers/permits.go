@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// permitAllDay is the hours of a permit without times
+const permitAllDay = "0000-" + endOfDay
+
+// GetPermits gets all of the permits from the DB
+func GetPermits() ([]types.Permit, error) {
+	return config.Config.Permits.All()
+}
+
+// GetPermit gets the permit with the given uuid from the DB
+func GetPermit(uuid string) (types.Permit, error) {
+	return config.Config.Permits.Get(uuid)
+}
+
+// CreatePermit creates a permit in the DB
+func CreatePermit(in *types.PermitInput) (types.Permit, error) {
+	var (
+		err    error
+		permit types.Permit
+	)
+
+	if err = validatePermitInput(in); err != nil {
+		return permit, err
+	}
+
+	uu, _ := uuid.NewV4()
+	permit = getPermit(in)
+	permit.UUID = uu.String()
+	permit.CreatedAt = time.Now().Unix()
+
+	err = config.Config.Permits.Put(permit)
+	return permit, err
+}
+
+// UpdatePermit replaces every field of the permit with the given uuid
+func UpdatePermit(uuid string, in *types.PermitInput) (types.Permit, error) {
+	var (
+		err    error
+		permit types.Permit
+	)
+
+	if permit, err = GetPermit(uuid); err != nil {
+		return permit, err
+	}
+
+	if err = validatePermitInput(in); err != nil {
+		return permit, err
+	}
+
+	updated := getPermit(in)
+	updated.UUID = permit.UUID
+	updated.CreatedAt = permit.CreatedAt
+
+	err = config.Config.Permits.Put(updated)
+	return updated, err
+}
+
+// DeletePermit removes the permit with the given uuid from the DB and returns it
+func DeletePermit(uuid string) (types.Permit, error) {
+	var (
+		err    error
+		permit types.Permit
+	)
+
+	if permit, err = GetPermit(uuid); err != nil {
+		return permit, err
+	}
+
+	err = config.Config.Permits.Delete(uuid)
+	return permit, err
+}
+
+// getPermit returns the permit that in describes, without a uuid
+func getPermit(in *types.PermitInput) types.Permit {
+	plates := make([]string, 0, len(in.Plates))
+	for _, plate := range in.Plates {
+		plates = append(plates, normalizePlate(plate))
+	}
+
+	return types.Permit{
+		Holder:     strings.TrimSpace(in.Holder),
+		Plates:     plates,
+		LotIDs:     in.LotIDs,
+		Days:       in.Days,
+		Times:      in.Times,
+		TZ:         in.TZ,
+		ValidFrom:  in.ValidFrom,
+		ValidUntil: in.ValidUntil,
+	}
+}
+
+// getPermitWindows returns the parts of a stay from startTime to endTime that fall in the
+// hours of permit on the days it is valid, in order. Hours that wrap past midnight belong
+// to the day they start on, so the hours that start the day before the stay are considered
+func getPermitWindows(permit types.Permit, startTime, endTime time.Time) []timespan {
+	loc, err := time.LoadLocation(permit.TZ)
+	if err != nil {
+		log.Errorf("Could not load permit %s timezone %s: %v", permit.UUID, permit.TZ, err)
+		return nil
+	}
+
+	times := permit.Times
+	if times == "" {
+		times = permitAllDay
+	}
+	timesSlice, _ := timeSpanAsSlice(times)
+	earlier, later, err := getTimeObjectsFromTimes(timesSlice)
+	if err != nil {
+		log.Errorf("Could not parse permit %s times %s: %v", permit.UUID, times, err)
+		return nil
+	}
+
+	var windows []timespan
+	y, m, d := startTime.In(loc).Date()
+	for date := time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC); getLocalInstant(date, 0, 0, loc).Before(endTime); date = date.AddDate(0, 0, 1) {
+		day, _ := weekdayToDay(date.Weekday())
+		validOn := date.Format(effectiveDateLayout)
+		if !strings.Contains(permit.Days, day) || validOn < permit.ValidFrom || validOn >= permit.ValidUntil {
+			continue
+		}
+
+		from := getLocalInstant(date, earlier.Hour(), earlier.Minute(), loc)
+		until := getLocalInstant(date.AddDate(0, 0, later.Day()-1), later.Hour(), later.Minute(), loc)
+		if from.Before(startTime) {
+			from = startTime
+		}
+		if until.After(endTime) {
+			until = endTime
+		}
+		if from.Before(until) {
+			windows = append(windows, timespan{start: from, end: until})
+		}
+	}
+	return windows
+}
+
+// getPermitGaps returns the parts of a stay from startTime to endTime that are outside
+// of the ordered permit windows
+func getPermitGaps(windows []timespan, startTime, endTime time.Time) []timespan {
+	var gaps []timespan
+	covered := startTime
+	for _, window := range windows {
+		if window.start.After(covered) {
+			gaps = append(gaps, timespan{start: covered, end: window.start})
+		}
+		if window.end.After(covered) {
+			covered = window.end
+		}
+	}
+	if covered.Before(endTime) {
+		gaps = append(gaps, timespan{start: covered, end: endTime})
+	}
+	return gaps
+}
+
+// getCoveredMinutes returns the number of whole minutes in the permit windows
+func getCoveredMinutes(windows []timespan) int {
+	var covered time.Duration
+	for _, window := range windows {
+		covered += window.end.Sub(window.start)
+	}
+	return int(covered / time.Minute)
+}
+
+// findPermit returns the permit of the vehicle with plate in the lot with the given lotID
+// that covers the most of a stay from startTime to endTime, along with the parts of the
+// stay it covers. A permit without lots is good in every lot
+func findPermit(permits []types.Permit, plate, lotID string, startTime, endTime time.Time) (types.Permit, []timespan, bool) {
+	var (
+		best        types.Permit
+		bestWindows []timespan
+		bestMinutes int
+	)
+
+	for _, permit := range permits {
+		if !containsString(permit.Plates, normalizePlate(plate)) || (len(permit.LotIDs) > 0 && !containsString(permit.LotIDs, lotID)) {
+			continue
+		}
+		windows := getPermitWindows(permit, startTime, endTime)
+		if minutes := getCoveredMinutes(windows); len(windows) > 0 && (bestWindows == nil || minutes > bestMinutes) {
+			best, bestWindows, bestMinutes = permit, windows, minutes
+		}
+	}
+	return best, bestWindows, bestWindows != nil
+}
+
+// permitsForLot returns the permits that are good in the lot with the given lotID
+func permitsForLot(permits []types.Permit, lotID string) []types.Permit {
+	var lotPermits []types.Permit
+	for _, permit := range permits {
+		if containsString(permit.LotIDs, lotID) {
+			lotPermits = append(lotPermits, permit)
+		}
+	}
+	return lotPermits
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
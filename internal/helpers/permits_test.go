@@ -0,0 +1,225 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_CreatePermit(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         types.PermitInput
+		wantPlates []string
+		wantErr    bool
+	}{
+		{
+			name:       "Simple Passing Create",
+			in:         types.PermitInput{Holder: "Jane Doe", Plates: []string{"abc 123", "DEF-456"}, Days: "mon,tues,wed,thurs,fri", Times: "0700-1900", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantPlates: []string{"ABC123", "DEF456"},
+		},
+		{
+			name:       "Overnight Passing Create",
+			in:         types.PermitInput{Holder: "Night Shift", Plates: []string{"ABC123"}, Days: "sat,sun", Times: "2200-0600", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2018-01-01"},
+			wantPlates: []string{"ABC123"},
+		},
+		{
+			name:    "Missing Holder Error",
+			in:      types.PermitInput{Plates: []string{"ABC123"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Plates Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Empty Plate Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123", " "}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, LotIDs: []string{"missing"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Times Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", Times: "0700", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Validity Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Ends Before Start Error",
+			in:      types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-02-01", ValidUntil: "2017-01-01"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			permit, err := CreatePermit(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreatePermit() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			permits, _ := GetPermits()
+			if !test.wantErr {
+				if len(permits) != 1 || !reflect.DeepEqual(permits[0], permit) || !reflect.DeepEqual(permit.Plates, test.wantPlates) {
+					t.Errorf("CreatePermit() stored = %v, want %v with plates %v", permits, permit, test.wantPlates)
+				}
+			} else if len(permits) != 0 {
+				t.Errorf("CreatePermit() stored %d permits, want 0", len(permits))
+			}
+		})
+	}
+}
+
+func Test_getPermitWindows(t *testing.T) {
+	chicago, _ := time.LoadLocation("America/Chicago")
+	tests := []struct {
+		name   string
+		permit types.Permit
+		start  string
+		end    string
+		want   [][2]string
+	}{
+		{
+			name:   "Overnight Windows",
+			permit: types.Permit{Days: "fri,sat", Times: "2200-0600", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			start:  "2017-01-06T20:00:00-06:00",
+			end:    "2017-01-07T23:00:00-06:00",
+			want:   [][2]string{{"2017-01-06T22:00:00-06:00", "2017-01-07T06:00:00-06:00"}, {"2017-01-07T22:00:00-06:00", "2017-01-07T23:00:00-06:00"}},
+		},
+		{
+			name:   "Started The Day Before Window",
+			permit: types.Permit{Days: "thurs", Times: "2200-0600", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			start:  "2017-01-06T01:00:00-06:00",
+			end:    "2017-01-06T08:00:00-06:00",
+			want:   [][2]string{{"2017-01-06T01:00:00-06:00", "2017-01-06T06:00:00-06:00"}},
+		},
+		{
+			name:   "All Day Window",
+			permit: types.Permit{Days: "sat", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+			start:  "2017-01-06T23:00:00-06:00",
+			end:    "2017-01-07T02:00:00-06:00",
+			want:   [][2]string{{"2017-01-07T00:00:00-06:00", "2017-01-07T02:00:00-06:00"}},
+		},
+		{
+			name:   "Expired Permit",
+			permit: types.Permit{Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-01-06"},
+			start:  "2017-01-06T09:00:00-06:00",
+			end:    "2017-01-06T10:00:00-06:00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, test.start)
+			end, _ := time.Parse(time.RFC3339, test.end)
+			var got [][2]string
+			for _, window := range getPermitWindows(test.permit, start, end) {
+				got = append(got, [2]string{window.start.In(chicago).Format(time.RFC3339), window.end.In(chicago).Format(time.RFC3339)})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getPermitWindows() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_getPermitGaps(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse(time.RFC3339, "2017-01-06T"+clock+":00-06:00")
+		return t
+	}
+	tests := []struct {
+		name    string
+		windows []timespan
+		want    []timespan
+	}{
+		{
+			name:    "Gaps Either Side",
+			windows: []timespan{{start: at("17:00"), end: at("18:00")}},
+			want:    []timespan{{start: at("16:00"), end: at("17:00")}, {start: at("18:00"), end: at("19:00")}},
+		},
+		{
+			name:    "Gap Between",
+			windows: []timespan{{start: at("16:00"), end: at("17:00")}, {start: at("18:00"), end: at("19:00")}},
+			want:    []timespan{{start: at("17:00"), end: at("18:00")}},
+		},
+		{
+			name:    "Fully Covered",
+			windows: []timespan{{start: at("16:00"), end: at("19:00")}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getPermitGaps(test.windows, at("16:00"), at("19:00")); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getPermitGaps() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_findPermit(t *testing.T) {
+	permits := []types.Permit{
+		{UUID: "evenings", Plates: []string{"ABC123"}, LotIDs: []string{"garage"}, Days: "fri", Times: "1700-2400", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+		{UUID: "fridays", Plates: []string{"ABC123", "DEF456"}, LotIDs: []string{"garage"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+		{UUID: "anywhere", Plates: []string{"XYZ789"}, Days: "fri", Times: "0900-1700", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"},
+	}
+	tests := []struct {
+		name        string
+		plate       string
+		lotID       string
+		wantUUID    string
+		wantMinutes int
+		wantFound   bool
+	}{
+		{
+			name:        "Most Covering Permit",
+			plate:       "abc-123",
+			lotID:       "garage",
+			wantUUID:    "fridays",
+			wantMinutes: 180,
+			wantFound:   true,
+		},
+		{
+			name:        "Permit Without Lots",
+			plate:       "XYZ789",
+			lotID:       "surface",
+			wantUUID:    "anywhere",
+			wantMinutes: 60,
+			wantFound:   true,
+		},
+		{
+			name:  "Other Lot",
+			plate: "ABC123",
+			lotID: "surface",
+		},
+		{
+			name:  "Other Plate",
+			plate: "GHI012",
+			lotID: "garage",
+		},
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2017-01-06T16:00:00-06:00")
+	end, _ := time.Parse(time.RFC3339, "2017-01-06T19:00:00-06:00")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, windows, found := findPermit(permits, test.plate, test.lotID, start, end)
+			if found != test.wantFound || got.UUID != test.wantUUID || getCoveredMinutes(windows) != test.wantMinutes {
+				t.Errorf("findPermit() = %v, %v, %v, want %s covering %d minutes, found %v", got.UUID, windows, found, test.wantUUID, test.wantMinutes, test.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// GetProducts gets all of the products from the DB
+func GetProducts() ([]types.Product, error) {
+	return config.Config.Products.All()
+}
+
+// GetProduct gets the product with the given uuid from the DB
+func GetProduct(uuid string) (types.Product, error) {
+	return config.Config.Products.Get(uuid)
+}
+
+// CreateProduct creates a product in the DB
+func CreateProduct(in *types.ProductInput) (types.Product, error) {
+	var (
+		err     error
+		product types.Product
+	)
+
+	if err = validateProductInput(in); err != nil {
+		return product, err
+	}
+
+	uu, _ := uuid.NewV4()
+	product = getProduct(in)
+	product.UUID = uu.String()
+	product.CreatedAt = time.Now().Unix()
+
+	err = config.Config.Products.Put(product)
+	return product, err
+}
+
+// UpdateProduct replaces every field of the product with the given uuid
+func UpdateProduct(uuid string, in *types.ProductInput) (types.Product, error) {
+	var (
+		err     error
+		product types.Product
+	)
+
+	if product, err = GetProduct(uuid); err != nil {
+		return product, err
+	}
+
+	if err = validateProductInput(in); err != nil {
+		return product, err
+	}
+
+	updated := getProduct(in)
+	updated.UUID = product.UUID
+	updated.CreatedAt = product.CreatedAt
+
+	err = config.Config.Products.Put(updated)
+	return updated, err
+}
+
+// DeleteProduct removes the product with the given uuid from the DB and returns it
+func DeleteProduct(uuid string) (types.Product, error) {
+	var (
+		err     error
+		product types.Product
+	)
+
+	if product, err = GetProduct(uuid); err != nil {
+		return product, err
+	}
+
+	err = config.Config.Products.Delete(uuid)
+	return product, err
+}
+
+// getProduct returns the product that in describes, without a uuid
+func getProduct(in *types.ProductInput) types.Product {
+	return types.Product{
+		Name:         in.Name,
+		LotID:        in.LotID,
+		VehicleClass: in.VehicleClass,
+		Days:         in.Days,
+		EntryWindow:  in.EntryWindow,
+		ExitWindow:   in.ExitWindow,
+		TZ:           in.TZ,
+		Price:        in.Price,
+	}
+}
+
+// productsForLot returns the products for the lot with the given lotID, or the
+// products of the default schedule if lotID is empty
+func productsForLot(products []types.Product, lotID string) []types.Product {
+	var lotProducts []types.Product
+	for _, product := range products {
+		if product.LotID == lotID {
+			lotProducts = append(lotProducts, product)
+		}
+	}
+	return lotProducts
+}
+
+// productsForVehicleClass returns the products for the given vehicle class,
+// which includes the products that are for every class
+func productsForVehicleClass(products []types.Product, class string) []types.Product {
+	var classProducts []types.Product
+	for _, product := range products {
+		if product.VehicleClass == "" || product.VehicleClass == class {
+			classProducts = append(classProducts, product)
+		}
+	}
+	return classProducts
+}
+
+// getProductEligibility reports whether a stay from startTime to endTime enters and exits
+// within the windows of product, on the wall clock of the product's timezone, and if so
+// describes how it did. Since an entry window that wraps past midnight belongs to the day
+// it starts on, the windows that open the day before the stay starts are considered as well
+func getProductEligibility(product types.Product, startTime, endTime time.Time) (string, bool) {
+	loc, err := time.LoadLocation(product.TZ)
+	if err != nil {
+		log.Errorf("Could not load product %s timezone %s: %v", product.UUID, product.TZ, err)
+		return "", false
+	}
+
+	entryTimes, _ := timeSpanAsSlice(product.EntryWindow)
+	exitTimes, _ := timeSpanAsSlice(product.ExitWindow)
+	entryEarlier, entryLater, err := getTimeObjectsFromTimes(entryTimes)
+	if err != nil {
+		log.Errorf("Could not parse product %s entry window %s: %v", product.UUID, product.EntryWindow, err)
+		return "", false
+	}
+	exitEarlier, exitLater, err := getTimeObjectsFromTimes(exitTimes)
+	if err != nil {
+		log.Errorf("Could not parse product %s exit window %s: %v", product.UUID, product.ExitWindow, err)
+		return "", false
+	}
+
+	y, m, d := startTime.In(loc).Date()
+	for _, date := range []time.Time{time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC), time.Date(y, m, d, 0, 0, 0, 0, time.UTC)} {
+		day, _ := weekdayToDay(date.Weekday())
+		if !strings.Contains(product.Days, day) {
+			continue
+		}
+
+		entryFrom := getLocalInstant(date, entryEarlier.Hour(), entryEarlier.Minute(), loc)
+		entryUntil := getLocalInstant(date.AddDate(0, 0, entryLater.Day()-1), entryLater.Hour(), entryLater.Minute(), loc)
+		if startTime.Before(entryFrom) || !startTime.Before(entryUntil) {
+			continue
+		}
+
+		exitFrom := getLocalInstant(date, exitEarlier.Hour(), exitEarlier.Minute(), loc)
+		exitUntil := getLocalInstant(date.AddDate(0, 0, exitLater.Day()-1), exitLater.Hour(), exitLater.Minute(), loc)
+		if endTime.Before(exitFrom) || endTime.After(exitUntil) {
+			continue
+		}
+
+		return fmt.Sprintf("entered at %s within %s and exited at %s within %s", startTime.In(loc).Format("Mon 15:04"), product.EntryWindow, endTime.In(loc).Format("Mon 15:04"), product.ExitWindow), true
+	}
+	return "", false
+}
+
+// applyCheapestProduct picks between a quote from rates and the cheapest product the
+// stay is eligible for. The product is used when it is cheaper than the rates, or when
+// the rates could not price the stay and returned ratesErr
+func applyCheapestProduct(quote types.Quote, ratesErr error, products []types.Product, startTime, endTime time.Time) (types.Quote, error) {
+	var (
+		found    bool
+		cheapest types.Product
+		how      string
+	)
+	for _, product := range products {
+		if eligibility, ok := getProductEligibility(product, startTime, endTime); ok && (!found || product.Price < cheapest.Price) {
+			found, cheapest, how = true, product, eligibility
+		}
+	}
+
+	if !found {
+		return quote, ratesErr
+	}
+
+	if ratesErr == nil && quote.Total <= cheapest.Price {
+		quote.Reason = fmt.Sprintf("the rates total of %d is no more than the %d of product %s, which the stay %s", quote.Total, cheapest.Price, cheapest.Name, how)
+		return quote, nil
+	}
+
+	loc, _ := time.LoadLocation(cheapest.TZ)
+	productQuote := types.Quote{
+		Total:   cheapest.Price,
+		Days:    []types.DaySubtotal{{Date: startTime.In(loc).Format(effectiveDateLayout), Price: cheapest.Price}},
+		Product: &types.QuotedProduct{UUID: cheapest.UUID, Name: cheapest.Name, Price: cheapest.Price},
+	}
+	if ratesErr != nil {
+		productQuote.Reason = fmt.Sprintf("product %s applies because the stay %s, and the rates could not price the stay: %v", cheapest.Name, how, ratesErr)
+	} else {
+		productQuote.Reason = fmt.Sprintf("product %s applies because the stay %s, and its %d is less than the rates total of %d", cheapest.Name, how, cheapest.Price, quote.Total)
+	}
+	return productQuote, nil
+}
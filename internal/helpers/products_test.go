@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_CreateProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.ProductInput
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Create",
+			in:   types.ProductInput{Name: "Early Bird", Days: "mon,tues,wed,thurs,fri", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200},
+		},
+		{
+			name: "Wrapping Windows Passing Create",
+			in:   types.ProductInput{Name: "Evening", Days: "fri,sat", EntryWindow: "1700-0200", ExitWindow: "1700-0600", TZ: "America/Chicago", Price: 800},
+		},
+		{
+			name:    "Missing Name Error",
+			in:      types.ProductInput{Days: "mon", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Entry Window Error",
+			in:      types.ProductInput{Name: "Early Bird", Days: "mon", EntryWindow: "before 9", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Exit Window Error",
+			in:      types.ProductInput{Name: "Early Bird", Days: "mon", EntryWindow: "0500-0900", TZ: "America/Chicago", Price: 1200},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.ProductInput{Name: "Early Bird", LotID: "missing", Days: "mon", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Price Error",
+			in:      types.ProductInput{Name: "Early Bird", Days: "mon", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			product, err := CreateProduct(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreateProduct() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			products, _ := GetProducts()
+			if !test.wantErr {
+				if len(products) != 1 || products[0] != product {
+					t.Errorf("CreateProduct() stored = %v, want %v", products, product)
+				}
+			} else if len(products) != 0 {
+				t.Errorf("CreateProduct() stored %d products, want 0", len(products))
+			}
+		})
+	}
+}
+
+func Test_getProductEligibility(t *testing.T) {
+	earlyBird := types.Product{Name: "Early Bird", Days: "mon,tues,wed,thurs,fri", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago"}
+	evening := types.Product{Name: "Evening", Days: "fri", EntryWindow: "1700-0200", ExitWindow: "1700-0600", TZ: "America/Chicago"}
+	tests := []struct {
+		name    string
+		product types.Product
+		start   string
+		end     string
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:    "Early Bird Eligible",
+			product: earlyBird,
+			start:   "2017-01-06T08:30:00-06:00",
+			end:     "2017-01-06T16:00:00-06:00",
+			want:    "entered at Fri 08:30 within 0500-0900 and exited at Fri 16:00 within 1500-2000",
+			wantOk:  true,
+		},
+		{
+			name:    "Early Bird Entered Too Late",
+			product: earlyBird,
+			start:   "2017-01-06T09:00:00-06:00",
+			end:     "2017-01-06T16:00:00-06:00",
+		},
+		{
+			name:    "Early Bird Exited Too Early",
+			product: earlyBird,
+			start:   "2017-01-06T08:30:00-06:00",
+			end:     "2017-01-06T14:00:00-06:00",
+		},
+		{
+			name:    "Early Bird Exited The Next Day",
+			product: earlyBird,
+			start:   "2017-01-05T08:30:00-06:00",
+			end:     "2017-01-06T16:00:00-06:00",
+		},
+		{
+			name:    "Early Bird Wrong Day",
+			product: earlyBird,
+			start:   "2017-01-07T08:30:00-06:00",
+			end:     "2017-01-07T16:00:00-06:00",
+		},
+		{
+			name:    "Evening Exited The Next Morning",
+			product: evening,
+			start:   "2017-01-06T18:00:00-06:00",
+			end:     "2017-01-07T05:00:00-06:00",
+			want:    "entered at Fri 18:00 within 1700-0200 and exited at Sat 05:00 within 1700-0600",
+			wantOk:  true,
+		},
+		{
+			name:    "Evening Entered After Midnight",
+			product: evening,
+			start:   "2017-01-07T01:00:00-06:00",
+			end:     "2017-01-07T05:00:00-06:00",
+			want:    "entered at Sat 01:00 within 1700-0200 and exited at Sat 05:00 within 1700-0600",
+			wantOk:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, test.start)
+			end, _ := time.Parse(time.RFC3339, test.end)
+			got, ok := getProductEligibility(test.product, start, end)
+			if got != test.want || ok != test.wantOk {
+				t.Errorf("getProductEligibility() got = %q, %v, want %q, %v", got, ok, test.want, test.wantOk)
+			}
+		})
+	}
+}
+
+func Test_applyCheapestProduct(t *testing.T) {
+	products := []types.Product{
+		{UUID: "a", Name: "Early Bird", Days: "fri", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1500},
+		{UUID: "b", Name: "Commuter", Days: "fri", EntryWindow: "0700-1000", ExitWindow: "1500-1900", TZ: "America/Chicago", Price: 1200},
+		{UUID: "c", Name: "Weekend", Days: "sat", EntryWindow: "0000-2400", ExitWindow: "0000-2400", TZ: "America/Chicago", Price: 100},
+	}
+	ratesQuote := types.Quote{Total: 3000, Days: []types.DaySubtotal{{Date: "2017-01-06", Price: 3000}}}
+	tests := []struct {
+		name        string
+		quote       types.Quote
+		ratesErr    error
+		products    []types.Product
+		wantTotal   int
+		wantProduct *types.QuotedProduct
+		wantErr     bool
+	}{
+		{
+			name:      "No Products",
+			quote:     ratesQuote,
+			wantTotal: 3000,
+		},
+		{
+			name:        "Cheapest Eligible Product",
+			quote:       ratesQuote,
+			products:    products,
+			wantTotal:   1200,
+			wantProduct: &types.QuotedProduct{UUID: "b", Name: "Commuter", Price: 1200},
+		},
+		{
+			name:      "Rates Cheaper Than Products",
+			quote:     types.Quote{Total: 1000, Days: []types.DaySubtotal{{Date: "2017-01-06", Price: 1000}}},
+			products:  products,
+			wantTotal: 1000,
+		},
+		{
+			name:        "Product For Stay Rates Cannot Price",
+			ratesErr:    errUnavailable,
+			products:    products,
+			wantTotal:   1200,
+			wantProduct: &types.QuotedProduct{UUID: "b", Name: "Commuter", Price: 1200},
+		},
+		{
+			name:     "Rates Error Without Eligible Products",
+			ratesErr: errUnavailable,
+			products: products[2:],
+			wantErr:  true,
+		},
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2017-01-06T08:30:00-06:00")
+	end, _ := time.Parse(time.RFC3339, "2017-01-06T16:00:00-06:00")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applyCheapestProduct(test.quote, test.ratesErr, test.products, start, end)
+			if (err != nil) != test.wantErr {
+				t.Errorf("applyCheapestProduct() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if got.Total != test.wantTotal || !reflect.DeepEqual(got.Product, test.wantProduct) {
+				t.Errorf("applyCheapestProduct() got total %d and product %v, want %d and %v", got.Total, got.Product, test.wantTotal, test.wantProduct)
+			}
+			if wantReason := len(test.products) > 0 && !test.wantErr; (got.Reason != "") != wantReason {
+				t.Errorf("applyCheapestProduct() got reason %q, want one %v", got.Reason, wantReason)
+			}
+		})
+	}
+}
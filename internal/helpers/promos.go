@@ -0,0 +1,277 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// promo types, and the type and source of the adjustments that promos make
+const (
+	promoPercent = "percent"
+	promoAmount  = "amount"
+	adjustPromo  = "promo"
+	sourcePromo  = "promo"
+)
+
+// promoUpdateAttempts is how many times updating a promo is tried when the promo is
+// redeemed while it is being updated
+const promoUpdateAttempts = 5
+
+// promoRedeemAttempts is how many times a stay is quoted to redeem its promo code when the
+// promo is used up between quoting the stay and redeeming it
+const promoRedeemAttempts = 5
+
+// GetPromos gets all of the promos from the DB
+func GetPromos() ([]types.Promo, error) {
+	return config.Config.Promos.All()
+}
+
+// GetPromo gets the promo with the given uuid from the DB
+func GetPromo(uuid string) (types.Promo, error) {
+	return config.Config.Promos.Get(uuid)
+}
+
+// CreatePromo creates a promo in the DB
+func CreatePromo(in *types.PromoInput) (types.Promo, error) {
+	var (
+		err    error
+		promo  types.Promo
+		promos []types.Promo
+	)
+
+	if promos, err = GetPromos(); err != nil {
+		return promo, err
+	}
+
+	if err = validatePromoInput(in, promos); err != nil {
+		return promo, err
+	}
+
+	uu, _ := uuid.NewV4()
+	promo = getPromo(in)
+	promo.UUID = uu.String()
+	promo.CreatedAt = time.Now().Unix()
+
+	err = config.Config.Promos.Put(promo)
+	return promo, err
+}
+
+// UpdatePromo replaces every field of the promo with the given uuid, keeping
+// the number of times it has been used. The promo is read again and the update
+// tried again when the promo is redeemed in the meantime
+func UpdatePromo(uuid string, in *types.PromoInput) (types.Promo, error) {
+	var (
+		err     error
+		promo   types.Promo
+		promos  []types.Promo
+		updated types.Promo
+	)
+
+	if promo, err = GetPromo(uuid); err != nil {
+		return promo, err
+	}
+
+	if promos, err = GetPromos(); err != nil {
+		return promo, err
+	}
+
+	if err = validatePromoInput(in, promosExcept(promos, uuid)); err != nil {
+		return promo, err
+	}
+
+	for attempt := 0; attempt < promoUpdateAttempts; attempt++ {
+		updated = getPromo(in)
+		updated.UUID = promo.UUID
+		updated.Uses = promo.Uses
+		updated.CreatedAt = promo.CreatedAt
+		if err = config.Config.Promos.Update(updated, promo.Uses); !errors.Is(err, store.ErrConflict) {
+			return updated, err
+		}
+
+		if promo, err = GetPromo(uuid); err != nil {
+			return promo, err
+		}
+	}
+	return updated, store.ErrConflict
+}
+
+// DeletePromo removes the promo with the given uuid from the DB and returns it
+func DeletePromo(uuid string) (types.Promo, error) {
+	var (
+		err   error
+		promo types.Promo
+	)
+
+	if promo, err = GetPromo(uuid); err != nil {
+		return promo, err
+	}
+
+	err = config.Config.Promos.Delete(uuid)
+	return promo, err
+}
+
+// RedeemPromo records one use of the promo with the given uuid and returns it. A promo
+// that has already been used as many times as it is allowed is not redeemed again
+func RedeemPromo(uuid string) (types.Promo, error) {
+	return config.Config.Promos.Redeem(uuid)
+}
+
+// getRedeemedQuote quotes a stay like GetTimespanPrice and redeems the promo code that the
+// quote accepts, quoting the stay again if the promo is used up in between so that the
+// quote rejects it rather than discounting a use that was never recorded
+func getRedeemedQuote(in *types.GetTimespanPriceInput) (types.Quote, error) {
+	for attempt := 0; attempt < promoRedeemAttempts; attempt++ {
+		quote, err := GetTimespanPrice(in)
+		if err != nil || quote.Promo == nil || quote.Promo.Rejected != "" {
+			return quote, err
+		}
+		if _, err = RedeemPromo(quote.Promo.UUID); !errors.Is(err, store.ErrLimitReached) {
+			return quote, err
+		}
+	}
+	return types.Quote{}, fmt.Errorf("promo code %s kept being used up while it was redeemed: %w", in.PromoCode, store.ErrConflict)
+}
+
+// unredeemQuotedPromo gives back the use of the promo code that getRedeemedQuote redeemed
+// for quote, if it redeemed one
+func unredeemQuotedPromo(quote types.Quote) error {
+	if quote.Promo == nil || quote.Promo.Rejected != "" {
+		return nil
+	}
+	return config.Config.Promos.Unredeem(quote.Promo.UUID)
+}
+
+// getPromo returns the promo that in describes, without a uuid
+func getPromo(in *types.PromoInput) types.Promo {
+	return types.Promo{
+		Code:           strings.TrimSpace(in.Code),
+		Name:           in.Name,
+		Type:           in.Type,
+		Value:          in.Value,
+		LotID:          in.LotID,
+		Days:           in.Days,
+		TZ:             in.TZ,
+		EffectiveFrom:  in.EffectiveFrom,
+		EffectiveUntil: in.EffectiveUntil,
+		MaxUses:        in.MaxUses,
+	}
+}
+
+// findPromo returns the promo whose code is code, regardless of case
+func findPromo(promos []types.Promo, code string) (types.Promo, bool) {
+	for _, promo := range promos {
+		if strings.EqualFold(promo.Code, strings.TrimSpace(code)) {
+			return promo, true
+		}
+	}
+	return types.Promo{}, false
+}
+
+// promosExcept returns promos without the promo with the given uuid
+func promosExcept(promos []types.Promo, uuid string) []types.Promo {
+	var others []types.Promo
+	for _, promo := range promos {
+		if promo.UUID != uuid {
+			others = append(others, promo)
+		}
+	}
+	return others
+}
+
+// promosForLot returns the promos that are only good in the lot with the given lotID
+func promosForLot(promos []types.Promo, lotID string) []types.Promo {
+	var lotPromos []types.Promo
+	for _, promo := range promos {
+		if promo.LotID == lotID {
+			lotPromos = append(lotPromos, promo)
+		}
+	}
+	return lotPromos
+}
+
+// getPromoRejection returns why promo cannot be used for a stay in the lot with the
+// given lotID that starts at startTime, or "" when it can be used
+func getPromoRejection(promo types.Promo, lotID string, startTime time.Time) string {
+	loc, err := time.LoadLocation(promo.TZ)
+	if err != nil {
+		log.Errorf("Could not load promo %s timezone %s: %v", promo.UUID, promo.TZ, err)
+		return fmt.Sprintf("promo code %s cannot be used right now", promo.Code)
+	}
+
+	startDate := startTime.In(loc).Format(effectiveDateLayout)
+	if promo.EffectiveFrom != "" && startDate < promo.EffectiveFrom {
+		return fmt.Sprintf("promo code %s is only valid for stays starting on or after %s", promo.Code, promo.EffectiveFrom)
+	}
+	if promo.EffectiveUntil != "" && startDate >= promo.EffectiveUntil {
+		return fmt.Sprintf("promo code %s expired on %s", promo.Code, promo.EffectiveUntil)
+	}
+
+	if promo.MaxUses > 0 && promo.Uses >= promo.MaxUses {
+		return fmt.Sprintf("promo code %s has already been used the %d times it is allowed", promo.Code, promo.MaxUses)
+	}
+
+	if promo.LotID != "" && promo.LotID != lotID {
+		return fmt.Sprintf("promo code %s is not valid in this lot", promo.Code)
+	}
+
+	if promo.Days != "" {
+		day, _ := weekdayToDay(startTime.In(loc).Weekday())
+		if !strings.Contains(promo.Days, day) {
+			return fmt.Sprintf("promo code %s is only valid for stays starting on %s", promo.Code, promo.Days)
+		}
+	}
+
+	return ""
+}
+
+// applyPromoCode takes the discount of the promo with the given code off quote, spreading
+// it over the days of the stay: a percent promo takes its percentage off every day, rounded
+// down to the cent, and an amount promo is taken off the days in order until it is used
+// up, so a discount is never more than the price. A code that cannot be used leaves the
+// price as it is, and the quote says why the code was rejected
+func applyPromoCode(quote types.Quote, promos []types.Promo, code, lotID string, startTime time.Time) types.Quote {
+	quoted := &types.QuotedPromo{Code: code, Subtotal: quote.Total}
+	quote.Promo = quoted
+
+	promo, found := findPromo(promos, code)
+	if !found {
+		quoted.Rejected = fmt.Sprintf("promo code %s does not exist", code)
+		return quote
+	}
+	quoted.UUID, quoted.Code = promo.UUID, promo.Code
+	if quoted.Rejected = getPromoRejection(promo, lotID, startTime); quoted.Rejected != "" {
+		return quote
+	}
+
+	remaining := promo.Value
+	quote.Days = append([]types.DaySubtotal(nil), quote.Days...)
+	for i, day := range quote.Days {
+		var discount int
+		switch promo.Type {
+		case promoPercent:
+			discount = day.Price * promo.Value / 100
+		case promoAmount:
+			if discount = remaining; discount > day.Price {
+				discount = day.Price
+			}
+			remaining -= discount
+		}
+		if discount == 0 {
+			continue
+		}
+
+		quote.Days[i].Price -= discount
+		quote.Total -= discount
+		quoted.Discount += discount
+		quote.Adjustments = append(quote.Adjustments, types.PriceAdjustment{Type: adjustPromo, Source: sourcePromo, Date: day.Date, Amount: -discount})
+	}
+	return quote
+}
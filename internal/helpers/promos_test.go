@@ -0,0 +1,229 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_CreatePromo(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.PromoInput
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Create",
+			in:   types.PromoInput{Code: "WEEKEND20", Name: "20% off weekends", Type: "percent", Value: 20, Days: "sat,sun", TZ: "America/Chicago"},
+		},
+		{
+			name: "Limited Amount Passing Create",
+			in:   types.PromoInput{Code: "FIRST5", Name: "$5 off", Type: "amount", Value: 500, TZ: "America/Chicago", EffectiveFrom: "2017-01-01", EffectiveUntil: "2017-02-01", MaxUses: 100},
+		},
+		{
+			name:    "Missing Code Error",
+			in:      types.PromoInput{Name: "20% off", Type: "percent", Value: 20, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Code With Spaces Error",
+			in:      types.PromoInput{Code: "WEEKEND 20", Type: "percent", Value: 20, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Type Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "half", Value: 50, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Percent Over 100 Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 120, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Amount Error",
+			in:      types.PromoInput{Code: "FIRST5", Type: "amount", TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Days Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 20, Days: "weekend", TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 20, LotID: "missing", TZ: "America/Chicago"},
+			wantErr: true,
+		},
+		{
+			name:    "Ends Before Start Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 20, TZ: "America/Chicago", EffectiveFrom: "2017-02-01", EffectiveUntil: "2017-01-01"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Max Uses Error",
+			in:      types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 20, TZ: "America/Chicago", MaxUses: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			promo, err := CreatePromo(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreatePromo() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			promos, _ := GetPromos()
+			if !test.wantErr {
+				if len(promos) != 1 || promos[0] != promo {
+					t.Errorf("CreatePromo() stored = %v, want %v", promos, promo)
+				}
+			} else if len(promos) != 0 {
+				t.Errorf("CreatePromo() stored %d promos, want 0", len(promos))
+			}
+		})
+	}
+}
+
+func Test_UpdatePromo(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      types.PromoInput
+		wantErr bool
+	}{
+		{
+			name: "Same Code Passing Update",
+			in:   types.PromoInput{Code: "weekend20", Name: "20% off weekends", Type: "percent", Value: 20, Days: "sat,sun", TZ: "America/Chicago"},
+		},
+		{
+			name: "New Code Passing Update",
+			in:   types.PromoInput{Code: "WEEKEND25", Name: "25% off weekends", Type: "percent", Value: 25, Days: "sat,sun", TZ: "America/Chicago"},
+		},
+		{
+			name:    "Other Promo's Code Error",
+			in:      types.PromoInput{Code: "First5", Name: "20% off weekends", Type: "percent", Value: 20, TZ: "America/Chicago"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			promo, err := CreatePromo(&types.PromoInput{Code: "WEEKEND20", Type: "percent", Value: 20, TZ: "America/Chicago"})
+			if err != nil {
+				t.Fatalf("CreatePromo() setup error = %v", err)
+			}
+			if _, err = CreatePromo(&types.PromoInput{Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago"}); err != nil {
+				t.Fatalf("CreatePromo() setup error = %v", err)
+			}
+			if promo, err = RedeemPromo(promo.UUID); err != nil {
+				t.Fatalf("RedeemPromo() setup error = %v", err)
+			}
+
+			updated, err := UpdatePromo(promo.UUID, &test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("UpdatePromo() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (updated.Code != test.in.Code || updated.Uses != promo.Uses || updated.CreatedAt != promo.CreatedAt) {
+				t.Errorf("UpdatePromo() = %v, want code %s with uses and created at of %v", updated, test.in.Code, promo)
+			}
+		})
+	}
+}
+
+func Test_applyPromoCode(t *testing.T) {
+	promos := []types.Promo{
+		{UUID: "a", Code: "WEEKEND20", Type: "percent", Value: 20, Days: "sat,sun", TZ: "America/Chicago"},
+		{UUID: "b", Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago"},
+		{UUID: "c", Code: "BIG50", Type: "amount", Value: 5000, TZ: "America/Chicago"},
+		{UUID: "d", Code: "EXPIRED", Type: "amount", Value: 500, TZ: "America/Chicago", EffectiveUntil: "2017-01-01"},
+		{UUID: "e", Code: "USEDUP", Type: "amount", Value: 500, TZ: "America/Chicago", MaxUses: 10, Uses: 10},
+		{UUID: "f", Code: "GARAGE", Type: "amount", Value: 500, LotID: "garage", TZ: "America/Chicago"},
+	}
+	quote := types.Quote{Total: 3000, Days: []types.DaySubtotal{{Date: "2017-01-07", Price: 1000}, {Date: "2017-01-08", Price: 2000}}}
+	tests := []struct {
+		name         string
+		code         string
+		lotID        string
+		wantTotal    int
+		wantDays     []types.DaySubtotal
+		wantRejected bool
+	}{
+		{
+			name:      "Percent Promo",
+			code:      "weekend20",
+			wantTotal: 2400,
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-07", Price: 800}, {Date: "2017-01-08", Price: 1600}},
+		},
+		{
+			name:      "Amount Promo",
+			code:      "FIRST5",
+			wantTotal: 2500,
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-07", Price: 500}, {Date: "2017-01-08", Price: 2000}},
+		},
+		{
+			name:      "Amount Promo Over Price",
+			code:      "BIG50",
+			wantTotal: 0,
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-07", Price: 0}, {Date: "2017-01-08", Price: 0}},
+		},
+		{
+			name:      "Lot Promo",
+			code:      "GARAGE",
+			lotID:     "garage",
+			wantTotal: 2500,
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-07", Price: 500}, {Date: "2017-01-08", Price: 2000}},
+		},
+		{
+			name:         "Missing Promo Rejected",
+			code:         "NOPE",
+			wantTotal:    3000,
+			wantDays:     quote.Days,
+			wantRejected: true,
+		},
+		{
+			name:         "Expired Promo Rejected",
+			code:         "EXPIRED",
+			wantTotal:    3000,
+			wantDays:     quote.Days,
+			wantRejected: true,
+		},
+		{
+			name:         "Used Up Promo Rejected",
+			code:         "USEDUP",
+			wantTotal:    3000,
+			wantDays:     quote.Days,
+			wantRejected: true,
+		},
+		{
+			name:         "Other Lot Promo Rejected",
+			code:         "GARAGE",
+			wantTotal:    3000,
+			wantDays:     quote.Days,
+			wantRejected: true,
+		},
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2017-01-07T18:00:00-06:00")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := applyPromoCode(quote, promos, test.code, test.lotID, start)
+			if got.Total != test.wantTotal || !reflect.DeepEqual(got.Days, test.wantDays) {
+				t.Errorf("applyPromoCode() got total %d and days %v, want %d and %v", got.Total, got.Days, test.wantTotal, test.wantDays)
+			}
+			if got.Promo == nil || (got.Promo.Rejected != "") != test.wantRejected {
+				t.Errorf("applyPromoCode() promo = %v, wantRejected %v", got.Promo, test.wantRejected)
+				return
+			}
+			if got.Promo.Subtotal != quote.Total || got.Promo.Subtotal-got.Promo.Discount != got.Total {
+				t.Errorf("applyPromoCode() promo = %v, want subtotal %d less discount to be %d", got.Promo, quote.Total, got.Total)
+			}
+		})
+	}
+}
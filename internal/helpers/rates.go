@@ -2,9 +2,10 @@ package helpers
 
 import (
 	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
 	"charlie-parker/pkg/types"
 	"errors"
-	"strconv"
+	"fmt"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -12,34 +13,55 @@ import (
 
 // GetRates gets all of the rates from the DB
 func GetRates() ([]types.Rate, error) {
-	var rates []types.Rate
-	err := config.Config.RatesTableConn.Scan().All(&rates)
-	return rates, err
+	return config.Config.Rates.All()
 }
 
 // CreateRate creates a rate in the DB and allows for optional validation of the inputs
-// against existing rates for overlap and the option to create the rate in the DB immediately
+// against existing rates for overlap and the option to create the rate in the DB immediately.
+// A rate created immediately is only stored if no other rate was written since it was validated
 func CreateRate(in *types.CreateRateInput, checkOverlap bool, createImmediately bool) (types.Rate, error) {
 	var (
-		err  error
-		rate types.Rate
+		err      error
+		rate     types.Rate
+		revision int
 	)
 
+	if createImmediately {
+		if revision, err = config.Config.Rates.Revision(); err != nil {
+			return rate, err
+		}
+	}
+
 	if err = validateCreateRateInput(in, checkOverlap); err != nil {
 		return rate, err
 	}
 
 	uu, _ := uuid.NewV4()
 	rate = types.Rate{
-		Days:  in.Days,
-		Times: in.Times,
-		TZ:    in.TZ,
-		Price: in.Price,
-		UUID:  uu.String(),
+		LotID:            in.LotID,
+		VehicleClass:     in.VehicleClass,
+		Days:             in.Days,
+		Times:            in.Times,
+		TZ:               in.TZ,
+		Price:            in.Price,
+		BillingIncrement: in.BillingIncrement,
+		Rounding:         in.Rounding,
+		Tiers:            in.Tiers,
+		DailyMax:         in.DailyMax,
+		MinCharge:        in.MinCharge,
+		EntryGrace:       in.EntryGrace,
+		ExitGrace:        in.ExitGrace,
+		FreeMinutes:      in.FreeMinutes,
+		EffectiveFrom:    in.EffectiveFrom,
+		EffectiveUntil:   in.EffectiveUntil,
+		Dates:            in.Dates,
+		Calendar:         in.Calendar,
+		UUID:             uu.String(),
+		Version:          1,
 	}
 
 	if createImmediately {
-		if err = putRatesInTable(rate); err != nil {
+		if err = config.Config.Rates.Create(rate, revision); err != nil {
 			return rate, err
 		}
 	}
@@ -47,11 +69,206 @@ func CreateRate(in *types.CreateRateInput, checkOverlap bool, createImmediately
 	return rate, err
 }
 
-// OverwriteRates deletes all existing rates and replaces them with new ones from input
+// GetRate gets the rate with the given uuid from the DB
+func GetRate(uuid string) (types.Rate, error) {
+	return config.Config.Rates.Get(uuid)
+}
+
+// GetUpcomingRateChanges lists the dates on which rates are scheduled to start or stop applying
+func GetUpcomingRateChanges() ([]types.RateChange, error) {
+	rates, err := GetRates()
+	if err != nil {
+		return nil, err
+	}
+	return getUpcomingRateChanges(rates, time.Now()), nil
+}
+
+// UpdateRate replaces every field of the rate with the given uuid with the fields from input.
+// If ifMatch is not 0 the rate is only updated if it is still at that version. The update
+// is only stored if no other rate was written since it was validated
+func UpdateRate(uuid string, ifMatch int, in *types.CreateRateInput) (types.Rate, error) {
+	var (
+		err      error
+		rate     types.Rate
+		revision int
+	)
+
+	if revision, err = config.Config.Rates.Revision(); err != nil {
+		return rate, err
+	}
+
+	if rate, err = GetRate(uuid); err != nil {
+		return rate, err
+	}
+
+	if err = checkVersion(rate, ifMatch); err != nil {
+		return rate, err
+	}
+
+	if err = validateUpdateRateInput(uuid, in); err != nil {
+		return rate, err
+	}
+
+	version := rate.Version
+	rate.LotID = in.LotID
+	rate.VehicleClass = in.VehicleClass
+	rate.Days = in.Days
+	rate.Times = in.Times
+	rate.TZ = in.TZ
+	rate.Price = in.Price
+	rate.BillingIncrement = in.BillingIncrement
+	rate.Rounding = in.Rounding
+	rate.Tiers = in.Tiers
+	rate.DailyMax = in.DailyMax
+	rate.MinCharge = in.MinCharge
+	rate.EntryGrace = in.EntryGrace
+	rate.ExitGrace = in.ExitGrace
+	rate.FreeMinutes = in.FreeMinutes
+	rate.EffectiveFrom = in.EffectiveFrom
+	rate.EffectiveUntil = in.EffectiveUntil
+	rate.Dates = in.Dates
+	rate.Calendar = in.Calendar
+	rate.Version = version + 1
+
+	err = config.Config.Rates.Update(rate, version, revision)
+	return rate, err
+}
+
+// PatchRate changes only the given fields of the rate with the given uuid. If ifMatch
+// is not 0 the rate is only patched if it is still at that version
+func PatchRate(uuid string, ifMatch int, in *types.PatchRateInput) (types.Rate, error) {
+	var (
+		err  error
+		rate types.Rate
+	)
+
+	if rate, err = GetRate(uuid); err != nil {
+		return rate, err
+	}
+
+	if err = checkVersion(rate, ifMatch); err != nil {
+		return rate, err
+	}
+
+	update := getRateInput(rate)
+	if in.LotID != nil {
+		update.LotID = *in.LotID
+	}
+	if in.VehicleClass != nil {
+		update.VehicleClass = *in.VehicleClass
+	}
+	if in.Days != nil {
+		update.Days = *in.Days
+	}
+	if in.Times != nil {
+		update.Times = *in.Times
+	}
+	if in.TZ != nil {
+		update.TZ = *in.TZ
+	}
+	if in.Price != nil {
+		update.Price = *in.Price
+	}
+	if in.BillingIncrement != nil {
+		update.BillingIncrement = *in.BillingIncrement
+	}
+	if in.Rounding != nil {
+		update.Rounding = *in.Rounding
+	}
+	if in.Tiers != nil {
+		update.Tiers = *in.Tiers
+	}
+	if in.DailyMax != nil {
+		update.DailyMax = *in.DailyMax
+	}
+	if in.MinCharge != nil {
+		update.MinCharge = *in.MinCharge
+	}
+	if in.EntryGrace != nil {
+		update.EntryGrace = *in.EntryGrace
+	}
+	if in.ExitGrace != nil {
+		update.ExitGrace = *in.ExitGrace
+	}
+	if in.FreeMinutes != nil {
+		update.FreeMinutes = *in.FreeMinutes
+	}
+	if in.EffectiveFrom != nil {
+		update.EffectiveFrom = *in.EffectiveFrom
+	}
+	if in.EffectiveUntil != nil {
+		update.EffectiveUntil = *in.EffectiveUntil
+	}
+	if in.Dates != nil {
+		update.Dates = *in.Dates
+	}
+	if in.Calendar != nil {
+		update.Calendar = *in.Calendar
+	}
+
+	// the patch was applied to this version, so it must still be the stored one
+	return UpdateRate(uuid, rate.Version, &update)
+}
+
+// DeleteRate removes the rate with the given uuid from the DB and returns it. If
+// ifMatch is not 0 the rate is only deleted if it is still at that version
+func DeleteRate(uuid string, ifMatch int) (types.Rate, error) {
+	var (
+		err  error
+		rate types.Rate
+	)
+
+	if rate, err = GetRate(uuid); err != nil {
+		return rate, err
+	}
+
+	if err = checkVersion(rate, ifMatch); err != nil {
+		return rate, err
+	}
+
+	err = config.Config.Rates.Delete(uuid, rate.Version)
+	return rate, err
+}
+
+// getRateInput returns the input that would create rate as it is
+func getRateInput(rate types.Rate) types.CreateRateInput {
+	return types.CreateRateInput{
+		LotID:            rate.LotID,
+		VehicleClass:     rate.VehicleClass,
+		Days:             rate.Days,
+		Times:            rate.Times,
+		TZ:               rate.TZ,
+		Price:            rate.Price,
+		BillingIncrement: rate.BillingIncrement,
+		Rounding:         rate.Rounding,
+		Tiers:            rate.Tiers,
+		DailyMax:         rate.DailyMax,
+		MinCharge:        rate.MinCharge,
+		EntryGrace:       rate.EntryGrace,
+		ExitGrace:        rate.ExitGrace,
+		FreeMinutes:      rate.FreeMinutes,
+		EffectiveFrom:    rate.EffectiveFrom,
+		EffectiveUntil:   rate.EffectiveUntil,
+		Dates:            rate.Dates,
+		Calendar:         rate.Calendar,
+	}
+}
+
+// checkVersion errors if ifMatch is not 0 and rate is at another version
+func checkVersion(rate types.Rate, ifMatch int) error {
+	if ifMatch != 0 && rate.Version != ifMatch {
+		return &store.VersionConflictError{UUID: rate.UUID, Current: rate.Version}
+	}
+	return nil
+}
+
+// OverwriteRates replaces all existing rates with new ones from input. The swap is
+// all-or-nothing: if it fails, the existing rates are left exactly as they were
 func OverwriteRates(in *types.OverwriteRatesInput) ([]types.Rate, error) {
 	var (
-		err   error
-		rates []types.Rate
+		err       error
+		rates     []types.Rate
+		calendars []types.Calendar
 	)
 
 	if in.Rates == nil {
@@ -60,8 +277,12 @@ func OverwriteRates(in *types.OverwriteRatesInput) ([]types.Rate, error) {
 		return rates, errors.New("specify at least 1 rate to create")
 	}
 
+	if calendars, err = GetCalendars(); err != nil {
+		return rates, err
+	}
+
 	for _, input := range *in.Rates {
-		if err = validateAgainstExistingRates(rates, input); err != nil {
+		if err = validateAgainstExistingRates(rates, calendars, input); err != nil {
 			return rates, err
 		}
 
@@ -73,48 +294,251 @@ func OverwriteRates(in *types.OverwriteRatesInput) ([]types.Rate, error) {
 		rates = append(rates, rate)
 	}
 
-	var oldRates []types.Rate
-	if oldRates, err = GetRates(); err != nil {
-		return rates, err
-	}
-
-	for _, oldRate := range oldRates {
-		if err = config.Config.RatesTableConn.Delete("UUID", oldRate.UUID).Run(); err != nil {
-			return rates, err
-		}
-	}
-	err = putRatesInTable(rates...)
+	err = config.Config.Rates.Replace(rates)
 	return rates, err
 }
 
-// GetTimespanPrice finds the price corresponding to the given input
-func GetTimespanPrice(in *types.GetTimespanPriceInput) (string, error) {
+// GetTimespanPrice splits the given timespan, which may cover any number of days, at
+// midnight and at rate boundaries and prices each segment for its duration using the
+// hourly price and billing rules of the rate that covers it. Start and end are treated
+// as instants, so they may be given in any timezone, and are matched against rates on
+// the wall clock of the rates' own timezone. The daily maximums and minimum charges of
+// the rates and the lot are applied to the segment prices last. The stay is then priced
+// by the cheapest product it is eligible for instead, if that is cheaper or if the rates
+// cannot price it
+func GetTimespanPrice(in *types.GetTimespanPriceInput) (types.Quote, error) {
 	var (
 		err                error
-		price              string = "unavailable"
-		matchedRate        types.Rate
+		lot                types.Lot
+		quote              types.Quote
 		existingRates      []types.Rate
+		products           []types.Product
 		startTime, endTime time.Time
 	)
 
 	if in.Start == nil {
-		return price, errors.New("specify start")
+		return quote, fmt.Errorf("%w: specify start", ErrInvalidInput)
 	} else if in.End == nil {
-		return price, errors.New("specify end")
+		return quote, fmt.Errorf("%w: specify end", ErrInvalidInput)
 	}
 
 	if startTime, endTime, err = validateTimeRange(in.Start, in.End); err != nil {
-		return price, err
+		return quote, err
+	}
+
+	if in.LotID != "" {
+		if lot, err = GetLot(in.LotID); err != nil {
+			return quote, fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+		}
+	}
+
+	if in.VehicleClass != "" {
+		if err = isValidVehicleClass(in.VehicleClass); err != nil {
+			return quote, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
 	}
 
 	if existingRates, err = GetRates(); err != nil {
-		return price, err
+		return quote, err
+	}
+	existingRates = ratesForLot(existingRates, in.LotID)
+	// a vehicle class with no rates of its own in the lot pays the default class's rates
+	if classRates := ratesForVehicleClass(existingRates, in.VehicleClass); len(classRates) > 0 {
+		existingRates = classRates
+	} else {
+		existingRates = ratesForVehicleClass(existingRates, "")
+	}
+
+	var calendars []types.Calendar
+	if calendars, err = GetCalendars(); err != nil {
+		return quote, err
+	}
+	// date and calendar overrides take precedence over weekday rates on their dates
+	existingRates = resolveOverrides(existingRates, calendars)
+
+	var (
+		surge    types.QuotedSurge
+		surging  bool
+		occupied types.Occupancy
+	)
+	if len(lot.SurgeRules) > 0 {
+		// a lot that has not reported its occupancy yet is not surged
+		if occupied, err = GetOccupancy(lot.UUID); err == nil {
+			if surge, surging = getSurge(lot, occupied); surging {
+				existingRates = surgeRates(existingRates, surge.Increase)
+			}
+		} else if !errors.Is(err, store.ErrNotFound) {
+			return quote, err
+		}
+	}
+
+	if products, err = GetProducts(); err != nil {
+		return quote, err
+	}
+	products = productsForVehicleClass(productsForLot(products, in.LotID), in.VehicleClass)
+
+	var surged *types.QuotedSurge
+	if surging {
+		surged = &surge
 	}
 
-	if matchedRate, err = matchTimespanToRate(startTime, endTime, existingRates); err != nil {
-		return price, err
+	var permits []types.Permit
+	if in.Plate != "" {
+		if permits, err = GetPermits(); err != nil {
+			return quote, err
+		}
+	}
+
+	// only the parts of the stay outside a permit's hours are priced
+	permit, windows, permitted := findPermit(permits, in.Plate, in.LotID, startTime, endTime)
+	if quote, err = getStayQuote(existingRates, products, in.TZ, lot, surged, startTime, endTime, windows); err != nil {
+		return quote, err
+	}
+	if permitted {
+		quote.Permit = &types.QuotedPermit{UUID: permit.UUID, Holder: permit.Holder, CoveredMinutes: getCoveredMinutes(windows)}
+	}
+
+	if in.PromoCode == "" {
+		return quote, nil
+	}
+
+	var promos []types.Promo
+	if promos, err = GetPromos(); err != nil {
+		return quote, err
+	}
+	return applyPromoCode(quote, promos, in.PromoCode, in.LotID, startTime), nil
+}
+
+// getStayQuote prices a stay from startTime to endTime with existingRates like
+// getGracedRatesQuote, marking it with surge when the rates were surged, and then
+// with the cheapest of products that the stay is eligible for if that is cheaper
+func getStayQuote(existingRates []types.Rate, products []types.Product, tz string, lot types.Lot, surge *types.QuotedSurge, startTime, endTime time.Time, covered []timespan) (types.Quote, error) {
+	quote, err := getGracedRatesQuote(existingRates, tz, lot, startTime, endTime, covered)
+	if err == nil {
+		quote.Surge = surge
+	}
+	return applyCheapestProduct(quote, err, products, startTime, endTime)
+}
+
+// getRatesQuote prices a stay from startTime to endTime with existingRates, choosing
+// the timezone of the rates with tz when they are in several, and applies the daily
+// maximums and minimum charges of the rates and lot. The ordered covered parts of the
+// stay, such as the hours of a permit, are left out and need no rate. The segments that
+// were priced are returned along with the quote
+func getRatesQuote(existingRates []types.Rate, tz string, lot types.Lot, startTime, endTime time.Time, covered []timespan) (types.Quote, []rateSegment, error) {
+	var (
+		err      error
+		quote    types.Quote
+		segments []rateSegment
+		loc      *time.Location
+	)
+
+	if existingRates, loc, err = getRatesForTimezone(existingRates, tz, startTime); err != nil {
+		return quote, segments, err
+	}
+	// work on the wall clock of the rates' timezone from here on so that
+	// days are split at its midnight and the breakdown reads in its local time
+	startTime, endTime = startTime.In(loc), endTime.In(loc)
+
+	for _, gap := range getPermitGaps(covered, startTime, endTime) {
+		var gapSegments []rateSegment
+		if gapSegments, err = splitTimespanAtRates(gap.start.In(loc), gap.end.In(loc), existingRates); err != nil {
+			return quote, segments, err
+		}
+		segments = append(segments, gapSegments...)
+	}
+
+	// minutes already billed by each rate, which decide the tiers later segments start in
+	billed := make(map[string]int)
+	// the stay is rounded as a whole, so the part of an increment that a segment was
+	// billed for past its end is carried into the next segment rather than billed
+	// again where the stay crosses midnight, a rate boundary or covered hours
+	var elapsed, billedTotal time.Duration
+	for _, segment := range segments {
+		duration := segment.end.Sub(segment.start)
+		elapsed += duration
+		increment, rounding := getBillingRules(segment.rate)
+		units := getBillableUnits(getUnbilled(elapsed, billedTotal), increment, rounding)
+		billedTotal += time.Duration(units*increment) * time.Minute
+		price, tiers := getTieredPrice(segment.rate, units, increment, billed[segment.rate.UUID])
+		billed[segment.rate.UUID] += units * increment
+		quote.Segments = append(quote.Segments, types.PriceSegment{
+			RateUUID:         segment.rate.UUID,
+			Start:            segment.start.In(startTime.Location()).Format(time.RFC3339),
+			End:              segment.end.In(startTime.Location()).Format(time.RFC3339),
+			Minutes:          int(duration / time.Minute),
+			BillingIncrement: increment,
+			Rounding:         rounding,
+			BillableUnits:    units,
+			Price:            price,
+			Tiers:            tiers,
+		})
+		quote.Days = addToDaySubtotals(quote.Days, segment.start.In(startTime.Location()), price)
+		quote.Total += price
+	}
+
+	quote = applyPriceLimits(quote, segments, lot, startTime.Location())
+	return quote, segments, err
+}
+
+// getGracedRatesQuote prices a stay like getRatesQuote and then applies the grace periods
+// of the rate that covers the start of the stay (entry grace and free minutes) or its end
+// (exit grace), or of the lot where that rate does not set them. A stay no longer than the
+// entry grace period is free, the free minutes at the start of a stay are not charged, and
+// an overrun of the last billing increment that is no longer than the exit grace period is
+// not charged. The stay is priced again without the time each grace period leaves out, so
+// each one is listed in the quote's adjustments with the amount it took off
+func getGracedRatesQuote(existingRates []types.Rate, tz string, lot types.Lot, startTime, endTime time.Time, covered []timespan) (types.Quote, error) {
+	quote, segments, err := getRatesQuote(existingRates, tz, lot, startTime, endTime, covered)
+	if err != nil || len(segments) == 0 {
+		return quote, err
+	}
+
+	first := segments[0]
+	loc := first.start.Location()
+	entryDate := startTime.In(loc).Format(effectiveDateLayout)
+	entryGrace, entrySource := getGracePeriod(first.rate.EntryGrace, lot.EntryGrace)
+	freeMinutes, freeSource := getGracePeriod(first.rate.FreeMinutes, lot.FreeMinutes)
+
+	if entryGrace > 0 && endTime.Sub(startTime) <= time.Duration(entryGrace)*time.Minute {
+		return getFreeQuote(quote, adjustEntryGrace, entrySource, first.rate.UUID, entryDate), nil
+	}
+
+	var adjustments []types.PriceAdjustment
+	if freeMinutes > 0 {
+		if endTime.Sub(startTime) <= time.Duration(freeMinutes)*time.Minute {
+			return getFreeQuote(quote, adjustFreeMinutes, freeSource, first.rate.UUID, entryDate), nil
+		}
+
+		full := quote.Total
+		startTime = startTime.Add(time.Duration(freeMinutes) * time.Minute)
+		if quote, segments, err = getRatesQuote(existingRates, tz, lot, startTime, endTime, covered); err != nil {
+			return quote, err
+		}
+		adjustments = appendGraceAdjustment(adjustments, adjustFreeMinutes, freeSource, first.rate.UUID, entryDate, quote.Total-full)
+	}
+
+	// a stay that ends in covered hours has no overrun to grace at its exit
+	if len(segments) > 0 && segments[len(segments)-1].end.Equal(endTime) {
+		last := segments[len(segments)-1]
+		if exitGrace, exitSource := getGracePeriod(last.rate.ExitGrace, lot.ExitGrace); exitGrace > 0 {
+			increment, _ := getBillingRules(last.rate)
+			// the last segment bills whatever the segments before it left unbilled
+			unbilled := getUnbilled(getSegmentsTime(segments), getBilledTime(quote.Segments[:len(quote.Segments)-1]))
+			overrun := unbilled % (time.Duration(increment) * time.Minute)
+			// only an overrun after a full increment is graced, so a stay shorter than one
+			// increment is still billed for it rather than repriced as an empty stay
+			if billed := unbilled - overrun; billed > 0 && overrun > 0 && overrun <= time.Duration(exitGrace)*time.Minute {
+				full := quote.Total
+				endTime = endTime.Add(-overrun)
+				if quote, _, err = getRatesQuote(existingRates, tz, lot, startTime, endTime, covered); err != nil {
+					return quote, err
+				}
+				adjustments = appendGraceAdjustment(adjustments, adjustExitGrace, exitSource, last.rate.UUID, endTime.In(loc).Format(effectiveDateLayout), quote.Total-full)
+			}
+		}
 	}
 
-	price = strconv.Itoa(matchedRate.Price)
-	return price, err
+	quote.Adjustments = append(quote.Adjustments, adjustments...)
+	return quote, nil
 }
@@ -0,0 +1,746 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+)
+
+// useMemoryStores points the app-wide config at fresh in-memory stores
+func useMemoryStores() {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func Test_CreateRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []types.CreateRateInput
+		in       types.CreateRateInput
+		wantErr  bool
+	}{
+		{
+			name: "Simple Passing Create",
+			in:   types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+		},
+		{
+			name:     "Overlap Error",
+			existing: []types.CreateRateInput{{Days: "fri", Times: "1500-1700", TZ: "America/Chicago", Price: 1000}},
+			in:       types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+			wantErr:  true,
+		},
+		{
+			name:     "Overlap In Other Lot Passing Create",
+			existing: []types.CreateRateInput{{Days: "fri", Times: "1500-1700", TZ: "America/Chicago", Price: 1000}},
+			in:       types.CreateRateInput{LotID: "garage", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+		},
+		{
+			name:     "Overlap In Same Lot Error",
+			existing: []types.CreateRateInput{{LotID: "garage", Days: "fri", Times: "1500-1700", TZ: "America/Chicago", Price: 1000}},
+			in:       types.CreateRateInput{LotID: "garage", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+			wantErr:  true,
+		},
+		{
+			name:     "Overlap In Other Vehicle Class Passing Create",
+			existing: []types.CreateRateInput{{Days: "fri", Times: "1500-1700", TZ: "America/Chicago", Price: 1000}},
+			in:       types.CreateRateInput{VehicleClass: "motorcycle", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 600},
+		},
+		{
+			name:     "Overlap In Same Vehicle Class Error",
+			existing: []types.CreateRateInput{{VehicleClass: "motorcycle", Days: "fri", Times: "1500-1700", TZ: "America/Chicago", Price: 500}},
+			in:       types.CreateRateInput{VehicleClass: "motorcycle", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 600},
+			wantErr:  true,
+		},
+		{
+			name:    "Invalid Vehicle Class Error",
+			in:      types.CreateRateInput{VehicleClass: "bicycle", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 600},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.CreateRateInput{LotID: "missing", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Input Error",
+			in:      types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if err := config.Config.Lots.Put(types.Lot{UUID: "garage", Name: "Garage"}); err != nil {
+				t.Fatalf("Lots.Put() setup error = %v", err)
+			}
+			for _, existing := range test.existing {
+				if _, err := CreateRate(&existing, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+
+			rate, err := CreateRate(&test.in, true, true)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreateRate() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			rates, _ := GetRates()
+			if want := len(test.existing); !test.wantErr {
+				if len(rates) != want+1 || !reflect.DeepEqual(rates[want], rate) {
+					t.Errorf("CreateRate() stored = %v, want %v as rate %d", rates, rate, want)
+				}
+			} else if len(rates) != want {
+				t.Errorf("CreateRate() stored %d rates, want %d", len(rates), want)
+			}
+		})
+	}
+}
+
+func Test_OverwriteRates(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []types.CreateRateInput
+		in        []types.CreateRateInput
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:     "Simple Passing Overwrite",
+			existing: []types.CreateRateInput{{Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500}},
+			in: []types.CreateRateInput{
+				{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+				{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500},
+			},
+			wantCount: 2,
+		},
+		{
+			name:      "Overlapping Input Error",
+			existing:  []types.CreateRateInput{{Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500}},
+			in:        []types.CreateRateInput{{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, {Days: "fri", Times: "1700-1900", TZ: "America/Chicago", Price: 500}},
+			wantCount: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Empty Input Error",
+			existing:  []types.CreateRateInput{{Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500}},
+			in:        []types.CreateRateInput{},
+			wantCount: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			for _, existing := range test.existing {
+				if _, err := CreateRate(&existing, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+
+			if _, err := OverwriteRates(&types.OverwriteRatesInput{Rates: &test.in}); (err != nil) != test.wantErr {
+				t.Errorf("OverwriteRates() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if rates, _ := GetRates(); len(rates) != test.wantCount {
+				t.Errorf("OverwriteRates() left %d rates, want %d", len(rates), test.wantCount)
+			}
+		})
+	}
+}
+
+func Test_UpdateRate(t *testing.T) {
+	existing := []types.CreateRateInput{
+		{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500},
+		{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+	}
+	tests := []struct {
+		name    string
+		uuid    string
+		ifMatch int
+		in      types.CreateRateInput
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Simple Passing Update",
+			in:   types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 900},
+			want: 900,
+		},
+		{
+			name: "Overlap With Itself Passes",
+			in:   types.CreateRateInput{Days: "fri", Times: "0800-1300", TZ: "America/Chicago", Price: 500},
+			want: 500,
+		},
+		{
+			name:    "Overlap With Another Rate Error",
+			in:      types.CreateRateInput{Days: "fri", Times: "0900-1700", TZ: "America/Chicago", Price: 500},
+			want:    500,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Input Error",
+			in:      types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago"},
+			want:    500,
+			wantErr: true,
+		},
+		{
+			name:    "Matching Version Update",
+			ifMatch: 1,
+			in:      types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 900},
+			want:    900,
+		},
+		{
+			name:    "Stale Version Error",
+			ifMatch: 2,
+			in:      types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 900},
+			want:    500,
+			wantErr: true,
+		},
+		{
+			name:    "Not Found Error",
+			uuid:    "missing",
+			in:      types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 900},
+			want:    500,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			var first types.Rate
+			for i, in := range existing {
+				rate, err := CreateRate(&in, true, true)
+				if err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+				if i == 0 {
+					first = rate
+				}
+			}
+
+			uuid := first.UUID
+			if test.uuid != "" {
+				uuid = test.uuid
+			}
+			if _, err := UpdateRate(uuid, test.ifMatch, &test.in); (err != nil) != test.wantErr {
+				t.Errorf("UpdateRate() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if got, _ := GetRate(first.UUID); got.Price != test.want {
+				t.Errorf("UpdateRate() left price %d, want %d", got.Price, test.want)
+			}
+		})
+	}
+}
+
+func Test_PatchRate(t *testing.T) {
+	price, times := 900, "1600-1800"
+	tests := []struct {
+		name    string
+		in      types.PatchRateInput
+		want    types.Rate
+		wantErr bool
+	}{
+		{
+			name: "Patch Price Only",
+			in:   types.PatchRateInput{Price: &price},
+			want: types.Rate{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 900, Version: 2},
+		},
+		{
+			name: "Patch Times Only",
+			in:   types.PatchRateInput{Times: &times},
+			want: types.Rate{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 500, Version: 2},
+		},
+		{
+			name: "Empty Patch",
+			in:   types.PatchRateInput{},
+			want: types.Rate{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500, Version: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			rate, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500}, true, true)
+			if err != nil {
+				t.Fatalf("CreateRate() setup error = %v", err)
+			}
+
+			got, err := PatchRate(rate.UUID, 0, &test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("PatchRate() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			test.want.UUID = rate.UUID
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("PatchRate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_DeleteRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		uuid      string
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:      "Simple Passing Delete",
+			wantCount: 0,
+		},
+		{
+			name:      "Not Found Error",
+			uuid:      "missing",
+			wantCount: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			rate, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500}, true, true)
+			if err != nil {
+				t.Fatalf("CreateRate() setup error = %v", err)
+			}
+
+			uuid := rate.UUID
+			if test.uuid != "" {
+				uuid = test.uuid
+			}
+			if _, err := DeleteRate(uuid, 0); (err != nil) != test.wantErr {
+				t.Errorf("DeleteRate() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if rates, _ := GetRates(); len(rates) != test.wantCount {
+				t.Errorf("DeleteRate() left %d rates, want %d", len(rates), test.wantCount)
+			}
+		})
+	}
+}
+
+func Test_GetTimespanPrice(t *testing.T) {
+	seed := []types.CreateRateInput{
+		{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+		{Days: "fri", Times: "0900-1200", TZ: "America/Chicago", Price: 500},
+		{Days: "fri", Times: "0600-0900", TZ: "America/Chicago", Price: 300},
+		{Days: "fri", Times: "2000-2400", TZ: "America/Chicago", Price: 700},
+		{Days: "sat", Times: "0000-0800", TZ: "America/Chicago", Price: 400},
+		{Days: "sun", Times: "0900-1700", TZ: "America/Chicago", Price: 1200, BillingIncrement: 15, Rounding: "down"},
+		{Days: "tues,wed", Times: "0000-2400", TZ: "America/Chicago", Price: 600},
+		{Days: "thurs", Times: "2200-0200", TZ: "America/Chicago", Price: 1000},
+		{Days: "mon", Times: "0900-1700", TZ: "America/Chicago", Price: 1000, EffectiveUntil: "2017-01-09"},
+		{Days: "mon", Times: "0900-1700", TZ: "America/Chicago", Price: 1500, EffectiveFrom: "2017-01-09"},
+		{Calendar: "holidays", Times: "1000-1700", TZ: "America/Chicago", Price: 200},
+		{Dates: "2017-01-23", Times: "0000-2400", TZ: "America/Chicago", Price: 0},
+		{Dates: "2017-01-20", Times: "1200-1300", TZ: "America/Chicago", Price: 0},
+		{LotID: "garage", Days: "fri", Times: "1300-1400", TZ: "America/Chicago", Price: 600},
+		{VehicleClass: "motorcycle", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 400},
+		{Days: "sat", Times: "0800-2000", TZ: "America/Chicago", Price: 100, Tiers: []types.PriceTier{{From: 0, Until: 60, Price: 400}, {From: 60, Until: 180, Price: 200}}},
+		{LotID: "grace", Days: "fri", Times: "0900-1700", TZ: "America/Chicago", Price: 500},
+		{LotID: "busy", Days: "fri", Times: "0900-1700", TZ: "America/Chicago", Price: 1000},
+		{LotID: "grace", Days: "sat", Times: "0900-1700", TZ: "America/Chicago", Price: 500, FreeMinutes: 60},
+		{LotID: "exit", Days: "fri", Times: "0000-2400", TZ: "America/Chicago", Price: 600},
+		{LotID: "capped", Days: "fri", Times: "0000-2400", TZ: "America/Chicago", Price: 600, DailyMax: 2500},
+	}
+	tests := []struct {
+		name         string
+		in           types.GetTimespanPriceInput
+		wantTotal    int
+		wantDays     int
+		wantSegments int
+		wantPermit   bool
+		wantErr      bool
+	}{
+		{
+			name:         "Simple Passing Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00")},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Consecutive Rates Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T08:00:00-06:00"), End: strPtr("2017-01-06T10:00:00-06:00")},
+			wantTotal:    800,
+			wantDays:     1,
+			wantSegments: 2,
+		},
+		{
+			name:         "Overnight Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T21:00:00-06:00"), End: strPtr("2017-01-07T07:00:00-06:00")},
+			wantTotal:    4900,
+			wantDays:     2,
+			wantSegments: 2,
+		},
+		{
+			name:         "Started Hour Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T16:00:00-06:00"), End: strPtr("2017-01-06T17:20:00-06:00")},
+			wantTotal:    3600,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Quarter Hour Rounded Down Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-08T09:00:00-06:00"), End: strPtr("2017-01-08T09:20:00-06:00")},
+			wantTotal:    300,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Same Day Hour Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-03T10:30:00-06:00"), End: strPtr("2017-01-03T11:30:00-06:00")},
+			wantTotal:    600,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Hour Across Midnight Rounded Once Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-03T23:30:00-06:00"), End: strPtr("2017-01-04T00:30:00-06:00")},
+			wantTotal:    600,
+			wantDays:     2,
+			wantSegments: 2,
+		},
+		{
+			name:         "Hour Across Rate Boundary Rounded Once Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T08:30:00-06:00"), End: strPtr("2017-01-06T09:30:00-06:00")},
+			wantTotal:    300,
+			wantDays:     1,
+			wantSegments: 2,
+		},
+		{
+			name:         "UTC Input Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T23:00:00Z"), End: strPtr("2017-01-07T00:00:00Z")},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Wrapped Rate Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-05T23:30:00-06:00"), End: strPtr("2017-01-06T00:15:00-06:00")},
+			wantTotal:    1000,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Price Before Scheduled Change",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-02T10:00:00-06:00"), End: strPtr("2017-01-02T11:00:00-06:00")},
+			wantTotal:    1000,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Price After Scheduled Change",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-09T10:00:00-06:00"), End: strPtr("2017-01-09T11:00:00-06:00")},
+			wantTotal:    1500,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Calendar Override Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-16T10:00:00-06:00"), End: strPtr("2017-01-16T11:00:00-06:00")},
+			wantTotal:    200,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Free Date Override Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-23T10:00:00-06:00"), End: strPtr("2017-01-23T11:00:00-06:00")},
+			wantTotal:    0,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Partial Day Override Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-20T12:00:00-06:00"), End: strPtr("2017-01-20T13:00:00-06:00")},
+			wantTotal:    0,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Weekday Rate Outside Partial Day Override Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-20T16:00:00-06:00"), End: strPtr("2017-01-20T17:00:00-06:00")},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Lot Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T13:00:00-06:00"), End: strPtr("2017-01-06T14:00:00-06:00"), LotID: "garage"},
+			wantTotal:    600,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:    "Other Lot Rate Unavailable Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), LotID: "garage"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), LotID: "missing"},
+			wantErr: true,
+		},
+		{
+			name:         "Tiered Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-07T09:00:00-06:00"), End: strPtr("2017-01-07T14:00:00-06:00")},
+			wantTotal:    1000,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Entry Grace Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T10:10:00-06:00"), LotID: "grace"},
+			wantTotal:    0,
+			wantDays:     1,
+			wantSegments: 0,
+		},
+		{
+			name:         "Exit Grace Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T12:05:00-06:00"), LotID: "grace"},
+			wantTotal:    1000,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Past Exit Grace Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T12:20:00-06:00"), LotID: "grace"},
+			wantTotal:    1500,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Shorter Than An Increment Exit Grace Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T10:05:00-06:00"), LotID: "exit"},
+			wantTotal:    600,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Free Minutes Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-07T10:00:00-06:00"), End: strPtr("2017-01-07T12:30:00-06:00"), LotID: "grace"},
+			wantTotal:    1000,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Surge Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T12:00:00-06:00"), LotID: "busy"},
+			wantTotal:    2500,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Promo Code Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), PromoCode: "first5"},
+			wantTotal:    1300,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Rejected Promo Code Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), PromoCode: "WEEKEND20"},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Permit Covered Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T16:00:00-06:00"), End: strPtr("2017-01-06T17:00:00-06:00"), Plate: "abc 123"},
+			wantTotal:    0,
+			wantDays:     0,
+			wantSegments: 0,
+			wantPermit:   true,
+		},
+		{
+			name:         "Partially Permitted Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T16:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), Plate: "ABC123"},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+			wantPermit:   true,
+		},
+		{
+			name:         "Permit Splitting A Capped Day Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T00:00:00-06:00"), End: strPtr("2017-01-06T23:00:00-06:00"), LotID: "capped", Plate: "CAP123"},
+			wantTotal:    2500,
+			wantDays:     1,
+			wantSegments: 2,
+			wantPermit:   true,
+		},
+		{
+			name:         "Other Plate Permit Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T16:00:00-06:00"), End: strPtr("2017-01-06T17:00:00-06:00"), Plate: "XYZ789"},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Expired Permit Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-13T16:00:00-06:00"), End: strPtr("2017-01-13T17:00:00-06:00"), Plate: "ABC123"},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Product Cheaper Than Rates Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-09T09:30:00-06:00"), End: strPtr("2017-01-09T16:00:00-06:00")},
+			wantTotal:    1200,
+			wantDays:     1,
+			wantSegments: 0,
+		},
+		{
+			name:         "Product For Stay Rates Cannot Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-09T08:30:00-06:00"), End: strPtr("2017-01-09T16:00:00-06:00")},
+			wantTotal:    1200,
+			wantDays:     1,
+			wantSegments: 0,
+		},
+		{
+			name:         "Rates Cheaper Than Product Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T08:00:00-06:00"), End: strPtr("2017-01-06T11:00:00-06:00")},
+			wantTotal:    1300,
+			wantDays:     1,
+			wantSegments: 2,
+		},
+		{
+			name:         "Vehicle Class Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), VehicleClass: "motorcycle"},
+			wantTotal:    400,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:         "Default Vehicle Class Fallback Price",
+			in:           types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), VehicleClass: "ev"},
+			wantTotal:    1800,
+			wantDays:     1,
+			wantSegments: 1,
+		},
+		{
+			name:    "Vehicle Class Rate Unavailable Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T10:00:00-06:00"), End: strPtr("2017-01-06T11:00:00-06:00"), VehicleClass: "motorcycle"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Vehicle Class Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T17:00:00-06:00"), End: strPtr("2017-01-06T18:00:00-06:00"), VehicleClass: "bicycle"},
+			wantErr: true,
+		},
+		{
+			name:    "Overridden Weekday Rate Unavailable Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-16T09:00:00-06:00"), End: strPtr("2017-01-16T10:00:00-06:00")},
+			wantErr: true,
+		},
+		{
+			name:    "Unavailable Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T13:00:00-06:00"), End: strPtr("2017-01-06T14:00:00-06:00")},
+			wantErr: true,
+		},
+		{
+			name:    "Partially Covered Error",
+			in:      types.GetTimespanPriceInput{Start: strPtr("2017-01-06T11:00:00-06:00"), End: strPtr("2017-01-06T17:00:00-06:00")},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Start Error",
+			in:      types.GetTimespanPriceInput{End: strPtr("2017-01-06T18:00:00-06:00")},
+			wantErr: true,
+		},
+	}
+
+	useMemoryStores()
+	if err := config.Config.Lots.Put(types.Lot{UUID: "garage", Name: "Garage"}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	if err := config.Config.Lots.Put(types.Lot{UUID: "grace", Name: "Grace", EntryGrace: 15, ExitGrace: 10}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	if err := config.Config.Lots.Put(types.Lot{UUID: "exit", Name: "Exit", ExitGrace: 10}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	promos := []types.PromoInput{
+		{Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago"},
+		{Code: "WEEKEND20", Type: "percent", Value: 20, Days: "sat,sun", TZ: "America/Chicago"},
+	}
+	for _, promo := range promos {
+		if _, err := CreatePromo(&promo); err != nil {
+			t.Fatalf("CreatePromo() setup error = %v", err)
+		}
+	}
+	busy := types.Lot{UUID: "busy", Name: "Busy", Capacity: 100, SurgeRules: []types.SurgeRule{{Above: 80, Increase: 25}}}
+	if err := config.Config.Lots.Put(busy); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	if _, err := ReportOccupancy(busy.UUID, &types.ReportOccupancyInput{Occupied: intPtr(90)}); err != nil {
+		t.Fatalf("ReportOccupancy() setup error = %v", err)
+	}
+	if _, err := PutCalendar("holidays", &types.PutCalendarInput{Dates: []string{"2017-01-16"}}); err != nil {
+		t.Fatalf("PutCalendar() setup error = %v", err)
+	}
+	products := []types.ProductInput{
+		{Name: "Early Bird", Days: "mon", EntryWindow: "0000-1000", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200},
+		{Name: "Morning", Days: "fri", EntryWindow: "0600-0900", ExitWindow: "1000-1200", TZ: "America/Chicago", Price: 5000},
+	}
+	for _, product := range products {
+		if _, err := CreateProduct(&product); err != nil {
+			t.Fatalf("CreateProduct() setup error = %v", err)
+		}
+	}
+	if err := config.Config.Lots.Put(types.Lot{UUID: "capped", Name: "Capped"}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	if _, err := CreatePermit(&types.PermitInput{Holder: "John Doe", Plates: []string{"CAP123"}, LotIDs: []string{"capped"}, Days: "fri", Times: "1100-1200", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-01-13"}); err != nil {
+		t.Fatalf("CreatePermit() setup error = %v", err)
+	}
+	if _, err := CreatePermit(&types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", Times: "1600-1700", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-01-13"}); err != nil {
+		t.Fatalf("CreatePermit() setup error = %v", err)
+	}
+	if _, err := OverwriteRates(&types.OverwriteRatesInput{Rates: &seed}); err != nil {
+		t.Fatalf("OverwriteRates() setup error = %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := GetTimespanPrice(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetTimespanPrice() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if got.Total != test.wantTotal || len(got.Days) != test.wantDays || len(got.Segments) != test.wantSegments || (got.Permit != nil) != test.wantPermit {
+				t.Errorf("GetTimespanPrice() got = %+v, want total %d over %d days and %d segments, permit %v", got, test.wantTotal, test.wantDays, test.wantSegments, test.wantPermit)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package helpers
+
+import (
+	"bytes"
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+)
+
+// taxRateUnit is a tax rate of 100%, since tax rates are in hundredths of a percent
+const taxRateUnit = 10000
+
+// receiptTimeLayout is how times are shown in the lines of a receipt
+const receiptTimeLayout = "Mon Jan 2 15:04"
+
+// adjustmentLabels describe the types of price adjustments on receipts
+var adjustmentLabels = map[string]string{
+	adjustDailyMax:    "Daily maximum",
+	adjustMinCharge:   "Minimum charge",
+	adjustEntryGrace:  "Entry grace period",
+	adjustExitGrace:   "Exit grace period",
+	adjustFreeMinutes: "Free minutes",
+	adjustPromo:       "Promo",
+	adjustValidation:  "Validation",
+}
+
+// receiptAttempts is how many times issuing a receipt is tried when another receipt
+// is issued in the same lot first
+const receiptAttempts = 5
+
+// GetSessionReceipt gets the receipt of the session with the given uuid from the DB. It
+// errors with ErrNotFound unless the session was issued a receipt
+func GetSessionReceipt(uuid string) (types.Receipt, error) {
+	session, err := GetSession(uuid)
+	if err != nil {
+		return types.Receipt{}, err
+	}
+
+	if session.Receipt == nil {
+		return types.Receipt{}, fmt.Errorf("session %s has no receipt, only stays closed with a fee do: %w", uuid, store.ErrNotFound)
+	}
+	return *session.Receipt, nil
+}
+
+// issueReceipt closes the active session closed with the next receipt number of its lot,
+// trying again with the number after that when another receipt is issued in the lot first
+func issueReceipt(closed types.Session) (types.Session, error) {
+	var (
+		err  error
+		lot  types.Lot
+		last int
+	)
+
+	if closed.LotID != "" {
+		if lot, err = GetLot(closed.LotID); err != nil {
+			return closed, fmt.Errorf("could not find lot %s: %v", closed.LotID, err)
+		}
+	}
+
+	for attempt := 0; attempt < receiptAttempts; attempt++ {
+		if last, err = config.Config.Sessions.LastReceipt(closed.LotID); err != nil {
+			return closed, err
+		}
+
+		receipt := buildReceipt(closed, lot, last+1, time.Now())
+		closed.Receipt = &receipt
+		if err = config.Config.Sessions.Issue(closed, sessionActive); !errors.Is(err, store.ErrConflict) {
+			return closed, err
+		}
+
+		// the session may have been ended or voided by another request instead
+		if current, err := GetSession(closed.UUID); err != nil || current.State != sessionActive {
+			return closed, store.ErrConflict
+		}
+	}
+	return closed, store.ErrConflict
+}
+
+// RenderReceiptText renders a receipt as plain text
+func RenderReceiptText(receipt types.Receipt) string {
+	var (
+		b     strings.Builder
+		lines []types.ReceiptLine
+	)
+
+	fmt.Fprintf(&b, "RECEIPT %s\n", formatReceiptNumber(receipt.Number))
+	for _, line := range []string{receipt.LotName, receipt.LotAddress} {
+		if line != "" {
+			b.WriteString(line + "\n")
+		}
+	}
+	fmt.Fprintf(&b, "Plate:   %s\nEntered: %s\nExited:  %s\nIssued:  %s\n\n", receipt.Plate, formatReceiptTime(receipt.EntryTime), formatReceiptTime(receipt.ExitTime), formatReceiptTime(receipt.IssuedAt))
+
+	lines = append(lines, receipt.Charges...)
+	lines = append(lines, receipt.Adjustments...)
+	totals := []types.ReceiptLine{{Description: "Subtotal", Amount: receipt.Subtotal}}
+	for _, tax := range receipt.Taxes {
+		totals = append(totals, types.ReceiptLine{Description: fmt.Sprintf("%s (%s)", tax.Name, formatTaxRate(tax.Rate)), Amount: tax.Amount})
+	}
+	totals = append(totals, types.ReceiptLine{Description: "Total", Amount: receipt.Total})
+
+	// descriptions are left aligned and amounts right aligned in two columns
+	width, amountWidth := 0, 0
+	for _, line := range append(append([]types.ReceiptLine{}, lines...), totals...) {
+		if len(line.Description) > width {
+			width = len(line.Description)
+		}
+		if len(formatCents(line.Amount)) > amountWidth {
+			amountWidth = len(formatCents(line.Amount))
+		}
+	}
+	for _, line := range lines {
+		fmt.Fprintf(&b, "%-*s  %*s\n", width, line.Description, amountWidth, formatCents(line.Amount))
+	}
+	b.WriteString(strings.Repeat("-", width+2+amountWidth) + "\n")
+	for _, line := range totals {
+		fmt.Fprintf(&b, "%-*s  %*s\n", width, line.Description, amountWidth, formatCents(line.Amount))
+	}
+	return b.String()
+}
+
+// receiptHTML is the printable HTML template of a receipt
+var receiptHTML = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"cents":  formatCents,
+	"number": formatReceiptNumber,
+	"rate":   formatTaxRate,
+	"time":   formatReceiptTime,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt {{number .Number}}</title>
+<style>
+body { font-family: sans-serif; max-width: 32em; margin: 2em auto; }
+table { width: 100%; border-collapse: collapse; }
+td.amount { text-align: right; }
+tr.total td { border-top: 1px solid #000; font-weight: bold; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Receipt {{number .Number}}</h1>
+{{if .LotName}}<p>{{.LotName}}{{with .LotAddress}}<br>{{.}}{{end}}</p>
+{{end}}<p>Plate: {{.Plate}}<br>Entered: {{time .EntryTime}}<br>Exited: {{time .ExitTime}}<br>Issued: {{time .IssuedAt}}</p>
+<table>
+{{range .Charges}}<tr><td>{{.Description}}</td><td class="amount">{{cents .Amount}}</td></tr>
+{{end}}{{range .Adjustments}}<tr><td>{{.Description}}</td><td class="amount">{{cents .Amount}}</td></tr>
+{{end}}<tr class="total"><td>Subtotal</td><td class="amount">{{cents .Subtotal}}</td></tr>
+{{range .Taxes}}<tr><td>{{.Name}} ({{rate .Rate}})</td><td class="amount">{{cents .Amount}}</td></tr>
+{{end}}<tr class="total"><td>Total</td><td class="amount">{{cents .Total}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// RenderReceiptHTML renders a receipt as a printable HTML page
+func RenderReceiptHTML(receipt types.Receipt) (string, error) {
+	var b bytes.Buffer
+	err := receiptHTML.Execute(&b, receipt)
+	return b.String(), err
+}
+
+// buildReceipt itemizes the quote of a session closed with a fee in lot as receipt
+// number, issued at issuedAt. Sessions without a lot are given an empty lot
+func buildReceipt(session types.Session, lot types.Lot, number int, issuedAt time.Time) types.Receipt {
+	receipt := types.Receipt{
+		Number:     number,
+		LotID:      lot.UUID,
+		LotName:    lot.Name,
+		LotAddress: lot.Address,
+		Plate:      session.Plate,
+		EntryTime:  session.EntryTime,
+		ExitTime:   session.ExitTime,
+		IssuedAt:   issuedAt.Format(time.RFC3339),
+		Total:      session.Fee,
+	}
+
+	if quote := session.Quote; quote != nil {
+		if quote.Product != nil {
+			receipt.Charges = append(receipt.Charges, types.ReceiptLine{Description: "Product " + quote.Product.Name, Amount: quote.Product.Price})
+		}
+		for _, segment := range quote.Segments {
+			description := fmt.Sprintf("Parking %s - %s (%d min)", formatReceiptTime(segment.Start), formatReceiptTime(segment.End), segment.Minutes)
+			if quote.Surge != nil {
+				description += fmt.Sprintf(", surge +%d%%", quote.Surge.Increase)
+			}
+			receipt.Charges = append(receipt.Charges, types.ReceiptLine{Description: description, Amount: segment.Price})
+		}
+		for _, adjustment := range quote.Adjustments {
+			description := adjustmentLabels[adjustment.Type]
+			if adjustment.Type == adjustPromo && quote.Promo != nil {
+				description += " " + quote.Promo.Code
+			} else if adjustment.Code != "" {
+				description += " " + adjustment.Code
+			}
+			receipt.Adjustments = append(receipt.Adjustments, types.ReceiptLine{Description: description + " on " + adjustment.Date, Amount: adjustment.Amount})
+		}
+	}
+
+	receipt.Subtotal, receipt.Taxes = getIncludedTaxes(session.Fee, lot.Taxes)
+	return receipt
+}
+
+// getIncludedTaxes splits a total that includes taxes into the subtotal without them and
+// the amount of each tax, rounded to the cent. The last tax takes any rounding left over
+// so that the subtotal and the taxes always add up to the total
+func getIncludedTaxes(total int, taxes []types.Tax) (int, []types.ReceiptTax) {
+	if len(taxes) == 0 {
+		return total, nil
+	}
+
+	rate := 0
+	for _, tax := range taxes {
+		rate += tax.Rate
+	}
+	subtotal := int(math.Round(float64(total) * taxRateUnit / float64(taxRateUnit+rate)))
+
+	var (
+		receiptTaxes = make([]types.ReceiptTax, len(taxes))
+		left         = total - subtotal
+	)
+	for i, tax := range taxes {
+		amount := left
+		if i < len(taxes)-1 {
+			amount = int(math.Round(float64(subtotal) * float64(tax.Rate) / taxRateUnit))
+		}
+		receiptTaxes[i] = types.ReceiptTax{Name: tax.Name, Rate: tax.Rate, Amount: amount}
+		left -= amount
+	}
+	return subtotal, receiptTaxes
+}
+
+// formatReceiptTime formats a time in the format "2017-01-06T17:00:00-06:00" for a
+// receipt, or returns it as is if it does not parse
+func formatReceiptTime(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format(receiptTimeLayout)
+}
+
+// formatReceiptNumber formats a receipt number with leading zeros, such as "000042"
+func formatReceiptNumber(number int) string {
+	return fmt.Sprintf("%06d", number)
+}
+
+// formatCents formats an amount in cents as dollars, such as "$18.00" or "-$5.25"
+func formatCents(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, cents/100, cents%100)
+}
+
+// formatTaxRate formats a tax rate in hundredths of a percent, such as "8.25%"
+func formatTaxRate(rate int) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%d.%02d", rate/100, rate%100), "0"), ".") + "%"
+}
@@ -0,0 +1,223 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_GetSessionReceipt(t *testing.T) {
+	tests := []struct {
+		name       string
+		lot        string
+		promoCode  string
+		wantNumber int
+		wantErr    bool
+	}{
+		{
+			name:       "First Receipt In Lot",
+			lot:        "garage",
+			wantNumber: 1,
+		},
+		{
+			name:       "Next Receipt Without Lot",
+			wantNumber: 2,
+		},
+		{
+			name:      "Free Stay Error",
+			promoCode: "FREE",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot, err := CreateLot(&types.LotInput{Name: "Garage", Taxes: []types.Tax{{Name: "Sales tax", Rate: 1000}}})
+			if err != nil {
+				t.Fatalf("CreateLot() setup error = %v", err)
+			}
+			for _, lotID := range []string{"", lot.UUID} {
+				if _, err = CreateRate(&types.CreateRateInput{LotID: lotID, Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+					t.Fatalf("CreateRate() setup error = %v", err)
+				}
+			}
+			if _, err = CreatePromo(&types.PromoInput{Code: "FREE", Type: "percent", Value: 100, TZ: "America/Chicago"}); err != nil {
+				t.Fatalf("CreatePromo() setup error = %v", err)
+			}
+			// the first receipt without a lot
+			earlier, err := StartSession(&types.StartSessionInput{Plate: "XYZ789", EntryTime: "2017-01-06T16:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+			if _, err = EndSession(earlier.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T17:00:00-06:00"}); err != nil {
+				t.Fatalf("EndSession() setup error = %v", err)
+			}
+
+			in := types.StartSessionInput{Plate: "ABC123", EntryTime: "2017-01-06T17:00:00-06:00"}
+			if test.lot == "garage" {
+				in.LotID = lot.UUID
+			}
+			session, err := StartSession(&in)
+			if err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+			if _, err = EndSession(session.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00", PromoCode: test.promoCode}); err != nil {
+				t.Fatalf("EndSession() setup error = %v", err)
+			}
+
+			got, err := GetSessionReceipt(session.UUID)
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetSessionReceipt() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.Number != test.wantNumber || got.Total != 1800 || got.LotID != in.LotID) {
+				t.Errorf("GetSessionReceipt() = %v, want receipt %d for 1800 in lot %s", got, test.wantNumber, in.LotID)
+			}
+		})
+	}
+}
+
+func Test_RenderReceipt(t *testing.T) {
+	receipt := types.Receipt{
+		Number:      42,
+		LotName:     "Main <Street> Garage",
+		Plate:       "ABC123",
+		EntryTime:   "2017-01-06T17:00:00-06:00",
+		ExitTime:    "2017-01-06T18:00:00-06:00",
+		IssuedAt:    "2017-01-06T18:00:05-06:00",
+		Charges:     []types.ReceiptLine{{Description: "Parking Fri Jan 6 17:00 - Fri Jan 6 18:00 (60 min)", Amount: 1800}},
+		Adjustments: []types.ReceiptLine{{Description: "Promo FIRST5 on 2017-01-06", Amount: -500}},
+		Subtotal:    1201,
+		Taxes:       []types.ReceiptTax{{Name: "Sales tax", Rate: 825, Amount: 99}},
+		Total:       1300,
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: "Paid Receipt",
+			want: []string{"000042", "Main", "ABC123", "Fri Jan 6 17:00", "$18.00", "-$5.00", "$12.01", "Sales tax (8.25%)", "$0.99", "$13.00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := RenderReceiptText(receipt)
+			html, err := RenderReceiptHTML(receipt)
+			if err != nil {
+				t.Errorf("RenderReceiptHTML() error = %v", err)
+				return
+			}
+
+			for _, want := range test.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("RenderReceiptText() = %s, want it to contain %s", text, want)
+				}
+				if !strings.Contains(html, want) {
+					t.Errorf("RenderReceiptHTML() = %s, want it to contain %s", html, want)
+				}
+			}
+			if !strings.Contains(html, "Main &lt;Street&gt; Garage") {
+				t.Errorf("RenderReceiptHTML() = %s, want the lot name escaped", html)
+			}
+		})
+	}
+}
+
+func Test_buildReceipt(t *testing.T) {
+	session := types.Session{
+		Plate:     "ABC123",
+		EntryTime: "2017-01-06T17:00:00-06:00",
+		ExitTime:  "2017-01-06T18:00:00-06:00",
+		Fee:       1300,
+		Quote: &types.Quote{
+			Total:       1300,
+			Segments:    []types.PriceSegment{{Start: "2017-01-06T17:00:00-06:00", End: "2017-01-06T18:00:00-06:00", Minutes: 60, Price: 1800}},
+			Adjustments: []types.PriceAdjustment{{Type: adjustPromo, Source: sourcePromo, Date: "2017-01-06", Amount: -500}},
+			Promo:       &types.QuotedPromo{Code: "FIRST5", Subtotal: 1800, Discount: 500},
+		},
+	}
+	lot := types.Lot{UUID: "garage", Name: "Garage", Taxes: []types.Tax{{Name: "Sales tax", Rate: 825}}}
+	issuedAt, _ := time.Parse(time.RFC3339, "2017-01-06T18:00:05-06:00")
+	want := types.Receipt{
+		Number:      7,
+		LotID:       "garage",
+		LotName:     "Garage",
+		Plate:       "ABC123",
+		EntryTime:   "2017-01-06T17:00:00-06:00",
+		ExitTime:    "2017-01-06T18:00:00-06:00",
+		IssuedAt:    "2017-01-06T18:00:05-06:00",
+		Charges:     []types.ReceiptLine{{Description: "Parking Fri Jan 6 17:00 - Fri Jan 6 18:00 (60 min)", Amount: 1800}},
+		Adjustments: []types.ReceiptLine{{Description: "Promo FIRST5 on 2017-01-06", Amount: -500}},
+		Subtotal:    1201,
+		Taxes:       []types.ReceiptTax{{Name: "Sales tax", Rate: 825, Amount: 99}},
+		Total:       1300,
+	}
+
+	if got := buildReceipt(session, lot, 7, issuedAt); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildReceipt() = %v, want %v", got, want)
+	}
+}
+
+func Test_getIncludedTaxes(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		taxes        []types.Tax
+		wantSubtotal int
+		wantTaxes    []types.ReceiptTax
+	}{
+		{
+			name:         "No Taxes",
+			total:        1800,
+			wantSubtotal: 1800,
+		},
+		{
+			name:         "One Tax",
+			total:        1100,
+			taxes:        []types.Tax{{Name: "Sales tax", Rate: 1000}},
+			wantSubtotal: 1000,
+			wantTaxes:    []types.ReceiptTax{{Name: "Sales tax", Rate: 1000, Amount: 100}},
+		},
+		{
+			name:         "Rounding Left To Last Tax",
+			total:        1000,
+			taxes:        []types.Tax{{Name: "State", Rate: 625}, {Name: "City", Rate: 200}},
+			wantSubtotal: 924,
+			wantTaxes:    []types.ReceiptTax{{Name: "State", Rate: 625, Amount: 58}, {Name: "City", Rate: 200, Amount: 18}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subtotal, taxes := getIncludedTaxes(test.total, test.taxes)
+			if subtotal != test.wantSubtotal || !reflect.DeepEqual(taxes, test.wantTaxes) {
+				t.Errorf("getIncludedTaxes() = %d, %v, want %d, %v", subtotal, taxes, test.wantSubtotal, test.wantTaxes)
+			}
+		})
+	}
+}
+
+func Test_formatCents(t *testing.T) {
+	tests := []struct {
+		cents int
+		want  string
+	}{
+		{cents: 1800, want: "$18.00"},
+		{cents: 5, want: "$0.05"},
+		{cents: -525, want: "-$5.25"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if got := formatCents(test.cents); got != test.want {
+				t.Errorf("formatCents() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
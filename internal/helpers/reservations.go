@@ -0,0 +1,346 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// reservation states
+const (
+	reservationBooked    = "booked"
+	reservationCancelled = "cancelled"
+)
+
+// maxReservationLength is the longest stay that may be reserved
+const maxReservationLength = 24 * time.Hour
+
+// GetReservations gets all of the reservations from the DB
+func GetReservations() ([]types.Reservation, error) {
+	return config.Config.Reservations.All()
+}
+
+// GetReservation gets the reservation with the given uuid from the DB
+func GetReservation(uuid string) (types.Reservation, error) {
+	return config.Config.Reservations.Get(uuid)
+}
+
+// CreateReservation books a space in a lot at the price that GetTimespanPrice quotes for
+// the stay. Every hour of the stay must have a space left within the lot's capacity
+func CreateReservation(in *types.CreateReservationInput) (types.Reservation, error) {
+	var (
+		err                error
+		reservation        types.Reservation
+		lot                types.Lot
+		startTime, endTime time.Time
+		holds              map[string]int
+		quote              types.Quote
+	)
+
+	if in.LotID == "" {
+		return reservation, errors.New("specify a lot to reserve a space in")
+	}
+
+	if lot, err = GetLot(in.LotID); err != nil {
+		return reservation, fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+	}
+
+	if startTime, endTime, err = validateReservationInput(in.Plate, in.VehicleClass, in.Start, in.End, time.Now()); err != nil {
+		return reservation, err
+	}
+
+	if holds, err = getSlotHolds(lot, startTime, endTime); err != nil {
+		return reservation, err
+	}
+
+	if quote, err = quoteReservation(lot.UUID, in.VehicleClass, in.Start, in.End); err != nil {
+		return reservation, err
+	}
+
+	uu, _ := uuid.NewV4()
+	reservation = types.Reservation{
+		UUID:         uu.String(),
+		LotID:        lot.UUID,
+		Plate:        normalizePlate(in.Plate),
+		VehicleClass: in.VehicleClass,
+		Start:        in.Start,
+		End:          in.End,
+		State:        reservationBooked,
+		Price:        quote.Total,
+		Quote:        &quote,
+		Version:      1,
+		CreatedAt:    time.Now().Unix(),
+	}
+	reservation.UpdatedAt = reservation.CreatedAt
+
+	err = config.Config.Reservations.Write(reservation, 0, holds, nil)
+	return reservation, err
+}
+
+// ModifyReservation changes the plate, vehicle class and times of the booked reservation
+// with the given uuid, repricing it. Changing it within one of the lot's cancellation rules
+// charges the fee that cancelling it would, which is returned along with the reservation
+func ModifyReservation(uuid string, in *types.ModifyReservationInput) (types.Reservation, int, error) {
+	var (
+		err                error
+		reservation        types.Reservation
+		lot                types.Lot
+		startTime, endTime time.Time
+		holds              map[string]int
+		quote              types.Quote
+	)
+
+	now := time.Now()
+	if reservation, lot, err = getChangeableReservation(uuid, now); err != nil {
+		return reservation, 0, err
+	}
+
+	if startTime, endTime, err = validateReservationInput(in.Plate, in.VehicleClass, in.Start, in.End, now); err != nil {
+		return reservation, 0, err
+	}
+
+	if holds, err = getSlotHolds(lot, startTime, endTime); err != nil {
+		return reservation, 0, err
+	}
+
+	if quote, err = quoteReservation(lot.UUID, in.VehicleClass, in.Start, in.End); err != nil {
+		return reservation, 0, err
+	}
+
+	oldStart, _ := time.Parse(time.RFC3339, reservation.Start)
+	oldEnd, _ := time.Parse(time.RFC3339, reservation.End)
+	hold, release := diffSlots(holds, getReservationSlots(lot.UUID, oldStart, oldEnd))
+	fee := getCancellationFee(lot, reservation, now)
+
+	modified := reservation
+	modified.Plate = normalizePlate(in.Plate)
+	modified.VehicleClass = in.VehicleClass
+	modified.Start = in.Start
+	modified.End = in.End
+	modified.Price = quote.Total
+	modified.Quote = &quote
+	modified.Fees += fee
+	modified.Version++
+	modified.UpdatedAt = now.Unix()
+
+	if err = config.Config.Reservations.Write(modified, reservation.Version, hold, release); err != nil {
+		return reservation, 0, err
+	}
+	return modified, fee, nil
+}
+
+// CancelReservation cancels the booked reservation with the given uuid, giving back the
+// spaces it held. The fee for cancelling it is returned along with the reservation
+func CancelReservation(uuid string) (types.Reservation, int, error) {
+	var (
+		err         error
+		reservation types.Reservation
+		lot         types.Lot
+	)
+
+	now := time.Now()
+	if reservation, lot, err = getChangeableReservation(uuid, now); err != nil {
+		return reservation, 0, err
+	}
+
+	startTime, _ := time.Parse(time.RFC3339, reservation.Start)
+	endTime, _ := time.Parse(time.RFC3339, reservation.End)
+	fee := getCancellationFee(lot, reservation, now)
+
+	cancelled := reservation
+	cancelled.State = reservationCancelled
+	cancelled.Fees += fee
+	cancelled.Version++
+	cancelled.UpdatedAt = now.Unix()
+
+	if err = config.Config.Reservations.Write(cancelled, reservation.Version, nil, getReservationSlots(lot.UUID, startTime, endTime)); err != nil {
+		return reservation, 0, err
+	}
+	return cancelled, fee, nil
+}
+
+// getChangeableReservation gets the reservation with the given uuid and its lot, and
+// errors unless it is booked and has not started at now
+func getChangeableReservation(uuid string, now time.Time) (types.Reservation, types.Lot, error) {
+	var (
+		err         error
+		reservation types.Reservation
+		lot         types.Lot
+	)
+
+	if reservation, err = GetReservation(uuid); err != nil {
+		return reservation, lot, err
+	}
+
+	if reservation.State != reservationBooked {
+		return reservation, lot, fmt.Errorf("reservation %s is %s, only booked reservations can be changed", uuid, reservation.State)
+	}
+
+	startTime, err := time.Parse(time.RFC3339, reservation.Start)
+	if err != nil {
+		return reservation, lot, fmt.Errorf("could not parse reservation %s start %s: %v", uuid, reservation.Start, err)
+	}
+	if !now.Before(startTime) {
+		return reservation, lot, fmt.Errorf("reservation %s has already started", uuid)
+	}
+
+	if lot, err = GetLot(reservation.LotID); err != nil {
+		return reservation, lot, fmt.Errorf("could not find lot %s: %v", reservation.LotID, err)
+	}
+	return reservation, lot, nil
+}
+
+// quoteReservation quotes a stay in the lot with the given lotID for a reservation
+func quoteReservation(lotID, vehicleClass, start, end string) (types.Quote, error) {
+	quote, err := GetTimespanPrice(&types.GetTimespanPriceInput{
+		Start:        &start,
+		End:          &end,
+		LotID:        lotID,
+		VehicleClass: vehicleClass,
+	})
+	if err != nil {
+		return quote, fmt.Errorf("could not price reservation: %v", err)
+	}
+	return quote, nil
+}
+
+// getSlotHours returns the first hour and the hour after the last of the times of a
+// reservation slot, which must be whole hours of one day, such as "0800-2400"
+func getSlotHours(times string) (from, until int, err error) {
+	var (
+		timesSlice     []string
+		earlier, later time.Time
+	)
+
+	if timesSlice, err = timeSpanAsSlice(times); err != nil {
+		return from, until, err
+	}
+	if earlier, later, err = getTimeObjectsFromTimes(timesSlice); err != nil {
+		return from, until, err
+	}
+	if earlier.Minute() != 0 || later.Minute() != 0 {
+		return from, until, fmt.Errorf("reservation slot times must be whole hours: %s", times)
+	}
+	if later.Day() > 1 && timesSlice[1] != endOfDay {
+		return from, until, fmt.Errorf("reservation slot times cannot wrap past midnight: %s", times)
+	}
+
+	from, until = earlier.Hour(), later.Hour()
+	if timesSlice[1] == endOfDay {
+		until = 24
+	}
+	return from, until, nil
+}
+
+// getSlotCapacity returns the number of spaces that may be reserved in lot in the hour
+// starting at hour. The first of the lot's reservation slots that covers the hour, on the
+// wall clock of its timezone, sets the capacity, and otherwise the lot's reservation capacity does
+func getSlotCapacity(lot types.Lot, hour time.Time) int {
+	for _, slot := range lot.ReservationSlots {
+		loc, err := time.LoadLocation(slot.TZ)
+		if err != nil {
+			log.Errorf("Could not load lot %s reservation slot timezone %s: %v", lot.UUID, slot.TZ, err)
+			continue
+		}
+		from, until, err := getSlotHours(slot.Times)
+		if err != nil {
+			log.Errorf("Could not parse lot %s reservation slot times %s: %v", lot.UUID, slot.Times, err)
+			continue
+		}
+
+		wall := hour.In(loc)
+		day, _ := weekdayToDay(wall.Weekday())
+		if strings.Contains(slot.Days, day) && wall.Hour() >= from && wall.Hour() < until {
+			return slot.Capacity
+		}
+	}
+	return lot.ReservationCapacity
+}
+
+// getReservationSlot returns the slot of the lot with the given lotID for the hour starting at hour
+func getReservationSlot(lotID string, hour time.Time) string {
+	return lotID + "#" + hour.UTC().Format(time.RFC3339)
+}
+
+// getReservationSlots returns the slots, one for each hour in UTC, that a reservation in
+// the lot with the given lotID from startTime to endTime holds a space in
+func getReservationSlots(lotID string, startTime, endTime time.Time) []string {
+	var slots []string
+	for hour := startTime.UTC().Truncate(time.Hour); hour.Before(endTime); hour = hour.Add(time.Hour) {
+		slots = append(slots, getReservationSlot(lotID, hour))
+	}
+	return slots
+}
+
+// getSlotHolds maps the slots that a reservation in lot from startTime to endTime holds
+// a space in to their capacity, and errors if the lot takes no reservations in one of them
+func getSlotHolds(lot types.Lot, startTime, endTime time.Time) (map[string]int, error) {
+	holds := make(map[string]int)
+	for hour := startTime.UTC().Truncate(time.Hour); hour.Before(endTime); hour = hour.Add(time.Hour) {
+		capacity := getSlotCapacity(lot, hour)
+		if capacity == 0 {
+			return nil, fmt.Errorf("lot %s does not take reservations at %s", lot.UUID, hour.Format(time.RFC3339))
+		}
+		holds[getReservationSlot(lot.UUID, hour)] = capacity
+	}
+	return holds, nil
+}
+
+// diffSlots returns the holds that are not already held, and the held slots that are not in holds
+func diffSlots(holds map[string]int, held []string) (map[string]int, []string) {
+	var (
+		hold    = make(map[string]int)
+		release []string
+		isHeld  = make(map[string]bool)
+	)
+
+	for _, slot := range held {
+		isHeld[slot] = true
+		if _, kept := holds[slot]; !kept {
+			release = append(release, slot)
+		}
+	}
+	for slot, capacity := range holds {
+		if !isHeld[slot] {
+			hold[slot] = capacity
+		}
+	}
+	return hold, release
+}
+
+// getCancellationFee returns what cancelling or changing reservation in lot at now costs.
+// Each of the lot's cancellation rules that now is within Before hours of the start of the
+// reservation replaces the fee of the rule before it, so the rule closest to the start applies
+func getCancellationFee(lot types.Lot, reservation types.Reservation, now time.Time) int {
+	startTime, err := time.Parse(time.RFC3339, reservation.Start)
+	if err != nil {
+		log.Errorf("Could not parse reservation %s start %s: %v", reservation.UUID, reservation.Start, err)
+		return 0
+	}
+
+	percent := 0
+	for _, rule := range lot.CancellationRules {
+		if startTime.Sub(now) < time.Duration(rule.Before)*time.Hour {
+			percent = rule.Fee
+		}
+	}
+	return reservation.Price * percent / 100
+}
+
+// bookedReservationsForLot returns the reservations in the lot with the given lotID that
+// are still booked
+func bookedReservationsForLot(reservations []types.Reservation, lotID string) []types.Reservation {
+	var booked []types.Reservation
+	for _, reservation := range reservations {
+		if reservation.State == reservationBooked && reservation.LotID == lotID {
+			booked = append(booked, reservation)
+		}
+	}
+	return booked
+}
@@ -0,0 +1,389 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// createReservationLot creates a lot with a fri rate that takes one reservation in each
+// hour, or two from 4pm to 5pm, with the given cancellation rules
+func createReservationLot(t *testing.T, rules []types.CancellationRule) types.Lot {
+	lot, err := CreateLot(&types.LotInput{
+		Name:                "Garage",
+		ReservationCapacity: 1,
+		ReservationSlots:    []types.ReservationSlot{{Days: "fri", Times: "1600-1700", TZ: "America/Chicago", Capacity: 2}},
+		CancellationRules:   rules,
+	})
+	if err != nil {
+		t.Fatalf("CreateLot() setup error = %v", err)
+	}
+	if _, err = CreateRate(&types.CreateRateInput{LotID: lot.UUID, Days: "fri", Times: "1600-2000", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+		t.Fatalf("CreateRate() setup error = %v", err)
+	}
+	return lot
+}
+
+func Test_CreateReservation(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        types.CreateReservationInput
+		wantPrice int
+		wantErr   bool
+	}{
+		{
+			name:      "Simple Passing Create",
+			in:        types.CreateReservationInput{LotID: "garage", Plate: "abc 123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"},
+			wantPrice: 1800,
+		},
+		{
+			name:    "Full Slot Error",
+			in:      types.CreateReservationInput{LotID: "garage", Plate: "ABC123", Start: "2031-01-03T16:30:00-06:00", End: "2031-01-03T17:30:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.CreateReservationInput{Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Plate Error",
+			in:      types.CreateReservationInput{LotID: "garage", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Past Start Error",
+			in:      types.CreateReservationInput{LotID: "garage", Plate: "ABC123", Start: "2017-01-06T16:00:00-06:00", End: "2017-01-06T17:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Too Long Error",
+			in:      types.CreateReservationInput{LotID: "garage", Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-04T17:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Unpriceable Stay Error",
+			in:      types.CreateReservationInput{LotID: "garage", Plate: "ABC123", Start: "2031-01-03T20:00:00-06:00", End: "2031-01-03T21:00:00-06:00"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot := createReservationLot(t, nil)
+			if test.in.LotID == "garage" {
+				test.in.LotID = lot.UUID
+			}
+			if _, err := CreateReservation(&types.CreateReservationInput{LotID: lot.UUID, Plate: "XYZ789", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T18:00:00-06:00"}); err != nil {
+				t.Fatalf("CreateReservation() setup error = %v", err)
+			}
+
+			got, err := CreateReservation(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CreateReservation() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.Plate != "ABC123" || got.State != reservationBooked || got.Price != test.wantPrice || got.Quote == nil) {
+				t.Errorf("CreateReservation() = %v, want a booked reservation with a price of %d", got, test.wantPrice)
+			}
+		})
+	}
+}
+
+func Test_ModifyReservation(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []types.CancellationRule
+		cancelled bool
+		in        types.ModifyReservationInput
+		wantPrice int
+		wantFee   int
+		wantErr   bool
+	}{
+		{
+			name:      "Simple Passing Modify",
+			in:        types.ModifyReservationInput{Plate: "XYZ789", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"},
+			wantPrice: 1800,
+		},
+		{
+			name:      "Longer Stay Passing Modify",
+			in:        types.ModifyReservationInput{Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T18:00:00-06:00"},
+			wantPrice: 3600,
+		},
+		{
+			name:      "Late Change Passing Modify",
+			rules:     []types.CancellationRule{{Before: 24 * 365 * 10, Fee: 50}},
+			in:        types.ModifyReservationInput{Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T18:00:00-06:00"},
+			wantPrice: 3600,
+			wantFee:   900,
+		},
+		{
+			name:    "Full Slot Error",
+			in:      types.ModifyReservationInput{Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T19:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:      "Cancelled Reservation Error",
+			cancelled: true,
+			in:        types.ModifyReservationInput{Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot := createReservationLot(t, test.rules)
+			reservation, err := CreateReservation(&types.CreateReservationInput{LotID: lot.UUID, Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("CreateReservation() setup error = %v", err)
+			}
+			if _, err = CreateReservation(&types.CreateReservationInput{LotID: lot.UUID, Plate: "DEF456", Start: "2031-01-03T18:00:00-06:00", End: "2031-01-03T19:00:00-06:00"}); err != nil {
+				t.Fatalf("CreateReservation() setup error = %v", err)
+			}
+			if test.cancelled {
+				if _, _, err = CancelReservation(reservation.UUID); err != nil {
+					t.Fatalf("CancelReservation() setup error = %v", err)
+				}
+			}
+
+			got, fee, err := ModifyReservation(reservation.UUID, &test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ModifyReservation() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := GetReservation(reservation.UUID); !test.wantErr && (got.Price != test.wantPrice || fee != test.wantFee || got.Fees != test.wantFee || got.Version != 2 || stored.Version != got.Version) {
+				t.Errorf("ModifyReservation() = %v, %d, stored %v, want a price of %d and a fee of %d", got, fee, stored, test.wantPrice, test.wantFee)
+			}
+		})
+	}
+}
+
+func Test_CancelReservation(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []types.CancellationRule
+		cancelled bool
+		wantFee   int
+		wantErr   bool
+	}{
+		{
+			name: "Simple Passing Cancel",
+		},
+		{
+			name:    "Late Cancel Passing Cancel",
+			rules:   []types.CancellationRule{{Before: 24 * 365 * 20, Fee: 25}, {Before: 24 * 365 * 10, Fee: 50}},
+			wantFee: 900,
+		},
+		{
+			name:      "Cancelled Reservation Error",
+			cancelled: true,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot := createReservationLot(t, test.rules)
+			in := types.CreateReservationInput{LotID: lot.UUID, Plate: "ABC123", Start: "2031-01-03T17:00:00-06:00", End: "2031-01-03T18:00:00-06:00"}
+			reservation, err := CreateReservation(&in)
+			if err != nil {
+				t.Fatalf("CreateReservation() setup error = %v", err)
+			}
+			if test.cancelled {
+				if _, _, err = CancelReservation(reservation.UUID); err != nil {
+					t.Fatalf("CancelReservation() setup error = %v", err)
+				}
+			}
+
+			got, fee, err := CancelReservation(reservation.UUID)
+			if (err != nil) != test.wantErr {
+				t.Errorf("CancelReservation() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.State != reservationCancelled || fee != test.wantFee || got.Fees != test.wantFee) {
+				t.Errorf("CancelReservation() = %v, %d, want a cancelled reservation with a fee of %d", got, fee, test.wantFee)
+			}
+			if _, err = CreateReservation(&in); err != nil {
+				t.Errorf("CancelReservation() did not give back the held space: %v", err)
+			}
+		})
+	}
+}
+
+func Test_getSlotCapacity(t *testing.T) {
+	lot := types.Lot{
+		ReservationCapacity: 10,
+		ReservationSlots: []types.ReservationSlot{
+			{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Capacity: 4},
+			{Days: "fri,sat", Times: "0000-2400", TZ: "America/Chicago", Capacity: 6},
+		},
+	}
+	tests := []struct {
+		name string
+		hour string
+		want int
+	}{
+		{
+			name: "First Slot",
+			hour: "2031-01-03T16:00:00-06:00",
+			want: 4,
+		},
+		{
+			name: "First Slot In UTC",
+			hour: "2031-01-03T23:00:00Z",
+			want: 4,
+		},
+		{
+			name: "Second Slot",
+			hour: "2031-01-03T18:00:00-06:00",
+			want: 6,
+		},
+		{
+			name: "No Slot",
+			hour: "2031-01-05T16:00:00-06:00",
+			want: 10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hour, _ := time.Parse(time.RFC3339, test.hour)
+			if got := getSlotCapacity(lot, hour); got != test.want {
+				t.Errorf("getSlotCapacity() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_getSlotHolds(t *testing.T) {
+	lot := types.Lot{
+		UUID:                "garage",
+		ReservationCapacity: 2,
+		ReservationSlots:    []types.ReservationSlot{{Days: "fri", Times: "1800-2400", TZ: "America/Chicago", Capacity: 0}},
+	}
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:  "Simple Passing Holds",
+			start: "2031-01-03T16:30:00-06:00",
+			end:   "2031-01-03T17:15:00-06:00",
+			want:  map[string]int{"garage#2031-01-03T22:00:00Z": 2, "garage#2031-01-03T23:00:00Z": 2},
+		},
+		{
+			name:    "No Reservations Error",
+			start:   "2031-01-03T17:00:00-06:00",
+			end:     "2031-01-03T19:00:00-06:00",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, test.start)
+			end, _ := time.Parse(time.RFC3339, test.end)
+			got, err := getSlotHolds(lot, start, end)
+			if (err != nil) != test.wantErr {
+				t.Errorf("getSlotHolds() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getSlotHolds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_diffSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		holds       map[string]int
+		held        []string
+		wantHold    map[string]int
+		wantRelease []string
+	}{
+		{
+			name:     "Nothing Held",
+			holds:    map[string]int{"a": 1, "b": 1},
+			wantHold: map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name:        "Moved Later",
+			holds:       map[string]int{"b": 1, "c": 2},
+			held:        []string{"a", "b"},
+			wantHold:    map[string]int{"c": 2},
+			wantRelease: []string{"a"},
+		},
+		{
+			name:     "Unchanged",
+			holds:    map[string]int{"a": 1},
+			held:     []string{"a"},
+			wantHold: map[string]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hold, release := diffSlots(test.holds, test.held)
+			if !reflect.DeepEqual(hold, test.wantHold) || !reflect.DeepEqual(release, test.wantRelease) {
+				t.Errorf("diffSlots() = %v, %v, want %v, %v", hold, release, test.wantHold, test.wantRelease)
+			}
+		})
+	}
+}
+
+func Test_getCancellationFee(t *testing.T) {
+	reservation := types.Reservation{Start: "2031-01-03T16:00:00-06:00", Price: 2000}
+	rules := []types.CancellationRule{{Before: 48, Fee: 25}, {Before: 2, Fee: 100}}
+	tests := []struct {
+		name  string
+		rules []types.CancellationRule
+		now   string
+		want  int
+	}{
+		{
+			name: "No Rules",
+			now:  "2031-01-03T15:00:00-06:00",
+			want: 0,
+		},
+		{
+			name:  "Before Every Rule",
+			rules: rules,
+			now:   "2031-01-01T15:00:00-06:00",
+			want:  0,
+		},
+		{
+			name:  "Within First Rule",
+			rules: rules,
+			now:   "2031-01-02T16:00:00-06:00",
+			want:  500,
+		},
+		{
+			name:  "Within Last Rule",
+			rules: rules,
+			now:   "2031-01-03T14:30:00-06:00",
+			want:  2000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now, _ := time.Parse(time.RFC3339, test.now)
+			if got := getCancellationFee(types.Lot{CancellationRules: test.rules}, reservation, now); got != test.want {
+				t.Errorf("getCancellationFee() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -10,9 +10,7 @@ import (
 
 // GetAllRouteMetrics gets all the route metrics from the DB
 func GetAllRouteMetrics() ([]types.RouteMetrics, error) {
-	var metrics []types.RouteMetrics
-	err := config.Config.RouteMetricsTableConn.Scan().All(&metrics)
-	return metrics, err
+	return config.Config.RouteMetrics.All()
 }
 
 // UpdateRouteResponseTime updates the route metrics with the given name
@@ -33,7 +31,7 @@ func UpdateRouteResponseTime(start time.Time, routeName string) {
 	metrics.HitCount++
 	metrics.AvgResponseTime = calculateAvgResponseTime(elapsed, metrics.AvgResponseTime, int64(metrics.HitCount))
 
-	if err = config.Config.RouteMetricsTableConn.Put(&metrics).Run(); err != nil {
+	if err = config.Config.RouteMetrics.Put(metrics); err != nil {
 		log.Errorf("Could not update %s average response time with error: %v", routeName, err)
 		return
 	}
@@ -63,7 +61,7 @@ func UpdateRouteSuccessFailureCount(success bool, routeName string) {
 	}
 	metrics.LastUpdated = time.Now().Unix()
 
-	if err = config.Config.RouteMetricsTableConn.Put(&metrics).Run(); err != nil {
+	if err = config.Config.RouteMetrics.Put(metrics); err != nil {
 		log.Errorf("Could not update %s success/failure count with error: %v", routeName, err)
 		return
 	}
@@ -0,0 +1,209 @@
+package helpers
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// session states
+const (
+	sessionActive = "active"
+	sessionClosed = "closed"
+	sessionVoided = "voided"
+)
+
+// GetSessions gets all of the sessions from the DB
+func GetSessions() ([]types.Session, error) {
+	return config.Config.Sessions.All()
+}
+
+// GetSession gets the session with the given uuid from the DB
+func GetSession(uuid string) (types.Session, error) {
+	return config.Config.Sessions.Get(uuid)
+}
+
+// StartSession starts an active session for a vehicle entering a lot. A plate can only
+// have one active session in a lot
+func StartSession(in *types.StartSessionInput) (types.Session, error) {
+	var (
+		err      error
+		session  types.Session
+		sessions []types.Session
+	)
+
+	if sessions, err = GetSessions(); err != nil {
+		return session, err
+	}
+
+	if err = validateStartSessionInput(in, sessions); err != nil {
+		return session, err
+	}
+
+	entryTime := in.EntryTime
+	if entryTime == "" {
+		entryTime = time.Now().Format(time.RFC3339)
+	}
+
+	uu, _ := uuid.NewV4()
+	session = types.Session{
+		UUID:         uu.String(),
+		LotID:        in.LotID,
+		Plate:        normalizePlate(in.Plate),
+		VehicleClass: in.VehicleClass,
+		State:        sessionActive,
+		EntryTime:    entryTime,
+		CreatedAt:    time.Now().Unix(),
+	}
+	session.UpdatedAt = session.CreatedAt
+
+	// the store holds the plate in the lot while the session is active, so a session
+	// started for the plate since the check above is not doubled
+	if err = config.Config.Sessions.Create(session); errors.Is(err, store.ErrConflict) {
+		return session, fmt.Errorf("plate %s already has an active session in this lot: %w", session.Plate, err)
+	}
+	return session, err
+}
+
+// EndSession closes the active session with the given uuid, charging it the price that
+// GetTimespanPrice quotes for the stay less the validations it redeemed. A promo code that
+// the quote accepts is redeemed and the validations are billed to their merchants before
+// the session is closed, and both are given back if it cannot be closed, so the fee of a
+// closed session always matches what was recorded for it. A session closed with a fee is
+// issued a receipt
+func EndSession(uuid string, in *types.EndSessionInput) (types.Session, error) {
+	var (
+		err         error
+		session     types.Session
+		quote       types.Quote
+		validated   types.Quote
+		validations []types.Validation
+	)
+
+	if session, err = GetSession(uuid); err != nil {
+		return session, err
+	}
+
+	if session.State != sessionActive {
+		return session, fmt.Errorf("session %s is %s, only active sessions can be ended", uuid, session.State)
+	}
+
+	exitTime := in.ExitTime
+	if exitTime == "" {
+		exitTime = time.Now().Format(time.RFC3339)
+	}
+
+	quoteInput := types.GetTimespanPriceInput{
+		Start:        &session.EntryTime,
+		End:          &exitTime,
+		LotID:        session.LotID,
+		VehicleClass: session.VehicleClass,
+		Plate:        session.Plate,
+		PromoCode:    in.PromoCode,
+	}
+	if quote, err = getRedeemedQuote(&quoteInput); err != nil {
+		return session, fmt.Errorf("could not price session %s: %v", uuid, err)
+	}
+
+	if validated, validations, err = getValidatedQuote(session, exitTime, quote); err != nil {
+		return session, unsettleSession(uuid, quote, nil, err)
+	}
+
+	// a validation is billed less than it was quoted for when its merchant's budget was
+	// spent by another session in between, so the quote takes off what was billed
+	if validations, err = applyValidations(validations); err != nil {
+		return session, unsettleSession(uuid, quote, validations, err)
+	}
+	quote = getBilledQuote(quote, validated, validations)
+
+	closed := session
+	closed.State = sessionClosed
+	closed.ExitTime = exitTime
+	closed.Fee = quote.Total
+	closed.Quote = &quote
+	closed.UpdatedAt = time.Now().Unix()
+
+	// the session is closed by a write conditional on it still being active, so that
+	// only the request that closes it keeps its promo use and its validations' billing
+	if closed.Fee > 0 {
+		closed, err = issueReceipt(closed)
+	} else {
+		err = config.Config.Sessions.Transition(closed, sessionActive)
+	}
+	if err != nil {
+		return session, unsettleSession(uuid, quote, validations, err)
+	}
+	return closed, nil
+}
+
+// unsettleSession gives back the promo use and the validations that EndSession settled for
+// the session with the given uuid before it failed to close it with err, and returns err
+func unsettleSession(uuid string, quote types.Quote, billed []types.Validation, err error) error {
+	if undoErr := unredeemQuotedPromo(quote); undoErr != nil {
+		err = fmt.Errorf("%w, and could not give back the use of promo code %s for session %s: %v", err, quote.Promo.Code, uuid, undoErr)
+	}
+	if undoErr := unapplyValidations(billed); undoErr != nil {
+		err = fmt.Errorf("%w, and for session %s %v", err, uuid, undoErr)
+	}
+	return err
+}
+
+// VoidSession voids the active session with the given uuid so that it is not charged. A
+// closed session has already been charged, so it cannot be voided
+func VoidSession(uuid string, in *types.VoidSessionInput) (types.Session, error) {
+	var (
+		err     error
+		session types.Session
+	)
+
+	if session, err = GetSession(uuid); err != nil {
+		return session, err
+	}
+
+	if session.State != sessionActive {
+		return session, fmt.Errorf("session %s is %s, only active sessions can be voided", uuid, session.State)
+	}
+
+	voided := session
+	voided.State = sessionVoided
+	voided.VoidReason = in.Reason
+	voided.UpdatedAt = time.Now().Unix()
+
+	if err = config.Config.Sessions.Transition(voided, sessionActive); err != nil {
+		return session, err
+	}
+	return voided, nil
+}
+
+// normalizePlate returns plate in upper case without spaces or dashes, so that
+// a plate matches however it was entered
+func normalizePlate(plate string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(plate)))
+}
+
+// findActiveSession returns the active session of the vehicle with plate in the lot with the given lotID
+func findActiveSession(sessions []types.Session, lotID, plate string) (types.Session, bool) {
+	for _, session := range sessions {
+		if session.State == sessionActive && session.LotID == lotID && session.Plate == normalizePlate(plate) {
+			return session, true
+		}
+	}
+	return types.Session{}, false
+}
+
+// activeSessionsForLot returns the sessions that are still active in the lot with the given lotID
+func activeSessionsForLot(sessions []types.Session, lotID string) []types.Session {
+	var active []types.Session
+	for _, session := range sessions {
+		if session.State == sessionActive && session.LotID == lotID {
+			active = append(active, session)
+		}
+	}
+	return active
+}
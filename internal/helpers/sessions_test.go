@@ -0,0 +1,214 @@
+package helpers
+
+import (
+	"charlie-parker/pkg/types"
+	"testing"
+)
+
+func Test_StartSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        types.StartSessionInput
+		wantPlate string
+		wantErr   bool
+	}{
+		{
+			name:      "Simple Passing Start",
+			in:        types.StartSessionInput{Plate: "abc 123", EntryTime: "2017-01-06T17:00:00-06:00"},
+			wantPlate: "ABC123",
+		},
+		{
+			name:      "Other Lot Passing Start",
+			in:        types.StartSessionInput{LotID: "garage", Plate: "XYZ-789"},
+			wantPlate: "XYZ789",
+		},
+		{
+			name:    "Missing Plate Error",
+			in:      types.StartSessionInput{Plate: " - "},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Lot Error",
+			in:      types.StartSessionInput{LotID: "missing", Plate: "ABC123"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Entry Time Error",
+			in:      types.StartSessionInput{Plate: "ABC123", EntryTime: "5pm"},
+			wantErr: true,
+		},
+		{
+			name:    "Already Parked Error",
+			in:      types.StartSessionInput{Plate: "xyz 789"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			lot, err := CreateLot(&types.LotInput{Name: "Garage"})
+			if err != nil {
+				t.Fatalf("CreateLot() setup error = %v", err)
+			}
+			if test.in.LotID == "garage" {
+				test.in.LotID = lot.UUID
+			}
+			if _, err = StartSession(&types.StartSessionInput{Plate: "XYZ789"}); err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+
+			got, err := StartSession(&test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("StartSession() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.Plate != test.wantPlate || got.State != sessionActive || got.EntryTime == "") {
+				t.Errorf("StartSession() = %v, want an active session for %s", got, test.wantPlate)
+			}
+		})
+	}
+}
+
+func Test_EndSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     string
+		permitted bool
+		in        types.EndSessionInput
+		wantFee   int
+		wantUses  int
+		wantErr   bool
+	}{
+		{
+			name:    "Simple Passing End",
+			in:      types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00"},
+			wantFee: 1800,
+		},
+		{
+			name:     "Promo Code Passing End",
+			in:       types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00", PromoCode: "FIRST5"},
+			wantFee:  1300,
+			wantUses: 1,
+		},
+		{
+			name:      "Permit Passing End",
+			permitted: true,
+			in:        types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00"},
+			wantFee:   0,
+		},
+		{
+			name:    "Unpriceable Stay Error",
+			in:      types.EndSessionInput{ExitTime: "2017-01-06T19:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Closed Session Error",
+			state:   sessionClosed,
+			in:      types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Closed Session Promo Code Error",
+			state:   sessionClosed,
+			in:      types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00", PromoCode: "FIRST5"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if _, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+				t.Fatalf("CreateRate() setup error = %v", err)
+			}
+			promo, err := CreatePromo(&types.PromoInput{Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago"})
+			if err != nil {
+				t.Fatalf("CreatePromo() setup error = %v", err)
+			}
+			if test.permitted {
+				if _, err = CreatePermit(&types.PermitInput{Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"}); err != nil {
+					t.Fatalf("CreatePermit() setup error = %v", err)
+				}
+			}
+			session, err := StartSession(&types.StartSessionInput{Plate: "ABC123", EntryTime: "2017-01-06T17:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+			if test.state == sessionClosed {
+				if _, err = EndSession(session.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T17:30:00-06:00"}); err != nil {
+					t.Fatalf("EndSession() setup error = %v", err)
+				}
+			}
+
+			got, err := EndSession(session.UUID, &test.in)
+			if (err != nil) != test.wantErr {
+				t.Errorf("EndSession() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := GetSession(session.UUID); !test.wantErr && (got.State != sessionClosed || got.Fee != test.wantFee || stored.Fee != got.Fee || got.Quote == nil || (got.Quote.Permit != nil) != test.permitted) {
+				t.Errorf("EndSession() = %v, stored %v, want a closed session with a fee of %d", got, stored, test.wantFee)
+			}
+			if redeemed, _ := GetPromo(promo.UUID); redeemed.Uses != test.wantUses {
+				t.Errorf("EndSession() left promo uses at %d, want %d", redeemed.Uses, test.wantUses)
+			}
+		})
+	}
+}
+
+func Test_VoidSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		wantErr bool
+	}{
+		{
+			name:  "Active Passing Void",
+			state: sessionActive,
+		},
+		{
+			name:    "Closed Session Error",
+			state:   sessionClosed,
+			wantErr: true,
+		},
+		{
+			name:    "Voided Session Error",
+			state:   sessionVoided,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStores()
+			if _, err := CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+				t.Fatalf("CreateRate() setup error = %v", err)
+			}
+			session, err := StartSession(&types.StartSessionInput{Plate: "ABC123", EntryTime: "2017-01-06T17:00:00-06:00"})
+			if err != nil {
+				t.Fatalf("StartSession() setup error = %v", err)
+			}
+			switch test.state {
+			case sessionClosed:
+				_, err = EndSession(session.UUID, &types.EndSessionInput{ExitTime: "2017-01-06T18:00:00-06:00"})
+			case sessionVoided:
+				_, err = VoidSession(session.UUID, &types.VoidSessionInput{})
+			}
+			if err != nil {
+				t.Fatalf("setup error = %v", err)
+			}
+
+			got, err := VoidSession(session.UUID, &types.VoidSessionInput{Reason: "gate malfunction"})
+			if (err != nil) != test.wantErr {
+				t.Errorf("VoidSession() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && (got.State != sessionVoided || got.VoidReason != "gate malfunction") {
+				t.Errorf("VoidSession() = %v, want a voided session", got)
+			}
+		})
+	}
+}
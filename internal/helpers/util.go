@@ -1,10 +1,10 @@
 package helpers
 
 import (
-	"charlie-parker/internal/config"
 	"charlie-parker/pkg/types"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,6 +26,40 @@ const (
 	sat   = "sat"
 )
 
+const (
+	roundUp      = "up"
+	roundDown    = "down"
+	roundNearest = "nearest"
+)
+
+const (
+	vehicleMotorcycle = "motorcycle"
+	vehicleOversize   = "oversize"
+	vehicleEV         = "ev"
+)
+
+// defaultBillingIncrement is the number of minutes billed at a time when a rate does
+// not set one, which bills per started hour along with the default rounding
+const defaultBillingIncrement = 60
+
+// isValidRounding returns an error for undefined rounding rules
+func isValidRounding(rounding string) error {
+	switch rounding {
+	case roundUp, roundDown, roundNearest:
+		return nil
+	}
+	return fmt.Errorf("Invalid rounding: %s", rounding)
+}
+
+// isValidVehicleClass returns an error for undefined vehicle classes
+func isValidVehicleClass(class string) error {
+	switch class {
+	case vehicleMotorcycle, vehicleOversize, vehicleEV:
+		return nil
+	}
+	return fmt.Errorf("Invalid vehicle class: %s", class)
+}
+
 // isValidDay returns an error for undefined day types
 func isValidDay(day string) error {
 	switch day {
@@ -77,16 +111,6 @@ func weekdayToDay(weekday time.Weekday) (string, error) {
 	return "n/a", fmt.Errorf("cannot convert %v to type Day", weekday)
 }
 
-// putRatesInTable puts one or more rates in the RatesTable
-func putRatesInTable(rates ...types.Rate) error {
-	for _, rate := range rates {
-		if err := config.Config.RatesTableConn.Put(&rate).Run(); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // timeRanges are constructed using days/times/timezones from CreateRateInput objects
 type timeRange struct {
 	days    string
@@ -145,17 +169,31 @@ func timeSpanAsSlice(timespan string) ([]string, error) {
 	return times, nil
 }
 
+// endOfDay may be used as the later time in a rate's times to cover up to midnight
+const endOfDay = "2400"
+
 // getTimeObjectsFromTimes returns the earlier and later Time representation of a slice "times" containing two hours
-// the times returned have the format "0000-01-01 HH:00:00 +0000 UTC"
+// the times returned have the format "0000-01-01 HH:00:00 +0000 UTC"; a later time of endOfDay
+// is returned as midnight at the end of the day, "0000-01-02 00:00:00 +0000 UTC", and a later
+// time before the earlier one wraps past midnight and is returned on the following day as well
 func getTimeObjectsFromTimes(times []string) (earlier time.Time, later time.Time, err error) {
 	if earlier, err = time.Parse("1504", times[0]); err != nil {
 		return earlier, later, fmt.Errorf("could not parse earlier time in range %v: %v", times, err)
+	} else if times[1] == endOfDay {
+		return earlier, time.Date(0, time.January, 2, 0, 0, 0, 0, time.UTC), err
 	} else if later, err = time.Parse("1504", times[1]); err != nil {
 		return earlier, later, fmt.Errorf("could not parse later time in range %v: %v", times, err)
 	}
+
+	if later.Before(earlier) {
+		later = later.AddDate(0, 0, 1)
+	}
 	return earlier, later, err
 }
 
+// errUnavailable is returned when no rate covers a timespan
+var errUnavailable = errors.New("unavailable")
+
 // matchTimespanToRate tries to find an existing rate that a given timespan would be covered by
 func matchTimespanToRate(startTime, endTime time.Time, existingRates []types.Rate) (types.Rate, error) {
 	var (
@@ -164,46 +202,21 @@ func matchTimespanToRate(startTime, endTime time.Time, existingRates []types.Rat
 		matchingRates []types.Rate
 	)
 
-	inputDayStr, _ := weekdayToDay(startTime.Weekday())
-	inputDay := startTime.Day()
-	inputMonth := startTime.Month()
-	inputYear := startTime.Year()
-	_, inputOffset := startTime.Zone()
-
 	for _, rate := range existingRates {
-		if strings.Contains(rate.Days, inputDayStr) {
-			rateLocation, _ := time.LoadLocation(rate.TZ)
-			rateOffset := getLocationOffset(inputYear, inputMonth, inputDay, rateLocation)
-			if inputOffset == rateOffset {
-				rateTimes, _ := timeSpanAsSlice(rate.Times)
-				// these times have the format "0000-01-01 HH:00:00 +0000 UTC"
-				rateStart, rateEnd, _ := getTimeObjectsFromTimes(rateTimes)
-				// put rate start and end in terms of the input's year, month, and day;
-				// due to the month and day of the existing rate times being set to 01,
-				// the month and day passed in are decremented
-				rateStart = rateStart.AddDate(inputYear, int(inputMonth)-1, inputDay-1)
-				rateEnd = rateEnd.AddDate(inputYear, int(inputMonth)-1, inputDay-1)
-				// rate start and end are still in UTC, thus we must put the times in the correct
-				// timezone while retaining the same hour information by subtracting the offset
-				// from their unix timestamp representation
-				rateStart = time.Unix((rateStart.Unix() - int64(inputOffset)), 0).In(rateLocation)
-				rateEnd = time.Unix((rateEnd.Unix() - int64(inputOffset)), 0).In(rateLocation)
-				// rateStart <= startTime < rateEnd
-				if startTime.Sub(rateStart) >= 0 && startTime.Sub(rateEnd) < 0 {
-					// rateStart < endTime <= rateEnd
-					if endTime.Sub(rateStart) > 0 && endTime.Sub(rateEnd) <= 0 {
-						// We found a match!
-						matchingRates = append(matchingRates, rate)
-					}
-					log.Infof("Input end time (%v) is does not fall between rate start and end (%v - %v)", endTime, rateStart, rateEnd)
-				} else {
-					log.Infof("Input start time (%v) is does not fall between rate start and end (%v - %v)", startTime, rateStart, rateEnd)
+		for _, window := range getRateWindows(rate, startTime, endTime) {
+			rateStart, rateEnd := window.start, window.end
+			// rateStart <= startTime < rateEnd
+			if startTime.Sub(rateStart) >= 0 && startTime.Sub(rateEnd) < 0 {
+				// rateStart < endTime <= rateEnd
+				if endTime.Sub(rateStart) > 0 && endTime.Sub(rateEnd) <= 0 {
+					// We found a match!
+					matchingRates = append(matchingRates, rate)
+					break
 				}
+				log.Infof("Input end time (%v) is does not fall between rate start and end (%v - %v)", endTime, rateStart, rateEnd)
 			} else {
-				log.Infof("Input offset (%v) not equal to rate offset (%v)", inputOffset, rateOffset)
+				log.Infof("Input start time (%v) is does not fall between rate start and end (%v - %v)", startTime, rateStart, rateEnd)
 			}
-		} else {
-			log.Infof("Input day (%d) not in rate's days %d", inputDay, rate.Days)
 		}
 	}
 
@@ -213,12 +226,514 @@ func matchTimespanToRate(startTime, endTime time.Time, existingRates []types.Rat
 	}
 
 	if len(matchingRates) == 0 {
-		return rate, errors.New("unavailable")
+		return rate, errUnavailable
 	}
 
 	return matchingRates[0], err
 }
 
+// getRateWindows returns every occurrence of a rate that overlaps from - to. Occurrences
+// are computed on the wall clock of the rate's own timezone, so they are the correct
+// instants whatever timezone from and to are expressed in and on days when clocks change.
+// Since a rate that wraps past midnight belongs to the day it starts on, the occurrence
+// that starts the day before from is considered as well
+func getRateWindows(rate types.Rate, from, to time.Time) []timespan {
+	rateLocation, err := time.LoadLocation(rate.TZ)
+	if err != nil {
+		log.Errorf("Could not load rate %s timezone %s: %v", rate.UUID, rate.TZ, err)
+		return nil
+	}
+
+	rateTimes, _ := timeSpanAsSlice(rate.Times)
+	// these times have the format "0000-01-01 HH:MM:00 +0000 UTC", where a later time
+	// on "0000-01-02" ends on the day after the rate starts
+	earlier, later, err := getTimeObjectsFromTimes(rateTimes)
+	if err != nil {
+		log.Errorf("Could not parse rate %s times %s: %v", rate.UUID, rate.Times, err)
+		return nil
+	}
+
+	var windows []timespan
+	// dates are walked as UTC calendar days, which always have the expected weekday
+	y, m, d := from.In(rateLocation).Date()
+	for date := time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC); ; date = date.AddDate(0, 0, 1) {
+		rateStart := getLocalInstant(date, earlier.Hour(), earlier.Minute(), rateLocation)
+		if !rateStart.Before(to) {
+			break
+		}
+
+		if !appliesOn(rate, date) {
+			continue
+		}
+
+		if !isEffectiveOn(rate, date) {
+			continue
+		}
+
+		rateEnd := getLocalInstant(date.AddDate(0, 0, later.Day()-1), later.Hour(), later.Minute(), rateLocation)
+		if rateEnd.After(from) {
+			windows = append(windows, timespan{start: rateStart, end: rateEnd})
+		}
+	}
+	return windows
+}
+
+// getLocalInstant returns the instant at which the wall clock in loc reads hour:min on the
+// calendar day of date. Go leaves it unspecified which instant time.Date picks around clock
+// changes, so they are resolved explicitly: a wall clock time that does not exist because
+// clocks jump forward resolves to the instant the clocks jump to, and a wall clock time that
+// happens twice because clocks fall back resolves to its first occurrence. Resolving every
+// wall clock time the same way keeps rates that meet at it back to back
+func getLocalInstant(date time.Time, hour, min int, loc *time.Location) time.Time {
+	wall := time.Date(date.Year(), date.Month(), date.Day(), hour, min, 0, 0, time.UTC)
+	// the offsets in effect a day either side of the wall clock time are the only
+	// offsets that can be in effect at it
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(loc).Zone()
+	atBefore := wall.Add(-time.Duration(offsetBefore) * time.Second).In(loc)
+	atAfter := wall.Add(-time.Duration(offsetAfter) * time.Second).In(loc)
+
+	_, atBeforeOffset := atBefore.Zone()
+	_, atAfterOffset := atAfter.Zone()
+	beforeExists, afterExists := atBeforeOffset == offsetBefore, atAfterOffset == offsetAfter
+	switch {
+	case beforeExists && afterExists:
+		if atAfter.Before(atBefore) {
+			return atAfter
+		}
+		return atBefore
+	case beforeExists:
+		return atBefore
+	case afterExists:
+		return atAfter
+	}
+
+	// the wall clock time was skipped, so search between the two candidates for the
+	// instant at which the offset changes
+	lo, hi := atAfter, atBefore
+	if hi.Before(lo) {
+		lo, hi = hi, lo
+	}
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, offset := mid.Zone(); offset == offsetBefore {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+// getRatesForTimezone narrows rates down to the schedule of a single timezone and returns
+// that timezone's location. If tz is not given and the rates use more than one timezone,
+// the schedule is inferred from the timezones whose offset at startTime matches the offset
+// startTime was given in, and it is an error if that does not single out one timezone
+func getRatesForTimezone(rates []types.Rate, tz string, startTime time.Time) ([]types.Rate, *time.Location, error) {
+	var zones []string
+	zoneRates := make(map[string][]types.Rate)
+	for _, rate := range rates {
+		if _, seen := zoneRates[rate.TZ]; !seen {
+			zones = append(zones, rate.TZ)
+		}
+		zoneRates[rate.TZ] = append(zoneRates[rate.TZ], rate)
+	}
+
+	if tz == "" {
+		switch len(zones) {
+		case 0:
+			return rates, startTime.Location(), nil
+		case 1:
+			tz = zones[0]
+		default:
+			var candidates []string
+			_, startOffset := startTime.Zone()
+			for _, zone := range zones {
+				loc, _ := time.LoadLocation(zone)
+				if _, offset := startTime.In(loc).Zone(); offset == startOffset {
+					candidates = append(candidates, zone)
+				}
+			}
+			if len(candidates) != 1 {
+				return nil, nil, fmt.Errorf("rates exist in several timezones (%s), specify tz", strings.Join(zones, ", "))
+			}
+			tz = candidates[0]
+		}
+	}
+
+	if err := validateTimeZone(tz); err != nil {
+		return nil, nil, err
+	}
+	loc, _ := time.LoadLocation(tz)
+	return zoneRates[tz], loc, nil
+}
+
+// timespan is a start and end time
+type timespan struct {
+	start time.Time
+	end   time.Time
+}
+
+// rateSegment is the part of a timespan that is covered by a single occurrence of a rate
+type rateSegment struct {
+	rate  types.Rate
+	start time.Time
+	end   time.Time
+	// occurrence is when the occurrence of the rate covering the segment starts
+	occurrence time.Time
+}
+
+// continues reports whether next picks up right where s leaves off in the same occurrence of the same rate
+func (s rateSegment) continues(next rateSegment) bool {
+	return s.rate.UUID == next.rate.UUID && s.end.Equal(next.start) && s.occurrence.Equal(next.occurrence)
+}
+
+// splitTimespanIntoDays cuts a timespan at every midnight in the start time's location
+func splitTimespanIntoDays(startTime, endTime time.Time) []timespan {
+	var days []timespan
+	for dayStart := startTime; dayStart.Before(endTime); {
+		y, m, d := dayStart.Date()
+		dayEnd := time.Date(y, m, d+1, 0, 0, 0, 0, startTime.Location())
+		if dayEnd.After(endTime) {
+			dayEnd = endTime
+		}
+		days = append(days, timespan{start: dayStart, end: dayEnd})
+		dayStart = dayEnd
+	}
+	return days
+}
+
+// splitTimespanAtRates walks every day of a timespan, cuts each day at every rate
+// boundary that falls inside of it and matches each piece to the rate that covers it.
+// A segment only continues past midnight when it is covered by a rate that wraps past
+// midnight, in which case it belongs to the day it starts on. If any part of the
+// timespan is not covered by a rate, the returned error names every uncovered part
+func splitTimespanAtRates(startTime, endTime time.Time, existingRates []types.Rate) ([]rateSegment, error) {
+	var (
+		segments []rateSegment
+		gaps     []timespan
+	)
+	for _, day := range splitTimespanIntoDays(startTime, endTime) {
+		daySegments, dayGaps, err := splitDayAtRates(day.start, day.end, existingRates)
+		if err != nil {
+			return segments, err
+		}
+		for _, segment := range daySegments {
+			if last := len(segments) - 1; last >= 0 && segments[last].continues(segment) {
+				segments[last].end = segment.end
+				continue
+			}
+			segments = append(segments, segment)
+		}
+		for _, gap := range dayGaps {
+			// join gaps that continue across midnight into one
+			if last := len(gaps) - 1; last >= 0 && gaps[last].end.Equal(gap.start) {
+				gaps[last].end = gap.end
+				continue
+			}
+			gaps = append(gaps, gap)
+		}
+	}
+
+	if len(gaps) > 0 {
+		var uncovered []string
+		for _, gap := range gaps {
+			uncovered = append(uncovered, formatTimespan(gap.start, gap.end, startTime.Location()))
+		}
+		return segments, fmt.Errorf("%w: no rate covers %s", errUnavailable, strings.Join(uncovered, ", "))
+	}
+	return segments, nil
+}
+
+// splitDayAtRates cuts a timespan that falls on one day at every rate boundary inside
+// of it and matches each piece to the rate that covers it. Consecutive pieces covered by
+// the same occurrence of a rate are merged into one segment; pieces not covered by any
+// rate are returned as gaps
+func splitDayAtRates(startTime, endTime time.Time, existingRates []types.Rate) ([]rateSegment, []timespan, error) {
+	boundaries := []time.Time{startTime, endTime}
+	for _, rate := range existingRates {
+		for _, window := range getRateWindows(rate, startTime, endTime) {
+			for _, boundary := range []time.Time{window.start, window.end} {
+				if boundary.After(startTime) && boundary.Before(endTime) {
+					boundaries = append(boundaries, boundary)
+				}
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	var (
+		segments []rateSegment
+		gaps     []timespan
+	)
+	for i := 0; i+1 < len(boundaries); i++ {
+		pieceStart, pieceEnd := boundaries[i], boundaries[i+1]
+		if !pieceStart.Before(pieceEnd) {
+			continue
+		}
+
+		rate, err := matchTimespanToRate(pieceStart, pieceEnd, existingRates)
+		if err == errUnavailable {
+			if last := len(gaps) - 1; last >= 0 && gaps[last].end.Equal(pieceStart) {
+				gaps[last].end = pieceEnd
+			} else {
+				gaps = append(gaps, timespan{start: pieceStart, end: pieceEnd})
+			}
+			continue
+		} else if err != nil {
+			return segments, gaps, err
+		}
+
+		segment := rateSegment{rate: rate, start: pieceStart, end: pieceEnd}
+		for _, window := range getRateWindows(rate, pieceStart, pieceEnd) {
+			if !pieceStart.Before(window.start) && !pieceEnd.After(window.end) {
+				segment.occurrence = window.start
+			}
+		}
+		if last := len(segments) - 1; last >= 0 && segments[last].continues(segment) {
+			segments[last].end = pieceEnd
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return segments, gaps, nil
+}
+
+// getBillingRules returns a rate's billing increment in minutes and rounding rule,
+// filling in the defaults for rates that do not set them
+func getBillingRules(rate types.Rate) (int, string) {
+	increment, rounding := rate.BillingIncrement, rate.Rounding
+	if increment == 0 {
+		increment = defaultBillingIncrement
+	}
+	if rounding == "" {
+		rounding = roundUp
+	}
+	return increment, rounding
+}
+
+// getBillableUnits returns how many increments of the given number of minutes are
+// billed for duration, using rounding to decide how a partial increment is billed
+func getBillableUnits(duration time.Duration, increment int, rounding string) int {
+	seconds := int64(duration / time.Second)
+	incrementSeconds := int64(increment) * 60
+	switch rounding {
+	case roundDown:
+		return int(seconds / incrementSeconds)
+	case roundNearest:
+		return int((seconds + incrementSeconds/2) / incrementSeconds)
+	}
+	return int((seconds + incrementSeconds - 1) / incrementSeconds)
+}
+
+// getUnbilled returns how much of elapsed is left once billed has been billed, or
+// nothing when billed already covers all of it
+func getUnbilled(elapsed, billed time.Duration) time.Duration {
+	if billed >= elapsed {
+		return 0
+	}
+	return elapsed - billed
+}
+
+// getSegmentsTime returns the time that segments cover
+func getSegmentsTime(segments []rateSegment) time.Duration {
+	var elapsed time.Duration
+	for _, segment := range segments {
+		elapsed += segment.end.Sub(segment.start)
+	}
+	return elapsed
+}
+
+// getBilledTime returns the time billed for priced segments, counting every
+// billable unit as a full increment
+func getBilledTime(segments []types.PriceSegment) time.Duration {
+	var billed time.Duration
+	for _, segment := range segments {
+		billed += time.Duration(segment.BillableUnits*segment.BillingIncrement) * time.Minute
+	}
+	return billed
+}
+
+// getUnitsPrice converts a number of billable units of increment minutes into cents
+// for a price in cents per hour, rounding to the nearest cent
+func getUnitsPrice(pricePerHour, units, increment int) int {
+	return (pricePerHour*units*increment + 30) / 60
+}
+
+// getTieredPrice converts a number of billable units of increment minutes into cents
+// using the rate's tiers, for units that start once the rate has already billed
+// elapsed minutes of the stay. Units that no tier covers are charged the rate's price.
+// The charges for each tier are only returned for rates that have tiers
+func getTieredPrice(rate types.Rate, units, increment, elapsed int) (int, []types.TierCharge) {
+	if len(rate.Tiers) == 0 {
+		return getUnitsPrice(rate.Price, units, increment), nil
+	}
+
+	var (
+		price   int
+		charges []types.TierCharge
+	)
+	for i := 0; i < units; i++ {
+		from := elapsed + i*increment
+		tier, pricePerHour := 0, rate.Price
+		for t, priceTier := range rate.Tiers {
+			if from >= priceTier.From && (priceTier.Until == 0 || from < priceTier.Until) {
+				tier, pricePerHour = t+1, priceTier.Price
+				break
+			}
+		}
+
+		if last := len(charges) - 1; last >= 0 && charges[last].Tier == tier {
+			charges[last].Until += increment
+			charges[last].BillableUnits++
+			continue
+		}
+		charges = append(charges, types.TierCharge{Tier: tier, From: from, Until: from + increment, BillableUnits: 1, Price: pricePerHour})
+	}
+
+	// charges hold the hourly price until every unit of the tier has been counted
+	for i, charge := range charges {
+		charges[i].Price = getUnitsPrice(charge.Price, charge.BillableUnits, increment)
+		price += charges[i].Price
+	}
+	return price, charges
+}
+
+// addToDaySubtotals adds price to the subtotal for the day on which t falls, starting a
+// new subtotal when t is on a later day than the last one
+func addToDaySubtotals(days []types.DaySubtotal, t time.Time, price int) []types.DaySubtotal {
+	date := t.Format("2006-01-02")
+	if last := len(days) - 1; last >= 0 && days[last].Date == date {
+		days[last].Price += price
+		return days
+	}
+	return append(days, types.DaySubtotal{Date: date, Price: price})
+}
+
+const (
+	adjustDailyMax    = "dailyMax"
+	adjustMinCharge   = "minCharge"
+	adjustEntryGrace  = "entryGrace"
+	adjustExitGrace   = "exitGrace"
+	adjustFreeMinutes = "freeMinutes"
+	sourceRate        = "rate"
+	sourceLot         = "lot"
+)
+
+// applyPriceLimits applies the daily maximums and minimum charges of the rates that
+// priced segments, and then those of lot, to a quote whose segments are in the same
+// order as segments, with days on the wall clock of loc. Rates are capped per day and lifted to their minimum per stay,
+// the lot caps each day subtotal and lifts the total to its minimum. A minimum is
+// added to the first day it applies to so that the day subtotals add up to the total
+func applyPriceLimits(quote types.Quote, segments []rateSegment, lot types.Lot, loc *time.Location) types.Quote {
+	dayIndex := make(map[string]int)
+	for i, day := range quote.Days {
+		dayIndex[day.Date] = i
+	}
+
+	adjust := func(kind, source, rateUUID, date string, amount int) {
+		quote.Days[dayIndex[date]].Price += amount
+		quote.Total += amount
+		quote.Adjustments = append(quote.Adjustments, types.PriceAdjustment{
+			Type:     kind,
+			Source:   source,
+			RateUUID: rateUUID,
+			Date:     date,
+			Amount:   amount,
+		})
+		if kind == adjustDailyMax {
+			quote.CapApplied = true
+		} else {
+			quote.FloorApplied = true
+		}
+	}
+
+	// what each rate charges on each day, in the order the rates first apply
+	type rateDay struct{ uuid, date string }
+	var (
+		order    []rateDay
+		rates    = make(map[string]types.Rate)
+		rateDays = make(map[rateDay]int)
+	)
+	for i, segment := range segments {
+		key := rateDay{segment.rate.UUID, segment.start.In(loc).Format(effectiveDateLayout)}
+		if _, ok := rateDays[key]; !ok {
+			order = append(order, key)
+		}
+		rates[key.uuid] = segment.rate
+		rateDays[key] += quote.Segments[i].Price
+	}
+
+	stayCharges := make(map[string]int)
+	for _, key := range order {
+		charge := rateDays[key]
+		if max := rates[key.uuid].DailyMax; max > 0 && charge > max {
+			adjust(adjustDailyMax, sourceRate, key.uuid, key.date, max-charge)
+			charge = max
+		}
+		stayCharges[key.uuid] += charge
+	}
+
+	floored := make(map[string]bool)
+	for _, key := range order {
+		if floored[key.uuid] {
+			continue
+		}
+		floored[key.uuid] = true
+		if min := rates[key.uuid].MinCharge; stayCharges[key.uuid] < min {
+			adjust(adjustMinCharge, sourceRate, key.uuid, key.date, min-stayCharges[key.uuid])
+		}
+	}
+
+	for _, day := range quote.Days {
+		if lot.DailyMax > 0 && day.Price > lot.DailyMax {
+			adjust(adjustDailyMax, sourceLot, "", day.Date, lot.DailyMax-day.Price)
+		}
+	}
+
+	if len(quote.Days) > 0 && quote.Total < lot.MinCharge {
+		adjust(adjustMinCharge, sourceLot, "", quote.Days[0].Date, lot.MinCharge-quote.Total)
+	}
+
+	return quote
+}
+
+// getGracePeriod returns the rate's grace period in minutes and sourceRate when the rate
+// sets one, and otherwise the lot's grace period and sourceLot
+func getGracePeriod(rateMinutes, lotMinutes int) (int, string) {
+	if rateMinutes > 0 {
+		return rateMinutes, sourceRate
+	}
+	return lotMinutes, sourceLot
+}
+
+// appendGraceAdjustment adds an adjustment of amount made by a grace period to adjustments,
+// naming the rate only when the grace period is the rate's. Grace periods that did not
+// change the price are not added
+func appendGraceAdjustment(adjustments []types.PriceAdjustment, kind, source, rateUUID, date string, amount int) []types.PriceAdjustment {
+	if amount == 0 {
+		return adjustments
+	}
+	if source != sourceRate {
+		rateUUID = ""
+	}
+	return append(adjustments, types.PriceAdjustment{Type: kind, Source: source, RateUUID: rateUUID, Date: date, Amount: amount})
+}
+
+// getFreeQuote returns a quote that charges nothing for a stay that quote priced, because a
+// grace period of kind covers all of it
+func getFreeQuote(quote types.Quote, kind, source, rateUUID, date string) types.Quote {
+	return types.Quote{
+		Days:        []types.DaySubtotal{{Date: date, Price: 0}},
+		Adjustments: appendGraceAdjustment(nil, kind, source, rateUUID, date, -quote.Total),
+	}
+}
+
+// formatTimespan formats a start and end in loc as "start - end" using RFC3339
+func formatTimespan(start, end time.Time, loc *time.Location) string {
+	return fmt.Sprintf("%s - %s", start.In(loc).Format(time.RFC3339), end.In(loc).Format(time.RFC3339))
+}
+
 // getLocationOffset create a dummy time object in terms of the input's year, month, and day
 // set the hour of the time to 5am to widely avoid any potential conflict for days
 // on which clocks change around the world (the latest of which currently occurs
@@ -230,6 +745,242 @@ func getLocationOffset(year int, month time.Month, day int, loc *time.Location)
 	return offset
 }
 
+// isOverride reports whether rate targets specific dates or a calendar instead of weekdays
+func isOverride(rate types.Rate) bool {
+	return rate.Dates != "" || rate.Calendar != ""
+}
+
+// appliesOn reports whether rate has an occurrence that starts on the calendar day of date.
+// Override rates apply on their dates, and weekday rates apply on their days unless the
+// date is overridden. Dates are compared as comma separated strings, like days are
+func appliesOn(rate types.Rate, date time.Time) bool {
+	if isOverride(rate) {
+		return strings.Contains(rate.Dates, date.Format(effectiveDateLayout))
+	}
+
+	day, _ := weekdayToDay(date.Weekday())
+	if !strings.Contains(rate.Days, day) {
+		log.Debugf("Day (%s) not in rate's days %s", day, rate.Days)
+		return false
+	}
+	return !strings.Contains(rate.ExceptDates, date.Format(effectiveDateLayout))
+}
+
+// resolveOverrides returns a copy of rates ready to be matched: rates that target a calendar
+// have the calendar's dates added to their own, and weekday rates are excepted from every
+// date that an override rate in their lot, vehicle class and timezone covers some of their
+// hours on, so overrides take precedence. A calendar that does not exist adds no dates
+func resolveOverrides(rates []types.Rate, calendars []types.Calendar) []types.Rate {
+	calendarDates := make(map[string][]string, len(calendars))
+	for _, calendar := range calendars {
+		calendarDates[calendar.Name] = calendar.Dates
+	}
+
+	// overrides only take precedence within their own lot, vehicle class and timezone
+	type schedule struct{ lotID, vehicleClass, tz string }
+	resolved := make([]types.Rate, len(rates))
+	overrides := make(map[schedule][]types.Rate)
+	for i, rate := range rates {
+		if rate.Calendar != "" {
+			dates := calendarDates[rate.Calendar]
+			if rate.Dates != "" {
+				dates = append(strings.Split(rate.Dates, ","), dates...)
+			}
+			rate.Dates = strings.Join(dates, ",")
+		}
+		if rate.Dates != "" {
+			key := schedule{rate.LotID, rate.VehicleClass, rate.TZ}
+			overrides[key] = append(overrides[key], rate)
+		}
+		resolved[i] = rate
+	}
+
+	for i, rate := range resolved {
+		if isOverride(rate) {
+			continue
+		}
+		var except []string
+		for _, override := range overrides[schedule{rate.LotID, rate.VehicleClass, rate.TZ}] {
+			except = append(except, getOverriddenDates(rate, override)...)
+		}
+		resolved[i].ExceptDates = strings.Join(except, ",")
+	}
+	return resolved
+}
+
+// getOverriddenDates returns the dates that the occurrences of weekday rate overlapping
+// the hours of override on its dates start on. Besides the override's dates themselves,
+// these are the days before them when rate wraps past midnight into the override's hours,
+// and the days after them when the override wraps past midnight into the rate's. Rates
+// whose times do not parse are taken to overlap on every one of the override's dates
+func getOverriddenDates(rate, override types.Rate) []string {
+	rateEarlier, rateLater, rateErr := getRateTimes(rate)
+	overrideEarlier, overrideLater, overrideErr := getRateTimes(override)
+	// overlaps reports whether the occurrence of rate shift days from the override's overlaps it
+	overlaps := func(shift int) bool {
+		if rateErr != nil || overrideErr != nil {
+			return shift == 0
+		}
+		return rateEarlier.AddDate(0, 0, shift).Before(overrideLater) && overrideEarlier.Before(rateLater.AddDate(0, 0, shift))
+	}
+
+	var dates []string
+	for _, date := range strings.Split(override.Dates, ",") {
+		day, err := time.Parse(effectiveDateLayout, date)
+		if err != nil {
+			continue
+		}
+		for _, shift := range []int{-1, 0, 1} {
+			if overlaps(shift) {
+				dates = append(dates, day.AddDate(0, 0, shift).Format(effectiveDateLayout))
+			}
+		}
+	}
+	return dates
+}
+
+// getRateTimes returns the earlier and later time of a rate's times like getTimeObjectsFromTimes
+func getRateTimes(rate types.Rate) (time.Time, time.Time, error) {
+	times, err := timeSpanAsSlice(rate.Times)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return getTimeObjectsFromTimes(times)
+}
+
+// getDatesSpan returns the span from the start of the day before the first of
+// the comma separated dates to the end of the day after the last one, as UTC
+// calendar days, which covers every occurrence on those dates in any timezone
+func getDatesSpan(dates string) (from, to time.Time) {
+	for _, date := range strings.Split(dates, ",") {
+		day, err := time.Parse(effectiveDateLayout, date)
+		if err != nil {
+			continue
+		}
+		if from.IsZero() || day.Before(from) {
+			from = day
+		}
+		if to.IsZero() || day.After(to) {
+			to = day
+		}
+	}
+	return from.AddDate(0, 0, -1), to.AddDate(0, 0, 2)
+}
+
+// getCollisions returns the dates, in loc, of the windows in a that overlap a window in b.
+// Both lists of windows must be in chronological order
+func getCollisions(a, b []timespan, loc *time.Location) []string {
+	var collisions []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if a[i].start.Before(b[j].end) && b[j].start.Before(a[i].end) {
+			date := a[i].start.In(loc).Format(effectiveDateLayout)
+			if len(collisions) == 0 || collisions[len(collisions)-1] != date {
+				collisions = append(collisions, date)
+			}
+		}
+
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return collisions
+}
+
+// effectiveDateLayout is the layout of a rate's EffectiveFrom and EffectiveUntil
+const effectiveDateLayout = "2006-01-02"
+
+// rateStarts and rateEnds are the kinds of RateChange
+const (
+	rateStarts = "starts"
+	rateEnds   = "ends"
+)
+
+// isEffectiveOn reports whether rate applies to an occurrence that starts on the calendar
+// day of date. Effective dates compare as strings since they are in YYYY-MM-DD format
+func isEffectiveOn(rate types.Rate, date time.Time) bool {
+	day := date.Format(effectiveDateLayout)
+	if rate.EffectiveFrom != "" && day < rate.EffectiveFrom {
+		return false
+	}
+	return rate.EffectiveUntil == "" || day < rate.EffectiveUntil
+}
+
+// effectiveDatesIntersect reports whether there is a date on which both a rate effective
+// from aFrom until aUntil and one effective from bFrom until bUntil apply
+func effectiveDatesIntersect(aFrom, aUntil, bFrom, bUntil string) bool {
+	if aUntil != "" && bFrom != "" && bFrom >= aUntil {
+		return false
+	}
+	return bUntil == "" || aFrom == "" || aFrom < bUntil
+}
+
+// getUpcomingRateChanges returns the dates after now, on the wall clock of each rate's
+// timezone, on which rates start or stop applying, in date order
+func getUpcomingRateChanges(rates []types.Rate, now time.Time) []types.RateChange {
+	var changes []types.RateChange
+	for _, rate := range rates {
+		loc, err := time.LoadLocation(rate.TZ)
+		if err != nil {
+			log.Errorf("Could not load rate %s timezone %s: %v", rate.UUID, rate.TZ, err)
+			continue
+		}
+
+		today := now.In(loc).Format(effectiveDateLayout)
+		if rate.EffectiveFrom > today {
+			changes = append(changes, types.RateChange{Date: rate.EffectiveFrom, Change: rateStarts, Rate: rate})
+		}
+		if rate.EffectiveUntil > today {
+			changes = append(changes, types.RateChange{Date: rate.EffectiveUntil, Change: rateEnds, Rate: rate})
+		}
+	}
+
+	// a rate ending and the rate replacing it starting on the same date read best in that order
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Date != changes[j].Date {
+			return changes[i].Date < changes[j].Date
+		}
+		return changes[i].Change == rateEnds && changes[j].Change == rateStarts
+	})
+	return changes
+}
+
+// ratesForLot returns the rates for the lot with the given lotID, or the
+// rates of the default schedule if lotID is empty
+func ratesForLot(rates []types.Rate, lotID string) []types.Rate {
+	var lotRates []types.Rate
+	for _, rate := range rates {
+		if rate.LotID == lotID {
+			lotRates = append(lotRates, rate)
+		}
+	}
+	return lotRates
+}
+
+// ratesForVehicleClass returns the rates for the given vehicle class, or the
+// rates of the default class if class is empty
+func ratesForVehicleClass(rates []types.Rate, class string) []types.Rate {
+	var classRates []types.Rate
+	for _, rate := range rates {
+		if rate.VehicleClass == class {
+			classRates = append(classRates, rate)
+		}
+	}
+	return classRates
+}
+
+// ratesExcept returns rates without the rate with the given uuid
+func ratesExcept(rates []types.Rate, uuid string) []types.Rate {
+	var others []types.Rate
+	for _, rate := range rates {
+		if rate.UUID != uuid {
+			others = append(others, rate)
+		}
+	}
+	return others
+}
+
 //-----------------------------------------------------------------------------
 // ROUTE METRICS UTIL ---------------------------------------------------------
 //-----------------------------------------------------------------------------
@@ -241,6 +992,112 @@ const (
 	CreateRateRouteName = "CreateRateRoute"
 	// OverwriteRatesRouteName const
 	OverwriteRatesRouteName = "OverwriteRatesRoute"
+	// GetUpcomingRateChangesRouteName const
+	GetUpcomingRateChangesRouteName = "GetUpcomingRateChangesRoute"
+	// GetRateRouteName const
+	GetRateRouteName = "GetRateRoute"
+	// UpdateRateRouteName const
+	UpdateRateRouteName = "UpdateRateRoute"
+	// PatchRateRouteName const
+	PatchRateRouteName = "PatchRateRoute"
+	// DeleteRateRouteName const
+	DeleteRateRouteName = "DeleteRateRoute"
+	// GetCalendarsRouteName const
+	GetCalendarsRouteName = "GetCalendarsRoute"
+	// GetCalendarRouteName const
+	GetCalendarRouteName = "GetCalendarRoute"
+	// PutCalendarRouteName const
+	PutCalendarRouteName = "PutCalendarRoute"
+	// ImportCalendarRouteName const
+	ImportCalendarRouteName = "ImportCalendarRoute"
+	// DeleteCalendarRouteName const
+	DeleteCalendarRouteName = "DeleteCalendarRoute"
+	// GetLotsRouteName const
+	GetLotsRouteName = "GetLotsRoute"
+	// CreateLotRouteName const
+	CreateLotRouteName = "CreateLotRoute"
+	// GetLotRouteName const
+	GetLotRouteName = "GetLotRoute"
+	// UpdateLotRouteName const
+	UpdateLotRouteName = "UpdateLotRoute"
+	// DeleteLotRouteName const
+	DeleteLotRouteName = "DeleteLotRoute"
+	// GetProductsRouteName const
+	GetProductsRouteName = "GetProductsRoute"
+	// CreateProductRouteName const
+	CreateProductRouteName = "CreateProductRoute"
+	// GetProductRouteName const
+	GetProductRouteName = "GetProductRoute"
+	// UpdateProductRouteName const
+	UpdateProductRouteName = "UpdateProductRoute"
+	// DeleteProductRouteName const
+	DeleteProductRouteName = "DeleteProductRoute"
+	// GetPromosRouteName const
+	GetPromosRouteName = "GetPromosRoute"
+	// CreatePromoRouteName const
+	CreatePromoRouteName = "CreatePromoRoute"
+	// GetPromoRouteName const
+	GetPromoRouteName = "GetPromoRoute"
+	// UpdatePromoRouteName const
+	UpdatePromoRouteName = "UpdatePromoRoute"
+	// DeletePromoRouteName const
+	DeletePromoRouteName = "DeletePromoRoute"
+	// RedeemPromoRouteName const
+	RedeemPromoRouteName = "RedeemPromoRoute"
+	// ReportOccupancyRouteName const
+	ReportOccupancyRouteName = "ReportOccupancyRoute"
+	// GetOccupancyRouteName const
+	GetOccupancyRouteName = "GetOccupancyRoute"
+	// GetSessionsRouteName const
+	GetSessionsRouteName = "GetSessionsRoute"
+	// StartSessionRouteName const
+	StartSessionRouteName = "StartSessionRoute"
+	// GetSessionRouteName const
+	GetSessionRouteName = "GetSessionRoute"
+	// EndSessionRouteName const
+	EndSessionRouteName = "EndSessionRoute"
+	// VoidSessionRouteName const
+	VoidSessionRouteName = "VoidSessionRoute"
+	// GetReservationsRouteName const
+	GetReservationsRouteName = "GetReservationsRoute"
+	// CreateReservationRouteName const
+	CreateReservationRouteName = "CreateReservationRoute"
+	// GetReservationRouteName const
+	GetReservationRouteName = "GetReservationRoute"
+	// ModifyReservationRouteName const
+	ModifyReservationRouteName = "ModifyReservationRoute"
+	// CancelReservationRouteName const
+	CancelReservationRouteName = "CancelReservationRoute"
+	// GetSessionReceiptRouteName const
+	GetSessionReceiptRouteName = "GetSessionReceiptRoute"
+	// GetPermitsRouteName const
+	GetPermitsRouteName = "GetPermitsRoute"
+	// CreatePermitRouteName const
+	CreatePermitRouteName = "CreatePermitRoute"
+	// GetPermitRouteName const
+	GetPermitRouteName = "GetPermitRoute"
+	// UpdatePermitRouteName const
+	UpdatePermitRouteName = "UpdatePermitRoute"
+	// DeletePermitRouteName const
+	DeletePermitRouteName = "DeletePermitRoute"
+	// GetMerchantsRouteName const
+	GetMerchantsRouteName = "GetMerchantsRoute"
+	// CreateMerchantRouteName const
+	CreateMerchantRouteName = "CreateMerchantRoute"
+	// GetMerchantRouteName const
+	GetMerchantRouteName = "GetMerchantRoute"
+	// UpdateMerchantRouteName const
+	UpdateMerchantRouteName = "UpdateMerchantRoute"
+	// DeleteMerchantRouteName const
+	DeleteMerchantRouteName = "DeleteMerchantRoute"
+	// IssueValidationsRouteName const
+	IssueValidationsRouteName = "IssueValidationsRoute"
+	// GetMerchantValidationsRouteName const
+	GetMerchantValidationsRouteName = "GetMerchantValidationsRoute"
+	// GetMerchantStatementRouteName const
+	GetMerchantStatementRouteName = "GetMerchantStatementRoute"
+	// RedeemValidationRouteName const
+	RedeemValidationRouteName = "RedeemValidationRoute"
 	// GetTimespanPriceRouteName const
 	GetTimespanPriceRouteName = "GetTimespanPriceRoute"
 	// GetAllRouteMetricsRouteName const
@@ -250,7 +1107,20 @@ const (
 // isValidRouteName errors if a given route name is not defined
 func isValidRouteName(routeName string) error {
 	switch routeName {
-	case GetRatesRouteName, CreateRateRouteName, OverwriteRatesRouteName, GetTimespanPriceRouteName, GetAllRouteMetricsRouteName:
+	case GetRatesRouteName, CreateRateRouteName, OverwriteRatesRouteName, GetUpcomingRateChangesRouteName, GetRateRouteName,
+		UpdateRateRouteName, PatchRateRouteName, DeleteRateRouteName, GetCalendarsRouteName, GetCalendarRouteName,
+		PutCalendarRouteName, ImportCalendarRouteName, DeleteCalendarRouteName, GetLotsRouteName, CreateLotRouteName,
+		GetLotRouteName, UpdateLotRouteName, DeleteLotRouteName, GetProductsRouteName, CreateProductRouteName,
+		GetProductRouteName, UpdateProductRouteName, DeleteProductRouteName, GetPromosRouteName, CreatePromoRouteName,
+		GetPromoRouteName, UpdatePromoRouteName, DeletePromoRouteName, RedeemPromoRouteName, ReportOccupancyRouteName,
+		GetOccupancyRouteName, GetSessionsRouteName, StartSessionRouteName, GetSessionRouteName, EndSessionRouteName,
+		VoidSessionRouteName, GetReservationsRouteName, CreateReservationRouteName, GetReservationRouteName,
+		ModifyReservationRouteName, CancelReservationRouteName, GetSessionReceiptRouteName, GetPermitsRouteName,
+		CreatePermitRouteName, GetPermitRouteName, UpdatePermitRouteName, DeletePermitRouteName, GetMerchantsRouteName,
+		CreateMerchantRouteName, GetMerchantRouteName, UpdateMerchantRouteName, DeleteMerchantRouteName,
+		IssueValidationsRouteName, GetMerchantValidationsRouteName, GetMerchantStatementRouteName,
+		RedeemValidationRouteName, GetTimespanPriceRouteName,
+		GetAllRouteMetricsRouteName:
 		return nil
 	}
 	return fmt.Errorf("Invalid route name: %s", routeName)
@@ -328,6 +328,20 @@ func Test_getTimeObjectsFromTimes(t *testing.T) {
 			laterWant:   time.Date(0, time.January, 1, 12, 0, 0, 0, time.UTC),
 			wantErr:     false,
 		},
+		{
+			name:        "End Of Day Validation",
+			times:       []string{"1800", "2400"},
+			earlierWant: time.Date(0, time.January, 1, 18, 0, 0, 0, time.UTC),
+			laterWant:   time.Date(0, time.January, 2, 0, 0, 0, 0, time.UTC),
+			wantErr:     false,
+		},
+		{
+			name:        "Wrap Past Midnight Validation",
+			times:       []string{"2200", "0200"},
+			earlierWant: time.Date(0, time.January, 1, 22, 0, 0, 0, time.UTC),
+			laterWant:   time.Date(0, time.January, 2, 2, 0, 0, 0, time.UTC),
+			wantErr:     false,
+		},
 		{
 			name:    "Earlier Parsing Error",
 			times:   []string{"", "1200"},
@@ -469,6 +483,65 @@ func Test_matchTimespanToRate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:      "UTC Input Match",
+			startTime: time.Date(2017, time.January, 2, 16, 0, 0, 0, time.UTC),
+			endTime:   time.Date(2017, time.January, 2, 17, 0, 0, 0, time.UTC),
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "mon",
+					Times: "0900-1200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			want: types.Rate{
+				UUID:  "0000001",
+				Days:  "mon",
+				Times: "0900-1200",
+				TZ:    "America/Chicago",
+				Price: 1600,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Daylight Time Input Match",
+			startTime: time.Date(2017, time.July, 3, 9, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.July, 3, 12, 0, 0, 0, chi),
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "mon",
+					Times: "0900-1200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			want: types.Rate{
+				UUID:  "0000001",
+				Days:  "mon",
+				Times: "0900-1200",
+				TZ:    "America/Chicago",
+				Price: 1600,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Other Timezone Local Time No Match Error",
+			startTime: time.Date(2017, time.March, 13, 9, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.March, 13, 10, 0, 0, 0, chi),
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "mon",
+					Times: "0900-1000",
+					TZ:    "America/Mexico_City",
+					Price: 1600,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:      "No Match Error",
 			startTime: time.Date(2017, time.January, 2, 7, 0, 0, 0, chi),
@@ -504,64 +577,1135 @@ func Test_matchTimespanToRate(t *testing.T) {
 	}
 }
 
-func Test_getLocationOffset(t *testing.T) {
+func Test_getLocalInstant(t *testing.T) {
 	chi, _ := time.LoadLocation("America/Chicago")
+	syd, _ := time.LoadLocation("Australia/Sydney")
 	tests := []struct {
-		name  string
-		year  int
-		month time.Month
-		day   int
-		loc   *time.Location
-		want  interface{}
+		name string
+		date time.Time
+		hour int
+		min  int
+		loc  *time.Location
+		want time.Time
 	}{
 		{
-			name:  "Chicago CST",
-			year:  2017,
-			month: time.March,
-			day:   11,
-			loc:   chi,
-			want:  -21600,
+			name: "Standard Time",
+			date: time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC),
+			hour: 9,
+			loc:  chi,
+			want: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC),
 		},
 		{
-			name:  "Chicago CDT",
-			year:  2017,
-			month: time.March,
-			day:   12,
-			loc:   chi,
-			want:  -18000,
+			name: "Daylight Time",
+			date: time.Date(2017, time.July, 3, 0, 0, 0, 0, time.UTC),
+			hour: 9,
+			loc:  chi,
+			want: time.Date(2017, time.July, 3, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Before Spring Forward",
+			date: time.Date(2017, time.March, 12, 0, 0, 0, 0, time.UTC),
+			hour: 1,
+			min:  59,
+			loc:  chi,
+			want: time.Date(2017, time.March, 12, 7, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "Nonexistent Time Resolves To Jump",
+			date: time.Date(2017, time.March, 12, 0, 0, 0, 0, time.UTC),
+			hour: 2,
+			min:  30,
+			loc:  chi,
+			want: time.Date(2017, time.March, 12, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "After Spring Forward",
+			date: time.Date(2017, time.March, 12, 0, 0, 0, 0, time.UTC),
+			hour: 3,
+			loc:  chi,
+			want: time.Date(2017, time.March, 12, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Repeated Time Resolves To First Occurrence",
+			date: time.Date(2017, time.November, 5, 0, 0, 0, 0, time.UTC),
+			hour: 1,
+			min:  30,
+			loc:  chi,
+			want: time.Date(2017, time.November, 5, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "After Fall Back",
+			date: time.Date(2017, time.November, 5, 0, 0, 0, 0, time.UTC),
+			hour: 2,
+			loc:  chi,
+			want: time.Date(2017, time.November, 5, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Southern Hemisphere Repeated Time",
+			date: time.Date(2017, time.April, 2, 0, 0, 0, 0, time.UTC),
+			hour: 2,
+			min:  30,
+			loc:  syd,
+			want: time.Date(2017, time.April, 1, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "Southern Hemisphere Nonexistent Time",
+			date: time.Date(2017, time.October, 1, 0, 0, 0, 0, time.UTC),
+			hour: 2,
+			min:  30,
+			loc:  syd,
+			want: time.Date(2017, time.September, 30, 16, 0, 0, 0, time.UTC),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got := getLocationOffset(test.year, test.month, test.day, test.loc)
-			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("getLocationOffset() got = %v, want %v", got, test.want)
+			if got := getLocalInstant(test.date, test.hour, test.min, test.loc); !got.Equal(test.want) {
+				t.Errorf("getLocalInstant() got = %v, want %v", got.UTC(), test.want)
+			}
+		})
+	}
+}
+
+func Test_getRateWindows(t *testing.T) {
+	tests := []struct {
+		name string
+		rate types.Rate
+		from time.Time
+		to   time.Time
+		want []timespan
+	}{
+		{
+			name: "Spring Forward Day Is Short",
+			rate: types.Rate{Days: "sun", Times: "0000-2400", TZ: "America/Chicago"},
+			from: time.Date(2017, time.March, 12, 6, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.March, 12, 7, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.March, 12, 6, 0, 0, 0, time.UTC), end: time.Date(2017, time.March, 13, 5, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Fall Back Day Is Long",
+			rate: types.Rate{Days: "sun", Times: "0000-2400", TZ: "America/Chicago"},
+			from: time.Date(2017, time.November, 5, 5, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.November, 5, 6, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.November, 5, 5, 0, 0, 0, time.UTC), end: time.Date(2017, time.November, 6, 6, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Rate Starting In Spring Forward Gap",
+			rate: types.Rate{Days: "sun", Times: "0230-0600", TZ: "America/Chicago"},
+			from: time.Date(2017, time.March, 12, 7, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.March, 12, 12, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.March, 12, 8, 0, 0, 0, time.UTC), end: time.Date(2017, time.March, 12, 11, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Wrapped Rate Across Fall Back",
+			rate: types.Rate{Days: "sat", Times: "2200-0200", TZ: "America/Chicago"},
+			from: time.Date(2017, time.November, 5, 6, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.November, 5, 7, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.November, 5, 3, 0, 0, 0, time.UTC), end: time.Date(2017, time.November, 5, 8, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Not Yet Effective",
+			rate: types.Rate{Days: "mon", Times: "0900-1200", TZ: "America/Chicago", EffectiveFrom: "2017-01-09"},
+			from: time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.January, 16, 0, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.January, 9, 15, 0, 0, 0, time.UTC), end: time.Date(2017, time.January, 9, 18, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "No Longer Effective",
+			rate: types.Rate{Days: "mon", Times: "0900-1200", TZ: "America/Chicago", EffectiveUntil: "2017-01-09"},
+			from: time.Date(2017, time.January, 2, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.January, 16, 0, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC), end: time.Date(2017, time.January, 2, 18, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Instants In Another Timezone",
+			rate: types.Rate{Days: "mon", Times: "0900-1200", TZ: "America/Chicago"},
+			from: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC),
+			to:   time.Date(2017, time.January, 2, 16, 0, 0, 0, time.UTC),
+			want: []timespan{
+				{start: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC), end: time.Date(2017, time.January, 2, 18, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getRateWindows(test.rate, test.from, test.to)
+			if len(got) != len(test.want) {
+				t.Errorf("getRateWindows() got = %v, want %v", got, test.want)
 				return
 			}
+			for i := range got {
+				if !got[i].start.Equal(test.want[i].start) || !got[i].end.Equal(test.want[i].end) {
+					t.Errorf("getRateWindows() got = %v, want %v", got, test.want)
+				}
+			}
 		})
 	}
 }
 
-func Test_isValidRouteName(t *testing.T) {
+func Test_getRatesForTimezone(t *testing.T) {
+	chicago := types.Rate{UUID: "0000001", Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500}
+	mexico := types.Rate{UUID: "0000002", Days: "mon", Times: "0900-1200", TZ: "America/Mexico_City", Price: 1500}
+	denver := types.Rate{UUID: "0000003", Days: "mon", Times: "0900-1200", TZ: "America/Denver", Price: 1500}
 	tests := []struct {
 		name      string
-		routeName string
+		rates     []types.Rate
+		tz        string
+		startTime time.Time
+		want      []types.Rate
 		wantErr   bool
 	}{
 		{
-			name:      "GetRatesRoute Validation",
-			routeName: GetRatesRouteName,
-			wantErr:   false,
+			name:      "Single Timezone Given In UTC",
+			rates:     []types.Rate{chicago},
+			startTime: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC),
+			want:      []types.Rate{chicago},
 		},
 		{
-			name:      "CreateRateRoute Validation",
-			routeName: CreateRateRouteName,
-			wantErr:   false,
+			name:      "Timezone Inferred From Offset",
+			rates:     []types.Rate{chicago, denver},
+			startTime: time.Date(2017, time.January, 2, 9, 0, 0, 0, time.FixedZone("", -6*60*60)),
+			want:      []types.Rate{chicago},
 		},
 		{
-			name:      "OverwriteRatesRoute Validation",
-			routeName: OverwriteRatesRouteName,
+			name:      "Timezone Given",
+			rates:     []types.Rate{chicago, mexico},
+			tz:        "America/Mexico_City",
+			startTime: time.Date(2017, time.January, 2, 9, 0, 0, 0, time.FixedZone("", -6*60*60)),
+			want:      []types.Rate{mexico},
+		},
+		{
+			name:      "Timezones Sharing An Offset Error",
+			rates:     []types.Rate{chicago, mexico},
+			startTime: time.Date(2017, time.January, 2, 9, 0, 0, 0, time.FixedZone("", -6*60*60)),
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid Timezone Error",
+			rates:     []types.Rate{chicago},
+			tz:        "INVALID",
+			startTime: time.Date(2017, time.January, 2, 15, 0, 0, 0, time.UTC),
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _, err := getRatesForTimezone(test.rates, test.tz, test.startTime)
+			if (err != nil) != test.wantErr {
+				t.Errorf("getRatesForTimezone() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getRatesForTimezone() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_splitTimespanIntoDays(t *testing.T) {
+	chi, _ := time.LoadLocation("America/Chicago")
+	tests := []struct {
+		name      string
+		startTime time.Time
+		endTime   time.Time
+		want      []timespan
+	}{
+		{
+			name:      "Same Day",
+			startTime: time.Date(2017, time.January, 2, 9, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 12, 0, 0, 0, chi),
+			want: []timespan{
+				{start: time.Date(2017, time.January, 2, 9, 0, 0, 0, chi), end: time.Date(2017, time.January, 2, 12, 0, 0, 0, chi)},
+			},
+		},
+		{
+			name:      "Across Year Boundary",
+			startTime: time.Date(2017, time.December, 31, 20, 0, 0, 0, chi),
+			endTime:   time.Date(2018, time.January, 2, 8, 0, 0, 0, chi),
+			want: []timespan{
+				{start: time.Date(2017, time.December, 31, 20, 0, 0, 0, chi), end: time.Date(2018, time.January, 1, 0, 0, 0, 0, chi)},
+				{start: time.Date(2018, time.January, 1, 0, 0, 0, 0, chi), end: time.Date(2018, time.January, 2, 0, 0, 0, 0, chi)},
+				{start: time.Date(2018, time.January, 2, 0, 0, 0, 0, chi), end: time.Date(2018, time.January, 2, 8, 0, 0, 0, chi)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := splitTimespanIntoDays(test.startTime, test.endTime)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("splitTimespanIntoDays() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_splitTimespanAtRates(t *testing.T) {
+	chi, _ := time.LoadLocation("America/Chicago")
+	existingRates := []types.Rate{
+		{UUID: "0000001", Days: "mon", Times: "0600-0900", TZ: "America/Chicago", Price: 1000},
+		{UUID: "0000002", Days: "mon", Times: "0900-2100", TZ: "America/Chicago", Price: 1500},
+		{UUID: "0000003", Days: "mon", Times: "2200-2300", TZ: "America/Chicago", Price: 500},
+		{UUID: "0000004", Days: "mon", Times: "2300-2400", TZ: "America/Chicago", Price: 500},
+		{UUID: "0000005", Days: "tues", Times: "0000-0600", TZ: "America/Chicago", Price: 500},
+		{UUID: "0000006", Days: "wed", Times: "2200-0200", TZ: "America/Chicago", Price: 500},
+		{UUID: "0000007", Days: "thurs", Times: "0200-0900", TZ: "America/Chicago", Price: 500},
+	}
+	tests := []struct {
+		name      string
+		startTime time.Time
+		endTime   time.Time
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "Single Rate",
+			startTime: time.Date(2017, time.January, 2, 10, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 12, 0, 0, 0, chi),
+			want:      []string{"0000002"},
+		},
+		{
+			name:      "Consecutive Rates",
+			startTime: time.Date(2017, time.January, 2, 8, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 10, 0, 0, 0, chi),
+			want:      []string{"0000001", "0000002"},
+		},
+		{
+			name:      "Overnight Across Rates",
+			startTime: time.Date(2017, time.January, 2, 22, 30, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 3, 1, 0, 0, 0, chi),
+			want:      []string{"0000003", "0000004", "0000005"},
+		},
+		{
+			name:      "Wrapped Rate Belongs To The Day It Starts On",
+			startTime: time.Date(2017, time.January, 4, 23, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 5, 3, 0, 0, 0, chi),
+			want:      []string{"0000006", "0000007"},
+		},
+		{
+			name:      "Gap Between Rates Error",
+			startTime: time.Date(2017, time.January, 2, 20, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 23, 0, 0, 0, chi),
+			wantErr:   "unavailable: no rate covers 2017-01-02T21:00:00-06:00 - 2017-01-02T22:00:00-06:00",
+		},
+		{
+			name:      "Uncovered Start And End Error",
+			startTime: time.Date(2017, time.January, 2, 5, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 22, 30, 0, 0, chi),
+			wantErr:   "unavailable: no rate covers 2017-01-02T05:00:00-06:00 - 2017-01-02T06:00:00-06:00, 2017-01-02T21:00:00-06:00 - 2017-01-02T22:00:00-06:00",
+		},
+		{
+			name:      "Uncovered Across Midnight Error",
+			startTime: time.Date(2017, time.January, 1, 23, 0, 0, 0, chi),
+			endTime:   time.Date(2017, time.January, 2, 7, 0, 0, 0, chi),
+			wantErr:   "unavailable: no rate covers 2017-01-01T23:00:00-06:00 - 2017-01-02T06:00:00-06:00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := splitTimespanAtRates(test.startTime, test.endTime, existingRates)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("splitTimespanAtRates() error = %v, want %s", err, test.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Errorf("splitTimespanAtRates() error = %v", err)
+				return
+			}
+
+			var gotUUIDs []string
+			for i, segment := range got {
+				gotUUIDs = append(gotUUIDs, segment.rate.UUID)
+				if i > 0 && !got[i-1].end.Equal(segment.start) {
+					t.Errorf("splitTimespanAtRates() segment %d starts at %v, want %v", i, segment.start, got[i-1].end)
+				}
+			}
+			if !reflect.DeepEqual(gotUUIDs, test.want) {
+				t.Errorf("splitTimespanAtRates() got = %v, want %v", gotUUIDs, test.want)
+			}
+		})
+	}
+}
+
+func Test_getBillableUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  time.Duration
+		increment int
+		rounding  string
+		want      int
+	}{
+		{
+			name:      "Per Started Hour",
+			duration:  80 * time.Minute,
+			increment: 60,
+			rounding:  roundUp,
+			want:      2,
+		},
+		{
+			name:      "Per Minute",
+			duration:  80 * time.Minute,
+			increment: 1,
+			rounding:  roundUp,
+			want:      80,
+		},
+		{
+			name:      "Per Quarter Hour Rounded Down",
+			duration:  40 * time.Minute,
+			increment: 15,
+			rounding:  roundDown,
+			want:      2,
+		},
+		{
+			name:      "Per Quarter Hour Rounded To Nearest",
+			duration:  40 * time.Minute,
+			increment: 15,
+			rounding:  roundNearest,
+			want:      3,
+		},
+		{
+			name:      "Exact Increments",
+			duration:  45 * time.Minute,
+			increment: 15,
+			rounding:  roundUp,
+			want:      3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getBillableUnits(test.duration, test.increment, test.rounding); got != test.want {
+				t.Errorf("getBillableUnits() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_getUnitsPrice(t *testing.T) {
+	tests := []struct {
+		name         string
+		pricePerHour int
+		units        int
+		increment    int
+		want         int
+	}{
+		{
+			name:         "Hours",
+			pricePerHour: 1500,
+			units:        2,
+			increment:    60,
+			want:         3000,
+		},
+		{
+			name:         "Quarter Hours",
+			pricePerHour: 1500,
+			units:        3,
+			increment:    15,
+			want:         1125,
+		},
+		{
+			name:         "Minutes Rounded To Nearest Cent",
+			pricePerHour: 1000,
+			units:        1,
+			increment:    1,
+			want:         17,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getUnitsPrice(test.pricePerHour, test.units, test.increment); got != test.want {
+				t.Errorf("getUnitsPrice() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_getTieredPrice(t *testing.T) {
+	tiers := []types.PriceTier{{From: 0, Until: 60, Price: 400}, {From: 60, Until: 180, Price: 200}, {From: 240, Price: 50}}
+	tests := []struct {
+		name      string
+		rate      types.Rate
+		units     int
+		increment int
+		elapsed   int
+		want      int
+		wantTiers []types.TierCharge
+	}{
+		{
+			name:      "Untiered Rate",
+			rate:      types.Rate{Price: 1000},
+			units:     2,
+			increment: 60,
+			want:      2000,
+		},
+		{
+			name:      "First Tier Only",
+			rate:      types.Rate{Price: 100, Tiers: tiers},
+			units:     1,
+			increment: 60,
+			want:      400,
+			wantTiers: []types.TierCharge{{Tier: 1, From: 0, Until: 60, BillableUnits: 1, Price: 400}},
+		},
+		{
+			name:      "Every Tier And Untiered Time",
+			rate:      types.Rate{Price: 100, Tiers: tiers},
+			units:     5,
+			increment: 60,
+			want:      950,
+			wantTiers: []types.TierCharge{
+				{Tier: 1, From: 0, Until: 60, BillableUnits: 1, Price: 400},
+				{Tier: 2, From: 60, Until: 180, BillableUnits: 2, Price: 400},
+				{Tier: 0, From: 180, Until: 240, BillableUnits: 1, Price: 100},
+				{Tier: 3, From: 240, Until: 300, BillableUnits: 1, Price: 50},
+			},
+		},
+		{
+			name:      "Continues From Elapsed Minutes",
+			rate:      types.Rate{Price: 100, Tiers: tiers},
+			units:     4,
+			increment: 15,
+			elapsed:   45,
+			want:      250,
+			wantTiers: []types.TierCharge{
+				{Tier: 1, From: 45, Until: 60, BillableUnits: 1, Price: 100},
+				{Tier: 2, From: 60, Until: 105, BillableUnits: 3, Price: 150},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotTiers := getTieredPrice(test.rate, test.units, test.increment, test.elapsed)
+			if got != test.want {
+				t.Errorf("getTieredPrice() got = %v, want %v", got, test.want)
+			}
+			if !reflect.DeepEqual(gotTiers, test.wantTiers) {
+				t.Errorf("getTieredPrice() got tiers = %v, want %v", gotTiers, test.wantTiers)
+			}
+		})
+	}
+}
+
+func Test_applyPriceLimits(t *testing.T) {
+	chi, _ := time.LoadLocation("America/Chicago")
+	tests := []struct {
+		name            string
+		rate            types.Rate
+		lot             types.Lot
+		wantDays        []types.DaySubtotal
+		wantTotal       int
+		wantCap         bool
+		wantFloor       bool
+		wantAdjustments []types.PriceAdjustment
+	}{
+		{
+			name:      "No Limits",
+			rate:      types.Rate{UUID: "a"},
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-06", Price: 4000}, {Date: "2017-01-07", Price: 2000}},
+			wantTotal: 6000,
+		},
+		{
+			name:            "Rate Daily Max",
+			rate:            types.Rate{UUID: "a", DailyMax: 2500},
+			wantDays:        []types.DaySubtotal{{Date: "2017-01-06", Price: 2500}, {Date: "2017-01-07", Price: 2000}},
+			wantTotal:       4500,
+			wantCap:         true,
+			wantAdjustments: []types.PriceAdjustment{{Type: "dailyMax", Source: "rate", RateUUID: "a", Date: "2017-01-06", Amount: -1500}},
+		},
+		{
+			name:            "Rate Min Charge",
+			rate:            types.Rate{UUID: "a", MinCharge: 8000},
+			wantDays:        []types.DaySubtotal{{Date: "2017-01-06", Price: 6000}, {Date: "2017-01-07", Price: 2000}},
+			wantTotal:       8000,
+			wantFloor:       true,
+			wantAdjustments: []types.PriceAdjustment{{Type: "minCharge", Source: "rate", RateUUID: "a", Date: "2017-01-06", Amount: 2000}},
+		},
+		{
+			name:            "Lot Daily Max",
+			rate:            types.Rate{UUID: "a"},
+			lot:             types.Lot{DailyMax: 3000},
+			wantDays:        []types.DaySubtotal{{Date: "2017-01-06", Price: 3000}, {Date: "2017-01-07", Price: 2000}},
+			wantTotal:       5000,
+			wantCap:         true,
+			wantAdjustments: []types.PriceAdjustment{{Type: "dailyMax", Source: "lot", Date: "2017-01-06", Amount: -1000}},
+		},
+		{
+			name:            "Lot Min Charge",
+			rate:            types.Rate{UUID: "a"},
+			lot:             types.Lot{MinCharge: 7000},
+			wantDays:        []types.DaySubtotal{{Date: "2017-01-06", Price: 5000}, {Date: "2017-01-07", Price: 2000}},
+			wantTotal:       7000,
+			wantFloor:       true,
+			wantAdjustments: []types.PriceAdjustment{{Type: "minCharge", Source: "lot", Date: "2017-01-06", Amount: 1000}},
+		},
+		{
+			name:      "Rate Daily Max Then Lot Min Charge",
+			rate:      types.Rate{UUID: "a", DailyMax: 1000},
+			lot:       types.Lot{MinCharge: 2500},
+			wantDays:  []types.DaySubtotal{{Date: "2017-01-06", Price: 1500}, {Date: "2017-01-07", Price: 1000}},
+			wantTotal: 2500,
+			wantCap:   true,
+			wantFloor: true,
+			wantAdjustments: []types.PriceAdjustment{
+				{Type: "dailyMax", Source: "rate", RateUUID: "a", Date: "2017-01-06", Amount: -3000},
+				{Type: "dailyMax", Source: "rate", RateUUID: "a", Date: "2017-01-07", Amount: -1000},
+				{Type: "minCharge", Source: "lot", Date: "2017-01-06", Amount: 500},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			segments := []rateSegment{
+				{rate: test.rate, start: time.Date(2017, 1, 6, 10, 0, 0, 0, chi), end: time.Date(2017, 1, 6, 14, 0, 0, 0, chi)},
+				{rate: test.rate, start: time.Date(2017, 1, 7, 0, 0, 0, 0, chi), end: time.Date(2017, 1, 7, 2, 0, 0, 0, chi)},
+			}
+			quote := types.Quote{
+				Total:    6000,
+				Days:     []types.DaySubtotal{{Date: "2017-01-06", Price: 4000}, {Date: "2017-01-07", Price: 2000}},
+				Segments: []types.PriceSegment{{RateUUID: "a", Price: 4000}, {RateUUID: "a", Price: 2000}},
+			}
+
+			got := applyPriceLimits(quote, segments, test.lot, chi)
+			if !reflect.DeepEqual(got.Days, test.wantDays) || got.Total != test.wantTotal {
+				t.Errorf("applyPriceLimits() got days %v and total %d, want %v and %d", got.Days, got.Total, test.wantDays, test.wantTotal)
+			}
+			if got.CapApplied != test.wantCap || got.FloorApplied != test.wantFloor {
+				t.Errorf("applyPriceLimits() got cap %v and floor %v, want %v and %v", got.CapApplied, got.FloorApplied, test.wantCap, test.wantFloor)
+			}
+			if !reflect.DeepEqual(got.Adjustments, test.wantAdjustments) {
+				t.Errorf("applyPriceLimits() got adjustments %v, want %v", got.Adjustments, test.wantAdjustments)
+			}
+		})
+	}
+}
+
+func Test_getLocationOffset(t *testing.T) {
+	chi, _ := time.LoadLocation("America/Chicago")
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		day   int
+		loc   *time.Location
+		want  interface{}
+	}{
+		{
+			name:  "Chicago CST",
+			year:  2017,
+			month: time.March,
+			day:   11,
+			loc:   chi,
+			want:  -21600,
+		},
+		{
+			name:  "Chicago CDT",
+			year:  2017,
+			month: time.March,
+			day:   12,
+			loc:   chi,
+			want:  -18000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getLocationOffset(test.year, test.month, test.day, test.loc)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getLocationOffset() got = %v, want %v", got, test.want)
+				return
+			}
+		})
+	}
+}
+
+func Test_resolveOverrides(t *testing.T) {
+	calendars := []types.Calendar{{Name: "holidays", Dates: []string{"2017-07-04", "2017-11-23"}}}
+	tests := []struct {
+		name  string
+		rates []types.Rate
+		want  []types.Rate
+	}{
+		{
+			name:  "Weekday Rates Only",
+			rates: []types.Rate{{Days: "mon", TZ: "America/Chicago"}},
+			want:  []types.Rate{{Days: "mon", TZ: "America/Chicago"}},
+		},
+		{
+			name: "Calendar And Dates Override Weekday Rates",
+			rates: []types.Rate{
+				{Days: "mon", TZ: "America/Chicago"},
+				{Calendar: "holidays", Dates: "2017-12-25", TZ: "America/Chicago"},
+				{Days: "mon", TZ: "America/New_York"},
+			},
+			want: []types.Rate{
+				{Days: "mon", TZ: "America/Chicago", ExceptDates: "2017-12-25,2017-07-04,2017-11-23"},
+				{Calendar: "holidays", Dates: "2017-12-25,2017-07-04,2017-11-23", TZ: "America/Chicago"},
+				{Days: "mon", TZ: "America/New_York"},
+			},
+		},
+		{
+			name: "Overrides Only Apply Within Their Lot And Vehicle Class",
+			rates: []types.Rate{
+				{Days: "mon", TZ: "America/Chicago"},
+				{Days: "mon", LotID: "garage", TZ: "America/Chicago"},
+				{Days: "mon", VehicleClass: "motorcycle", TZ: "America/Chicago"},
+				{Dates: "2017-12-25", VehicleClass: "motorcycle", TZ: "America/Chicago"},
+			},
+			want: []types.Rate{
+				{Days: "mon", TZ: "America/Chicago"},
+				{Days: "mon", LotID: "garage", TZ: "America/Chicago"},
+				{Days: "mon", VehicleClass: "motorcycle", TZ: "America/Chicago", ExceptDates: "2017-12-25"},
+				{Dates: "2017-12-25", VehicleClass: "motorcycle", TZ: "America/Chicago"},
+			},
+		},
+		{
+			name: "Partial Day Overrides Only Except Overlapping Weekday Rates",
+			rates: []types.Rate{
+				{Days: "mon", Times: "0600-0900", TZ: "America/Chicago"},
+				{Days: "mon", Times: "0900-2100", TZ: "America/Chicago"},
+				{Days: "sun", Times: "2200-0200", TZ: "America/Chicago"},
+				{Dates: "2017-12-25", Times: "0000-1000", TZ: "America/Chicago"},
+			},
+			want: []types.Rate{
+				{Days: "mon", Times: "0600-0900", TZ: "America/Chicago", ExceptDates: "2017-12-25"},
+				{Days: "mon", Times: "0900-2100", TZ: "America/Chicago", ExceptDates: "2017-12-25"},
+				{Days: "sun", Times: "2200-0200", TZ: "America/Chicago", ExceptDates: "2017-12-24"},
+				{Dates: "2017-12-25", Times: "0000-1000", TZ: "America/Chicago"},
+			},
+		},
+		{
+			name: "Partial Day Override Outside Weekday Rate Hours",
+			rates: []types.Rate{
+				{Days: "mon", Times: "0600-0900", TZ: "America/Chicago"},
+				{Dates: "2017-12-25", Times: "2200-0200", TZ: "America/Chicago"},
+				{Days: "tues", Times: "0100-0300", TZ: "America/Chicago"},
+			},
+			want: []types.Rate{
+				{Days: "mon", Times: "0600-0900", TZ: "America/Chicago"},
+				{Dates: "2017-12-25", Times: "2200-0200", TZ: "America/Chicago"},
+				{Days: "tues", Times: "0100-0300", TZ: "America/Chicago", ExceptDates: "2017-12-26"},
+			},
+		},
+		{
+			name:  "Missing Calendar Adds No Dates",
+			rates: []types.Rate{{Days: "mon", TZ: "America/Chicago"}, {Calendar: "missing", TZ: "America/Chicago"}},
+			want:  []types.Rate{{Days: "mon", TZ: "America/Chicago"}, {Calendar: "missing", TZ: "America/Chicago"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resolveOverrides(test.rates, calendars); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("resolveOverrides() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_effectiveDatesIntersect(t *testing.T) {
+	tests := []struct {
+		name   string
+		aFrom  string
+		aUntil string
+		bFrom  string
+		bUntil string
+		want   bool
+	}{
+		{
+			name: "Both Indefinite",
+			want: true,
+		},
+		{
+			name:   "One Indefinite",
+			aFrom:  "2017-01-01",
+			aUntil: "2017-02-01",
+			want:   true,
+		},
+		{
+			name:   "Consecutive",
+			aUntil: "2017-02-01",
+			bFrom:  "2017-02-01",
+			want:   false,
+		},
+		{
+			name:   "Consecutive Reversed",
+			aFrom:  "2017-02-01",
+			bUntil: "2017-02-01",
+			want:   false,
+		},
+		{
+			name:   "Intersecting",
+			aFrom:  "2017-01-01",
+			aUntil: "2017-02-01",
+			bFrom:  "2017-01-31",
+			bUntil: "2017-03-01",
+			want:   true,
+		},
+		{
+			name:   "Disjoint",
+			aFrom:  "2017-01-01",
+			aUntil: "2017-02-01",
+			bFrom:  "2017-03-01",
+			bUntil: "2017-04-01",
+			want:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := effectiveDatesIntersect(test.aFrom, test.aUntil, test.bFrom, test.bUntil); got != test.want {
+				t.Errorf("effectiveDatesIntersect() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_getUpcomingRateChanges(t *testing.T) {
+	old := types.Rate{UUID: "0000001", Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500, EffectiveUntil: "2017-03-01"}
+	next := types.Rate{UUID: "0000002", Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1800, EffectiveFrom: "2017-03-01"}
+	past := types.Rate{UUID: "0000003", Days: "tues", Times: "0900-1200", TZ: "America/Chicago", Price: 1500, EffectiveFrom: "2017-01-01"}
+	always := types.Rate{UUID: "0000004", Days: "wed", Times: "0900-1200", TZ: "America/Chicago", Price: 1500}
+	tests := []struct {
+		name  string
+		rates []types.Rate
+		now   time.Time
+		want  []types.RateChange
+	}{
+		{
+			name:  "Scheduled Price Change",
+			rates: []types.Rate{next, old, past, always},
+			now:   time.Date(2017, time.February, 1, 12, 0, 0, 0, time.UTC),
+			want: []types.RateChange{
+				{Date: "2017-03-01", Change: rateEnds, Rate: old},
+				{Date: "2017-03-01", Change: rateStarts, Rate: next},
+			},
+		},
+		{
+			name:  "Change Already Happened In Rate's Timezone",
+			rates: []types.Rate{next, old},
+			now:   time.Date(2017, time.March, 1, 6, 0, 0, 0, time.UTC),
+			want:  nil,
+		},
+		{
+			name:  "Change Not Yet Happened In Rate's Timezone",
+			rates: []types.Rate{next},
+			now:   time.Date(2017, time.March, 1, 5, 0, 0, 0, time.UTC),
+			want:  []types.RateChange{{Date: "2017-03-01", Change: rateStarts, Rate: next}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getUpcomingRateChanges(test.rates, test.now); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getUpcomingRateChanges() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_isValidRouteName(t *testing.T) {
+	tests := []struct {
+		name      string
+		routeName string
+		wantErr   bool
+	}{
+		{
+			name:      "GetRatesRoute Validation",
+			routeName: GetRatesRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreateRateRoute Validation",
+			routeName: CreateRateRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "OverwriteRatesRoute Validation",
+			routeName: OverwriteRatesRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetUpcomingRateChangesRoute Validation",
+			routeName: GetUpcomingRateChangesRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetRateRoute Validation",
+			routeName: GetRateRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdateRateRoute Validation",
+			routeName: UpdateRateRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "PatchRateRoute Validation",
+			routeName: PatchRateRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeleteRateRoute Validation",
+			routeName: DeleteRateRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetCalendarsRoute Validation",
+			routeName: GetCalendarsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetCalendarRoute Validation",
+			routeName: GetCalendarRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "PutCalendarRoute Validation",
+			routeName: PutCalendarRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "ImportCalendarRoute Validation",
+			routeName: ImportCalendarRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeleteCalendarRoute Validation",
+			routeName: DeleteCalendarRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetLotsRoute Validation",
+			routeName: GetLotsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreateLotRoute Validation",
+			routeName: CreateLotRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetLotRoute Validation",
+			routeName: GetLotRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdateLotRoute Validation",
+			routeName: UpdateLotRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeleteLotRoute Validation",
+			routeName: DeleteLotRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetProductsRoute Validation",
+			routeName: GetProductsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreateProductRoute Validation",
+			routeName: CreateProductRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetProductRoute Validation",
+			routeName: GetProductRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdateProductRoute Validation",
+			routeName: UpdateProductRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeleteProductRoute Validation",
+			routeName: DeleteProductRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetPromosRoute Validation",
+			routeName: GetPromosRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreatePromoRoute Validation",
+			routeName: CreatePromoRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetPromoRoute Validation",
+			routeName: GetPromoRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdatePromoRoute Validation",
+			routeName: UpdatePromoRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeletePromoRoute Validation",
+			routeName: DeletePromoRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "RedeemPromoRoute Validation",
+			routeName: RedeemPromoRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "ReportOccupancyRoute Validation",
+			routeName: ReportOccupancyRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetOccupancyRoute Validation",
+			routeName: GetOccupancyRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetSessionsRoute Validation",
+			routeName: GetSessionsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "StartSessionRoute Validation",
+			routeName: StartSessionRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetSessionRoute Validation",
+			routeName: GetSessionRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "EndSessionRoute Validation",
+			routeName: EndSessionRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "VoidSessionRoute Validation",
+			routeName: VoidSessionRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetReservationsRoute Validation",
+			routeName: GetReservationsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreateReservationRoute Validation",
+			routeName: CreateReservationRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetReservationRoute Validation",
+			routeName: GetReservationRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "ModifyReservationRoute Validation",
+			routeName: ModifyReservationRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CancelReservationRoute Validation",
+			routeName: CancelReservationRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetSessionReceiptRoute Validation",
+			routeName: GetSessionReceiptRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetPermitsRoute Validation",
+			routeName: GetPermitsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreatePermitRoute Validation",
+			routeName: CreatePermitRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetPermitRoute Validation",
+			routeName: GetPermitRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdatePermitRoute Validation",
+			routeName: UpdatePermitRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeletePermitRoute Validation",
+			routeName: DeletePermitRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetMerchantsRoute Validation",
+			routeName: GetMerchantsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "CreateMerchantRoute Validation",
+			routeName: CreateMerchantRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetMerchantRoute Validation",
+			routeName: GetMerchantRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "UpdateMerchantRoute Validation",
+			routeName: UpdateMerchantRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "DeleteMerchantRoute Validation",
+			routeName: DeleteMerchantRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "IssueValidationsRoute Validation",
+			routeName: IssueValidationsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetMerchantValidationsRoute Validation",
+			routeName: GetMerchantValidationsRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "GetMerchantStatementRoute Validation",
+			routeName: GetMerchantStatementRouteName,
+			wantErr:   false,
+		},
+		{
+			name:      "RedeemValidationRoute Validation",
+			routeName: RedeemValidationRouteName,
 			wantErr:   false,
 		},
 		{
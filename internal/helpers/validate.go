@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"charlie-parker/internal/config"
 	"charlie-parker/pkg/types"
 	"errors"
 	"fmt"
@@ -9,12 +10,26 @@ import (
 	"time"
 )
 
+// ErrInvalidInput is wrapped by the errors for input that can never be priced, such
+// as a time range that does not parse
+var ErrInvalidInput = errors.New("invalid input")
+
+// maxStayDays is the most days a stay may span, which keeps the work of pricing
+// a stay day by day bounded
+const maxStayDays = 366
+
 // validateCreateRateInput validates a CreateRateInput object and allows for
 // optional validation of the inputs against existing rates for overlap
 func validateCreateRateInput(in *types.CreateRateInput, checkOverlap bool) error {
 	var err error
 
-	if err = validatePrice(in.Price); err != nil {
+	override := in.Dates != "" || in.Calendar != ""
+	if override {
+		// an override may make parking free on its dates
+		if in.Price < 0 {
+			return errors.New("price must not be negative")
+		}
+	} else if err = validatePrice(in.Price); err != nil {
 		return err
 	}
 
@@ -22,7 +37,23 @@ func validateCreateRateInput(in *types.CreateRateInput, checkOverlap bool) error
 		return err
 	}
 
-	if err = validateDays(in.Days); err != nil {
+	if in.LotID != "" {
+		if _, err = GetLot(in.LotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+		}
+	}
+
+	if in.VehicleClass != "" {
+		if err = isValidVehicleClass(in.VehicleClass); err != nil {
+			return err
+		}
+	}
+
+	if override {
+		if err = validateOverrideTargets(in.Days, in.Dates, in.Calendar); err != nil {
+			return err
+		}
+	} else if err = validateDays(in.Days); err != nil {
 		return err
 	}
 
@@ -30,13 +61,40 @@ func validateCreateRateInput(in *types.CreateRateInput, checkOverlap bool) error
 		return err
 	}
 
+	if err = validateBilling(in.BillingIncrement, in.Rounding); err != nil {
+		return err
+	}
+
+	if err = validateTiers(in.Tiers, in.BillingIncrement); err != nil {
+		return err
+	}
+
+	if err = validatePriceLimits(in.DailyMax, in.MinCharge); err != nil {
+		return err
+	}
+
+	if err = validateGracePeriods(in.EntryGrace, in.ExitGrace, in.FreeMinutes); err != nil {
+		return err
+	}
+
+	if err = validateEffectiveDates(in.EffectiveFrom, in.EffectiveUntil); err != nil {
+		return err
+	}
+
 	if checkOverlap {
-		var rates []types.Rate
+		var (
+			rates     []types.Rate
+			calendars []types.Calendar
+		)
 		if rates, err = GetRates(); err != nil {
 			return err
 		}
 
-		if err = validateAgainstExistingRates(rates, *in); err != nil {
+		if calendars, err = GetCalendars(); err != nil {
+			return err
+		}
+
+		if err = validateAgainstExistingRates(rates, calendars, *in); err != nil {
 			return err
 		}
 	}
@@ -44,9 +102,406 @@ func validateCreateRateInput(in *types.CreateRateInput, checkOverlap bool) error
 	return err
 }
 
+// validateUpdateRateInput validates a CreateRateInput that replaces the rate with the
+// given uuid. Overlap is checked against every existing rate except the one being replaced
+func validateUpdateRateInput(uuid string, in *types.CreateRateInput) error {
+	var (
+		err       error
+		rates     []types.Rate
+		calendars []types.Calendar
+	)
+
+	if err = validateCreateRateInput(in, false); err != nil {
+		return err
+	}
+
+	if rates, err = GetRates(); err != nil {
+		return err
+	}
+
+	if calendars, err = GetCalendars(); err != nil {
+		return err
+	}
+
+	return validateAgainstExistingRates(ratesExcept(rates, uuid), calendars, *in)
+}
+
+// validateOverrideTargets validates the dates and calendar an override rate targets.
+// An override rate replaces weekday rates on its dates, so it cannot also have days
+func validateOverrideTargets(days, dates, calendar string) error {
+	if days != "" {
+		return errors.New("a rate targets either days or dates and a calendar, not both")
+	}
+
+	if dates != "" {
+		if err := validateDates(dates); err != nil {
+			return err
+		}
+	}
+
+	if calendar != "" {
+		if _, err := GetCalendar(calendar); err != nil {
+			return fmt.Errorf("could not find calendar %s: %v", calendar, err)
+		}
+	}
+
+	return nil
+}
+
+// validateDates validates that dates in a comma separated list are in the
+// format YYYY-MM-DD and that there are no repeated dates
+func validateDates(dates string) error {
+	seen := make(map[string]bool)
+	for _, date := range strings.Split(dates, ",") {
+		if _, err := time.Parse(effectiveDateLayout, date); err != nil {
+			return fmt.Errorf("dates must be in the format YYYY-MM-DD: %s", date)
+		}
+
+		if seen[date] {
+			return fmt.Errorf("%s is repeated in dates", date)
+		}
+		seen[date] = true
+	}
+	return nil
+}
+
+// validateCalendar validates a calendar's name and dates
+func validateCalendar(calendar types.Calendar) error {
+	if strings.TrimSpace(calendar.Name) == "" {
+		return errors.New("specify a calendar name")
+	}
+
+	for _, date := range calendar.Dates {
+		if _, err := time.Parse(effectiveDateLayout, date); err != nil {
+			return fmt.Errorf("dates must be in the format YYYY-MM-DD: %s", date)
+		}
+	}
+
+	return nil
+}
+
+// validateLotInput validates a LotInput object
+func validateLotInput(in *types.LotInput) error {
+	if strings.TrimSpace(in.Name) == "" {
+		return errors.New("specify a lot name")
+	}
+	if err := validatePriceLimits(in.DailyMax, in.MinCharge); err != nil {
+		return err
+	}
+	if err := validateGracePeriods(in.EntryGrace, in.ExitGrace, in.FreeMinutes); err != nil {
+		return err
+	}
+	if err := validateSurgeRules(in.Capacity, in.SurgeRules, in.MaxSurge); err != nil {
+		return err
+	}
+	if err := validateReservationSlots(in.ReservationCapacity, in.ReservationSlots); err != nil {
+		return err
+	}
+	if err := validateCancellationRules(in.CancellationRules); err != nil {
+		return err
+	}
+	return validateTaxes(in.Taxes)
+}
+
+// validateTaxes validates that a lot's taxes are named and have rates between 0.01% and 100%
+func validateTaxes(taxes []types.Tax) error {
+	for i, tax := range taxes {
+		if strings.TrimSpace(tax.Name) == "" {
+			return fmt.Errorf("specify a name for tax %d", i+1)
+		}
+
+		if tax.Rate < 1 || tax.Rate > taxRateUnit {
+			return fmt.Errorf("tax %s must have a rate between 1 and %d hundredths of a percent", tax.Name, taxRateUnit)
+		}
+	}
+
+	return nil
+}
+
+// validateProductInput validates a ProductInput object
+func validateProductInput(in *types.ProductInput) error {
+	var err error
+
+	if strings.TrimSpace(in.Name) == "" {
+		return errors.New("specify a product name")
+	}
+
+	if in.LotID != "" {
+		if _, err = GetLot(in.LotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+		}
+	}
+
+	if in.VehicleClass != "" {
+		if err = isValidVehicleClass(in.VehicleClass); err != nil {
+			return err
+		}
+	}
+
+	if err = validateDays(in.Days); err != nil {
+		return err
+	}
+
+	if err = validateTimespan(in.EntryWindow); err != nil {
+		return fmt.Errorf("entry window: %v", err)
+	}
+
+	if err = validateTimespan(in.ExitWindow); err != nil {
+		return fmt.Errorf("exit window: %v", err)
+	}
+
+	if err = validateTimeZone(in.TZ); err != nil {
+		return err
+	}
+
+	return validatePrice(in.Price)
+}
+
+// validatePromoInput validates the fields of a promo and that its code is not the code
+// of any of the existing promos, regardless of case
+func validatePromoInput(in *types.PromoInput, existingPromos []types.Promo) error {
+	var err error
+
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return errors.New("specify a promo code")
+	} else if strings.ContainsAny(code, " \t") {
+		return fmt.Errorf("promo code cannot contain spaces: %s", code)
+	}
+
+	if existing, found := findPromo(existingPromos, code); found {
+		return fmt.Errorf("promo code %s is already used by promo %s", code, existing.UUID)
+	}
+
+	switch in.Type {
+	case promoPercent:
+		if in.Value < 1 || in.Value > 100 {
+			return fmt.Errorf("percent promos must take between 1 and 100 percent off: %d", in.Value)
+		}
+	case promoAmount:
+		if in.Value < 1 {
+			return fmt.Errorf("amount promos must take at least 1 cent off: %d", in.Value)
+		}
+	default:
+		return fmt.Errorf("promo type must be %s or %s: %s", promoPercent, promoAmount, in.Type)
+	}
+
+	if in.LotID != "" {
+		if _, err = GetLot(in.LotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+		}
+	}
+
+	if in.Days != "" {
+		if err = validateDays(in.Days); err != nil {
+			return err
+		}
+	}
+
+	if err = validateTimeZone(in.TZ); err != nil {
+		return err
+	}
+
+	if err = validateEffectiveDates(in.EffectiveFrom, in.EffectiveUntil); err != nil {
+		return err
+	}
+
+	if in.MaxUses < 0 {
+		return fmt.Errorf("max uses must not be negative: %d", in.MaxUses)
+	}
+
+	return nil
+}
+
+// validatePermitInput validates a PermitInput object
+func validatePermitInput(in *types.PermitInput) error {
+	var err error
+
+	if strings.TrimSpace(in.Holder) == "" {
+		return errors.New("specify who holds the permit")
+	}
+
+	if len(in.Plates) == 0 {
+		return errors.New("specify at least one plate the permit covers")
+	}
+	for _, plate := range in.Plates {
+		if normalizePlate(plate) == "" {
+			return errors.New("permit plates cannot be empty")
+		}
+	}
+
+	for _, lotID := range in.LotIDs {
+		if _, err = GetLot(lotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", lotID, err)
+		}
+	}
+
+	if err = validateDays(in.Days); err != nil {
+		return err
+	}
+
+	if in.Times != "" {
+		if err = validateTimespan(in.Times); err != nil {
+			return err
+		}
+	}
+
+	if err = validateTimeZone(in.TZ); err != nil {
+		return err
+	}
+
+	if in.ValidFrom == "" || in.ValidUntil == "" {
+		return errors.New("specify the dates the permit is valid from and until")
+	}
+
+	return validateEffectiveDates(in.ValidFrom, in.ValidUntil)
+}
+
+// validateMerchantInput validates a MerchantInput object
+func validateMerchantInput(in *types.MerchantInput) error {
+	var err error
+
+	if strings.TrimSpace(in.Name) == "" {
+		return errors.New("specify a merchant name")
+	}
+
+	for _, lotID := range in.LotIDs {
+		if _, err = GetLot(lotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", lotID, err)
+		}
+	}
+
+	if in.Budget < 0 {
+		return fmt.Errorf("budget must not be negative: %d", in.Budget)
+	}
+
+	return validateTimeZone(in.TZ)
+}
+
+// maxValidationsPerIssue is the most validations that can be issued at once
+const maxValidationsPerIssue = 500
+
+// validateIssueValidationsInput validates an IssueValidationsInput object
+func validateIssueValidationsInput(in *types.IssueValidationsInput) error {
+	switch in.Type {
+	case validationMinutes:
+		if in.Value < 1 {
+			return fmt.Errorf("minutes validations must take at least 1 minute off: %d", in.Value)
+		}
+	case validationAmount:
+		if in.Value < 1 {
+			return fmt.Errorf("amount validations must take at least 1 cent off: %d", in.Value)
+		}
+	default:
+		return fmt.Errorf("validation type must be %s or %s: %s", validationMinutes, validationAmount, in.Type)
+	}
+
+	// a count of 0 is one left out, which issues a single validation
+	if in.Count < 0 || in.Count > maxValidationsPerIssue {
+		return fmt.Errorf("count must be between 1 and %d, or 0 to issue 1 validation: %d", maxValidationsPerIssue, in.Count)
+	}
+
+	if in.ValidUntil != "" {
+		if _, err := time.Parse(effectiveDateLayout, in.ValidUntil); err != nil {
+			return fmt.Errorf("valid until must be a date in the format YYYY-MM-DD: %s", in.ValidUntil)
+		}
+	}
+
+	return nil
+}
+
+// validateRedemption validates that validation, given out by merchant, can be redeemed
+// by session at now
+func validateRedemption(validation types.Validation, merchant types.Merchant, session types.Session, now time.Time) error {
+	if session.State != sessionActive {
+		return fmt.Errorf("session %s is %s, only active sessions can redeem validations", session.UUID, session.State)
+	}
+
+	if validation.State != validationIssued {
+		return fmt.Errorf("validation %s has already been redeemed", validation.Code)
+	}
+
+	if len(merchant.LotIDs) > 0 && !containsString(merchant.LotIDs, session.LotID) {
+		return fmt.Errorf("validation %s is not good in lot %s", validation.Code, session.LotID)
+	}
+
+	if loc, err := time.LoadLocation(merchant.TZ); err == nil && validation.ValidUntil != "" && now.In(loc).Format(effectiveDateLayout) >= validation.ValidUntil {
+		return fmt.Errorf("validation %s expired on %s", validation.Code, validation.ValidUntil)
+	}
+
+	return nil
+}
+
+// validateStartSessionInput validates a StartSessionInput and that the vehicle does not
+// already have an active session in the lot
+func validateStartSessionInput(in *types.StartSessionInput, existingSessions []types.Session) error {
+	var err error
+
+	if normalizePlate(in.Plate) == "" {
+		return errors.New("specify a plate")
+	}
+
+	if in.LotID != "" {
+		if _, err = GetLot(in.LotID); err != nil {
+			return fmt.Errorf("could not find lot %s: %v", in.LotID, err)
+		}
+	}
+
+	if in.VehicleClass != "" {
+		if err = isValidVehicleClass(in.VehicleClass); err != nil {
+			return err
+		}
+	}
+
+	if in.EntryTime != "" {
+		if _, err = time.Parse(time.RFC3339, in.EntryTime); err != nil {
+			return fmt.Errorf("entry time parsing error: %v", err)
+		}
+	}
+
+	if active, found := findActiveSession(existingSessions, in.LotID, in.Plate); found {
+		return fmt.Errorf("plate %s already has active session %s in this lot", active.Plate, active.UUID)
+	}
+
+	return nil
+}
+
 // validateAgainstExistingRates verifies that there is no overlap between new rate being created
-// and existing rates
-func validateAgainstExistingRates(existingRates []types.Rate, in types.CreateRateInput) error {
+// and existing rates in the same lot and vehicle class. Only rates that are effective on at least one of the same dates are compared.
+// Weekday rates are compared with weekday rates, and override rates with the override rates that
+// target any of the same dates, since overrides take precedence over weekday rates on their dates
+func validateAgainstExistingRates(existingRates []types.Rate, calendars []types.Calendar, in types.CreateRateInput) error {
+	existingRates = ratesForVehicleClass(ratesForLot(existingRates, in.LotID), in.VehicleClass)
+	newRate := types.Rate{
+		LotID:          in.LotID,
+		VehicleClass:   in.VehicleClass,
+		Days:           in.Days,
+		Times:          in.Times,
+		TZ:             in.TZ,
+		Price:          in.Price,
+		EffectiveFrom:  in.EffectiveFrom,
+		EffectiveUntil: in.EffectiveUntil,
+		Dates:          in.Dates,
+		Calendar:       in.Calendar,
+	}
+	resolved := resolveOverrides(append([]types.Rate{newRate}, existingRates...), calendars)
+	newRate = resolved[0]
+
+	var concurrentRates []types.Rate
+	for _, existingRate := range resolved[1:] {
+		if isOverride(existingRate) != isOverride(newRate) {
+			continue
+		}
+		if effectiveDatesIntersect(existingRate.EffectiveFrom, existingRate.EffectiveUntil, in.EffectiveFrom, in.EffectiveUntil) {
+			concurrentRates = append(concurrentRates, existingRate)
+		}
+	}
+	existingRates = concurrentRates
+
+	if len(existingRates) > 0 && isOverride(newRate) {
+		return validateOverlappingOverrides(existingRates, newRate)
+	}
+
 	if len(existingRates) > 0 {
 		newRanges := getTimeRangesFromDaysAndTimes(in.Days, in.Times, in.TZ, in.Price)
 		var existingRanges []timeRange
@@ -58,6 +513,95 @@ func validateAgainstExistingRates(existingRates []types.Rate, in types.CreateRat
 		if err := validateOverlappingRanges(existingRanges, newRanges); err != nil {
 			return err
 		}
+
+		if config.Config.CrossTimezoneOverlapCheck {
+			from, to := getOverlapCheckSpan(time.Now())
+			if err := validateOverlappingInstants(existingRates, newRate, from, to); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateOverlappingOverrides errors if newRate, an override rate with its calendar resolved,
+// covers any of the same instants as an existing override rate on the dates it targets
+func validateOverlappingOverrides(existingRates []types.Rate, newRate types.Rate) error {
+	if newRate.Dates == "" {
+		return nil
+	}
+
+	newLocation, err := time.LoadLocation(newRate.TZ)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %s", newRate.TZ)
+	}
+	from, to := getDatesSpan(newRate.Dates)
+	newWindows := getRateWindows(newRate, from, to)
+
+	for _, existingRate := range existingRates {
+		if collisions := getCollisions(newWindows, getRateWindows(existingRate, from, to), newLocation); len(collisions) > 0 {
+			return fmt.Errorf("a rate already exists for %s %s (TZ: %s, Price: %d) which overlaps the given %s %s (TZ: %s, Price: %d) on %s", getRateTargetLabel(existingRate), existingRate.Times, existingRate.TZ, existingRate.Price, getRateTargetLabel(newRate), newRate.Times, newRate.TZ, newRate.Price, formatCollisions(collisions))
+		}
+	}
+
+	return nil
+}
+
+// getRateTargetLabel describes the days, calendar, or dates a rate applies on for error messages
+func getRateTargetLabel(rate types.Rate) string {
+	if rate.Calendar != "" {
+		return "calendar " + rate.Calendar
+	} else if rate.Dates != "" {
+		return "dates " + rate.Dates
+	}
+	return rate.Days
+}
+
+// maxReportedCollisions is how many collision dates are listed in an overlap error
+const maxReportedCollisions = 5
+
+// getOverlapCheckSpan returns the representative calendar span that rates in different
+// timezones are compared over: the whole year that now falls in, which takes in every
+// daylight saving period in both hemispheres
+func getOverlapCheckSpan(now time.Time) (from, to time.Time) {
+	from = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, 0)
+}
+
+// formatCollisions lists the first few collision dates and how many more there are
+func formatCollisions(collisions []string) string {
+	dates := collisions
+	if len(dates) > maxReportedCollisions {
+		dates = dates[:maxReportedCollisions]
+	}
+	more := ""
+	if hidden := len(collisions) - len(dates); hidden > 0 {
+		more = fmt.Sprintf(" and %d more dates", hidden)
+	}
+	return strings.Join(dates, ", ") + more
+}
+
+// validateOverlappingInstants errors if newRate covers any of the same real-world instants
+// as an existing rate in a different timezone between from and to. Same timezone overlaps
+// are left to validateOverlappingRanges. The error lists the local dates of newRate on which
+// the rates collide
+func validateOverlappingInstants(existingRates []types.Rate, newRate types.Rate, from, to time.Time) error {
+	newLocation, err := time.LoadLocation(newRate.TZ)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %s", newRate.TZ)
+	}
+	newWindows := getRateWindows(newRate, from, to)
+
+	for _, existingRate := range existingRates {
+		if existingRate.TZ == newRate.TZ {
+			continue
+		}
+
+		collisions := getCollisions(newWindows, getRateWindows(existingRate, from, to), newLocation)
+		if len(collisions) > 0 {
+			return fmt.Errorf("a rate already exists for %s %s (TZ: %s, Price: %d) which overlaps the given %s %s (TZ: %s, Price: %d) on %s", existingRate.Days, existingRate.Times, existingRate.TZ, existingRate.Price, newRate.Days, newRate.Times, newRate.TZ, newRate.Price, formatCollisions(collisions))
+		}
 	}
 
 	return nil
@@ -76,6 +620,252 @@ func validatePrice(price int) error {
 	return nil
 }
 
+// validateBilling validates a billing increment in minutes and a rounding rule,
+// either of which may be left unset to use the defaults
+func validateBilling(increment int, rounding string) error {
+	if increment < 0 {
+		return errors.New("billing increment must be greater than zero")
+	}
+
+	if increment > 24*60 {
+		return errors.New("billing increment cannot be longer than a day")
+	}
+
+	if rounding != "" {
+		if err := isValidRounding(rounding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTiers validates that price tiers are in order and do not overlap, and that
+// every tier starts and ends on a billing increment so each billed unit is in one tier
+func validateTiers(tiers []types.PriceTier, increment int) error {
+	if increment == 0 {
+		increment = defaultBillingIncrement
+	}
+
+	for i, tier := range tiers {
+		if tier.From < 0 {
+			return fmt.Errorf("tier %d must not start before the stay", i+1)
+		}
+
+		if tier.Price < 0 {
+			return fmt.Errorf("tier %d price must not be negative", i+1)
+		}
+
+		if tier.Until == 0 && i < len(tiers)-1 {
+			return fmt.Errorf("only the last tier may be left without an end, tier %d has none", i+1)
+		}
+
+		if tier.Until != 0 && tier.Until <= tier.From {
+			return fmt.Errorf("tier %d must end after it starts", i+1)
+		}
+
+		if tier.From%increment != 0 || tier.Until%increment != 0 {
+			return fmt.Errorf("tier %d must start and end on a multiple of the %d minute billing increment", i+1, increment)
+		}
+
+		if i > 0 && tier.From < tiers[i-1].Until {
+			return fmt.Errorf("tier %d starts before tier %d ends", i+1, i)
+		}
+	}
+
+	return nil
+}
+
+// validateSurgeRules validates a lot's capacity and that its surge rules are in order of
+// the occupancy they start above, which needs a capacity to be measured against
+func validateSurgeRules(capacity int, rules []types.SurgeRule, maxSurge int) error {
+	if capacity < 0 {
+		return errors.New("capacity must not be negative")
+	}
+
+	if maxSurge < 0 {
+		return errors.New("max surge must not be negative")
+	}
+
+	if len(rules) > 0 && capacity == 0 {
+		return errors.New("specify a capacity for the surge rules to measure occupancy against")
+	}
+
+	for i, rule := range rules {
+		if rule.Above < 0 || rule.Above >= 100 {
+			return fmt.Errorf("surge rule %d must start above an occupancy from 0 up to 99 percent", i+1)
+		}
+
+		if rule.Increase <= 0 {
+			return fmt.Errorf("surge rule %d must increase prices", i+1)
+		}
+
+		if i > 0 && rule.Above <= rules[i-1].Above {
+			return fmt.Errorf("surge rule %d must start above a higher occupancy than surge rule %d", i+1, i)
+		}
+	}
+
+	return nil
+}
+
+// validateReservationSlots validates a lot's reservation capacity and the slots that
+// set a different capacity in some hours. A slot's times are whole hours of one day
+func validateReservationSlots(capacity int, slots []types.ReservationSlot) error {
+	if capacity < 0 {
+		return errors.New("reservation capacity must not be negative")
+	}
+
+	for i, slot := range slots {
+		if err := validateDays(slot.Days); err != nil {
+			return fmt.Errorf("reservation slot %d: %v", i+1, err)
+		}
+
+		if err := validateTimeZone(slot.TZ); err != nil {
+			return fmt.Errorf("reservation slot %d: %v", i+1, err)
+		}
+
+		if _, _, err := getSlotHours(slot.Times); err != nil {
+			return fmt.Errorf("reservation slot %d: %v", i+1, err)
+		}
+
+		if slot.Capacity < 0 {
+			return fmt.Errorf("reservation slot %d capacity must not be negative", i+1)
+		}
+	}
+
+	return nil
+}
+
+// validateCancellationRules validates that cancellation rules are in order of how many
+// hours before a reservation they start, and that each charges at least as much as the last
+func validateCancellationRules(rules []types.CancellationRule) error {
+	for i, rule := range rules {
+		if rule.Before <= 0 {
+			return fmt.Errorf("cancellation rule %d must start at least an hour before the reservation", i+1)
+		}
+
+		if rule.Fee < 1 || rule.Fee > 100 {
+			return fmt.Errorf("cancellation rule %d must charge between 1 and 100 percent", i+1)
+		}
+
+		if i > 0 && rule.Before >= rules[i-1].Before {
+			return fmt.Errorf("cancellation rule %d must start closer to the reservation than cancellation rule %d", i+1, i)
+		}
+
+		if i > 0 && rule.Fee < rules[i-1].Fee {
+			return fmt.Errorf("cancellation rule %d must charge at least as much as cancellation rule %d", i+1, i)
+		}
+	}
+
+	return nil
+}
+
+// validateReservationInput validates the plate, vehicle class and times of a reservation
+// and returns the times. Reservations must start after now and last at most maxReservationLength
+func validateReservationInput(plate, vehicleClass, start, end string, now time.Time) (startTime time.Time, endTime time.Time, err error) {
+	if normalizePlate(plate) == "" {
+		return startTime, endTime, errors.New("specify a plate")
+	}
+
+	if vehicleClass != "" {
+		if err = isValidVehicleClass(vehicleClass); err != nil {
+			return startTime, endTime, err
+		}
+	}
+
+	if startTime, endTime, err = validateTimeRange(&start, &end); err != nil {
+		return startTime, endTime, err
+	}
+
+	if !startTime.After(now) {
+		return startTime, endTime, errors.New("reservations must start in the future")
+	}
+
+	if endTime.Sub(startTime) > maxReservationLength {
+		return startTime, endTime, fmt.Errorf("reservations cannot be longer than %v", maxReservationLength)
+	}
+
+	return startTime, endTime, nil
+}
+
+// validateReportOccupancyInput validates a ReportOccupancyInput for a lot
+func validateReportOccupancyInput(in *types.ReportOccupancyInput, lot types.Lot) error {
+	if in.Occupied == nil {
+		return errors.New("specify the number of occupied spaces")
+	}
+
+	if *in.Occupied < 0 {
+		return errors.New("occupied spaces must not be negative")
+	}
+
+	if lot.Capacity == 0 {
+		return fmt.Errorf("lot %s has no capacity to report occupancy against", lot.UUID)
+	}
+
+	return nil
+}
+
+// validatePriceLimits validates a daily maximum and a minimum charge in cents,
+// either of which may be left unset for no limit
+func validatePriceLimits(dailyMax, minCharge int) error {
+	if dailyMax < 0 {
+		return errors.New("daily maximum must not be negative")
+	}
+
+	if minCharge < 0 {
+		return errors.New("minimum charge must not be negative")
+	}
+
+	if dailyMax > 0 && minCharge > dailyMax {
+		return errors.New("minimum charge cannot be more than the daily maximum")
+	}
+
+	return nil
+}
+
+// validateGracePeriods validates an entry grace period, an exit grace period and a
+// number of free minutes, any of which may be left unset for none
+func validateGracePeriods(entryGrace, exitGrace, freeMinutes int) error {
+	periods := []struct {
+		name    string
+		minutes int
+	}{{"entry grace period", entryGrace}, {"exit grace period", exitGrace}, {"free minutes", freeMinutes}}
+
+	for _, period := range periods {
+		if period.minutes < 0 {
+			return fmt.Errorf("%s must not be negative", period.name)
+		}
+
+		if period.minutes > 24*60 {
+			return fmt.Errorf("%s cannot be longer than a day", period.name)
+		}
+	}
+
+	return nil
+}
+
+// validateEffectiveDates validates the dates a rate is effective from and until,
+// either of which may be left unset for a rate that is effective indefinitely
+func validateEffectiveDates(from, until string) error {
+	if from != "" {
+		if _, err := time.Parse(effectiveDateLayout, from); err != nil {
+			return fmt.Errorf("effective from must be a date in the format YYYY-MM-DD: %s", from)
+		}
+	}
+
+	if until != "" {
+		if _, err := time.Parse(effectiveDateLayout, until); err != nil {
+			return fmt.Errorf("effective until must be a date in the format YYYY-MM-DD: %s", until)
+		}
+	}
+
+	if from != "" && until != "" && until <= from {
+		return errors.New("effective until must be after effective from")
+	}
+
+	return nil
+}
+
 // validateDays validates that days in a comma separated list are valid
 // and that there are no repeated days
 func validateDays(days string) error {
@@ -114,7 +904,7 @@ func validateTimeZone(tz string) error {
 	return nil
 }
 
-// validateTimespan validates a given time range
+// validateTimespan validates a given time range, which may wrap past midnight
 func validateTimespan(timespan string) error {
 	var err error
 
@@ -127,15 +917,8 @@ func validateTimespan(timespan string) error {
 		return errors.New("specify a time range between only two hours of the day")
 	}
 
-	var earlier, later time.Time
-	if earlier, later, err = getTimeObjectsFromTimes(times); err != nil {
-		return err
-	}
-
-	if earlier.After(later) {
-		return errors.New("the first time in times must be earlier than the second")
-	}
-
+	// a first time later than the second is a span that wraps past midnight
+	_, _, err = getTimeObjectsFromTimes(times)
 	return err
 }
 
@@ -146,8 +929,7 @@ func validateOverlappingRanges(existingRanges, newRanges []timeRange) error {
 		for _, newRange := range newRanges {
 			// assume that we only care if ranges overlap if they have the same timezone
 			if existingRange.tz == newRange.tz {
-				overlap := newRange.earlier.Before(existingRange.later) && existingRange.earlier.Before(newRange.later)
-				if overlap {
+				if rangesOverlap(existingRange, newRange) {
 					return fmt.Errorf("a rate already exists for %s %s (TZ: %s, Price: %d) which overlaps the given %s %s (TZ: %s, Price: %d)", existingRange.days, existingRange.times, existingRange.tz, existingRange.price, newRange.days, newRange.times, newRange.tz, newRange.price)
 				}
 			}
@@ -156,38 +938,44 @@ func validateOverlappingRanges(existingRanges, newRanges []timeRange) error {
 	return nil
 }
 
+// week is the length of the week of days that timeRanges are placed in
+const week = 7 * 24 * time.Hour
+
+// rangesOverlap reports whether two timeRanges overlap. Ranges that wrap past midnight at
+// the end of the week spill into the next week, so each range is also compared against the
+// other one shifted a week later
+func rangesOverlap(a, b timeRange) bool {
+	overlaps := func(aEarlier, aLater, bEarlier, bLater time.Time) bool {
+		return aEarlier.Before(bLater) && bEarlier.Before(aLater)
+	}
+	return overlaps(a.earlier, a.later, b.earlier, b.later) ||
+		overlaps(a.earlier.Add(week), a.later.Add(week), b.earlier, b.later) ||
+		overlaps(a.earlier, a.later, b.earlier.Add(week), b.later.Add(week))
+}
+
 // validateTimeRange validates that a time range represented by a start and end string
-// parses, spans just one year on one day for more than zero seconds, is not mal-ordered,
-// and indicates only one timezone
+// parses, spans more than zero seconds, and is not mal-ordered. The range may cross any
+// number of days and years up to maxStayDays, and start and end may be given with
+// different offsets since they are compared as instants. Its errors wrap ErrInvalidInput
 func validateTimeRange(start, end *string) (startTime time.Time, endTime time.Time, err error) {
 	if startTime, err = time.Parse(time.RFC3339, *start); err != nil {
-		return startTime, endTime, fmt.Errorf("start time parsing error: %v", err)
+		return startTime, endTime, fmt.Errorf("%w: start time parsing error: %v", ErrInvalidInput, err)
 	}
 
 	if endTime, err = time.Parse(time.RFC3339, *end); err != nil {
-		return startTime, endTime, fmt.Errorf("end time parsing error: %v", err)
-	}
-
-	if startTime.Year() != endTime.Year() {
-		return startTime, endTime, errors.New("start and end cannot be in different years")
-	}
-
-	if startTime.YearDay() != endTime.YearDay() {
-		return startTime, endTime, errors.New("start and end cannot be on different days")
+		return startTime, endTime, fmt.Errorf("%w: end time parsing error: %v", ErrInvalidInput, err)
 	}
 
 	if startTime.Equal(endTime) {
-		return startTime, endTime, errors.New("start and end cannot be equal")
+		return startTime, endTime, fmt.Errorf("%w: start and end cannot be equal", ErrInvalidInput)
 	}
 
 	if startTime.After(endTime) {
-		return startTime, endTime, errors.New("start cannot be after end")
+		return startTime, endTime, fmt.Errorf("%w: start cannot be after end", ErrInvalidInput)
 	}
 
-	_, startOffset := startTime.Zone()
-	_, endOffset := endTime.Zone()
-	if startOffset != endOffset {
-		return startTime, endTime, errors.New("start and end cannot be in different timezones")
+	if endTime.After(startTime.AddDate(0, 0, maxStayDays)) {
+		return startTime, endTime, fmt.Errorf("%w: a stay cannot span more than %d days", ErrInvalidInput, maxStayDays)
 	}
 
 	return startTime, endTime, err
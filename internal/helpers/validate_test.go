@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"charlie-parker/pkg/types"
+	"strings"
 	"testing"
 	"time"
 )
@@ -35,6 +36,7 @@ func Test_validateCreateRateInput(t *testing.T) {
 }
 
 func Test_validateAgainstExistingRates(t *testing.T) {
+	calendars := []types.Calendar{{Name: "holidays", Dates: []string{"2017-07-04", "2017-11-23"}}}
 	tests := []struct {
 		name          string
 		existingRates []types.Rate
@@ -109,6 +111,105 @@ func Test_validateAgainstExistingRates(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Consecutive Effective Dates Passing Validation",
+			existingRates: []types.Rate{
+				{
+					UUID:           "0000001",
+					Days:           "mon",
+					Times:          "0900-1200",
+					TZ:             "America/Chicago",
+					Price:          1600,
+					EffectiveUntil: "2017-03-01",
+				},
+			},
+			in: types.CreateRateInput{
+				Days:          "mon",
+				Times:         "0900-1200",
+				TZ:            "America/Chicago",
+				Price:         1800,
+				EffectiveFrom: "2017-03-01",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Intersecting Effective Dates Overlap Error",
+			existingRates: []types.Rate{
+				{
+					UUID:           "0000001",
+					Days:           "mon",
+					Times:          "0900-1200",
+					TZ:             "America/Chicago",
+					Price:          1600,
+					EffectiveUntil: "2017-03-02",
+				},
+			},
+			in: types.CreateRateInput{
+				Days:          "mon",
+				Times:         "0900-1200",
+				TZ:            "America/Chicago",
+				Price:         1800,
+				EffectiveFrom: "2017-03-01",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Wrapped Passing Validation",
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "mon",
+					Times: "2200-0200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			in: types.CreateRateInput{
+				Days:  "tues",
+				Times: "0200-0900",
+				TZ:    "America/Chicago",
+				Price: 1500,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Wrapped Into Next Day Overlap Error",
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "mon",
+					Times: "2200-0200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			in: types.CreateRateInput{
+				Days:  "tues",
+				Times: "0100-0900",
+				TZ:    "America/Chicago",
+				Price: 1500,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Wrapped Into Next Week Overlap Error",
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "sat",
+					Times: "2200-0200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			in: types.CreateRateInput{
+				Days:  "sun",
+				Times: "0100-0900",
+				TZ:    "America/Chicago",
+				Price: 1500,
+			},
+			wantErr: true,
+		},
 		{
 			name: "Complex Overlap Error",
 			existingRates: []types.Rate{
@@ -128,11 +229,66 @@ func Test_validateAgainstExistingRates(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Override Over Weekday Rate Passing Validation",
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Days:  "thurs",
+					Times: "0900-1200",
+					TZ:    "America/Chicago",
+					Price: 1600,
+				},
+			},
+			in: types.CreateRateInput{
+				Dates: "2017-11-23",
+				Times: "0900-1200",
+				TZ:    "America/Chicago",
+				Price: 500,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Overrides On Different Dates Passing Validation",
+			existingRates: []types.Rate{
+				{
+					UUID:  "0000001",
+					Dates: "2017-12-25",
+					Times: "0000-2400",
+					TZ:    "America/Chicago",
+				},
+			},
+			in: types.CreateRateInput{
+				Calendar: "holidays",
+				Times:    "0000-2400",
+				TZ:       "America/Chicago",
+				Price:    500,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Date Override Over Calendar Override Error",
+			existingRates: []types.Rate{
+				{
+					UUID:     "0000001",
+					Calendar: "holidays",
+					Times:    "0000-2400",
+					TZ:       "America/Chicago",
+				},
+			},
+			in: types.CreateRateInput{
+				Dates: "2017-07-04",
+				Times: "1000-1100",
+				TZ:    "America/Chicago",
+				Price: 500,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if err := validateAgainstExistingRates(test.existingRates, test.in); (err != nil) != test.wantErr {
+			if err := validateAgainstExistingRates(test.existingRates, calendars, test.in); (err != nil) != test.wantErr {
 				t.Errorf("validateAgainstExistingRates() error = %v, wantErr %v", err, test.wantErr)
 				return
 			}
@@ -140,6 +296,39 @@ func Test_validateAgainstExistingRates(t *testing.T) {
 	}
 }
 
+func Test_validateDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		dates   string
+		wantErr bool
+	}{
+		{
+			name:    "Simple Passing Validation",
+			dates:   "2017-07-04,2017-11-23",
+			wantErr: false,
+		},
+		{
+			name:    "Invalid Date Error",
+			dates:   "2017-07-04,Nov 23",
+			wantErr: true,
+		},
+		{
+			name:    "Repeated Date Error",
+			dates:   "2017-07-04,2017-07-04",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateDates(test.dates); (err != nil) != test.wantErr {
+				t.Errorf("validateDates() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
 func Test_validatePrice(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -174,6 +363,386 @@ func Test_validatePrice(t *testing.T) {
 	}
 }
 
+func Test_validateBilling(t *testing.T) {
+	tests := []struct {
+		name      string
+		increment int
+		rounding  string
+		wantErr   bool
+	}{
+		{
+			name:      "Defaults Passing Validation",
+			increment: 0,
+			rounding:  "",
+			wantErr:   false,
+		},
+		{
+			name:      "Simple Passing Validation",
+			increment: 15,
+			rounding:  roundNearest,
+			wantErr:   false,
+		},
+		{
+			name:      "Negative Increment Error",
+			increment: -15,
+			wantErr:   true,
+		},
+		{
+			name:      "Longer Than A Day Error",
+			increment: 24*60 + 1,
+			wantErr:   true,
+		},
+		{
+			name:     "Invalid Rounding Error",
+			rounding: "INVALID",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			if err = validateBilling(test.increment, test.rounding); (err != nil) != test.wantErr {
+				t.Errorf("validateBilling() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_validateTiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		tiers     []types.PriceTier
+		increment int
+		wantErr   bool
+	}{
+		{
+			name:    "No Tiers Passing Validation",
+			wantErr: false,
+		},
+		{
+			name:    "Simple Passing Validation",
+			tiers:   []types.PriceTier{{From: 0, Until: 60, Price: 400}, {From: 60, Until: 180, Price: 200}, {From: 180, Price: 100}},
+			wantErr: false,
+		},
+		{
+			name:      "Gap Between Tiers Passing Validation",
+			tiers:     []types.PriceTier{{From: 0, Until: 30, Price: 0}, {From: 120, Price: 300}},
+			increment: 15,
+			wantErr:   false,
+		},
+		{
+			name:    "Overlapping Tiers Error",
+			tiers:   []types.PriceTier{{From: 0, Until: 120, Price: 400}, {From: 60, Price: 200}},
+			wantErr: true,
+		},
+		{
+			name:    "Out Of Order Tiers Error",
+			tiers:   []types.PriceTier{{From: 60, Until: 120, Price: 200}, {From: 0, Until: 60, Price: 400}},
+			wantErr: true,
+		},
+		{
+			name:    "Open Ended Tier Before Last Error",
+			tiers:   []types.PriceTier{{From: 0, Price: 400}, {From: 60, Until: 120, Price: 200}},
+			wantErr: true,
+		},
+		{
+			name:    "Tier Ends Before It Starts Error",
+			tiers:   []types.PriceTier{{From: 120, Until: 60, Price: 400}},
+			wantErr: true,
+		},
+		{
+			name:    "Tier Off Billing Increment Error",
+			tiers:   []types.PriceTier{{From: 0, Until: 90, Price: 400}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Tier Price Error",
+			tiers:   []types.PriceTier{{From: 0, Until: 60, Price: -400}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateTiers(test.tiers, test.increment); (err != nil) != test.wantErr {
+				t.Errorf("validateTiers() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_validatePriceLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		dailyMax  int
+		minCharge int
+		wantErr   bool
+	}{
+		{
+			name:    "No Limits Passing Validation",
+			wantErr: false,
+		},
+		{
+			name:      "Simple Passing Validation",
+			dailyMax:  2500,
+			minCharge: 300,
+			wantErr:   false,
+		},
+		{
+			name:      "Minimum Without Maximum Passing Validation",
+			minCharge: 300,
+			wantErr:   false,
+		},
+		{
+			name:     "Negative Maximum Error",
+			dailyMax: -1,
+			wantErr:  true,
+		},
+		{
+			name:      "Negative Minimum Error",
+			minCharge: -1,
+			wantErr:   true,
+		},
+		{
+			name:      "Minimum Above Maximum Error",
+			dailyMax:  300,
+			minCharge: 2500,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validatePriceLimits(test.dailyMax, test.minCharge); (err != nil) != test.wantErr {
+				t.Errorf("validatePriceLimits() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_validateReservationSlots(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		slots    []types.ReservationSlot
+		wantErr  bool
+	}{
+		{
+			name:    "No Reservations Passing Validation",
+			wantErr: false,
+		},
+		{
+			name:     "Simple Passing Validation",
+			capacity: 10,
+			slots:    []types.ReservationSlot{{Days: "fri,sat", Times: "1800-2400", TZ: "America/Chicago", Capacity: 20}},
+			wantErr:  false,
+		},
+		{
+			name:     "Negative Capacity Error",
+			capacity: -1,
+			wantErr:  true,
+		},
+		{
+			name:    "Part Hour Error",
+			slots:   []types.ReservationSlot{{Days: "fri", Times: "1830-2000", TZ: "America/Chicago", Capacity: 20}},
+			wantErr: true,
+		},
+		{
+			name:    "Past Midnight Error",
+			slots:   []types.ReservationSlot{{Days: "fri", Times: "2200-0200", TZ: "America/Chicago", Capacity: 20}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Timezone Error",
+			slots:   []types.ReservationSlot{{Days: "fri", Times: "1800-2000", Capacity: 20}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateReservationSlots(test.capacity, test.slots); (err != nil) != test.wantErr {
+				t.Errorf("validateReservationSlots() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateCancellationRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []types.CancellationRule
+		wantErr bool
+	}{
+		{
+			name:    "Simple Passing Validation",
+			rules:   []types.CancellationRule{{Before: 48, Fee: 25}, {Before: 2, Fee: 100}},
+			wantErr: false,
+		},
+		{
+			name:    "Out Of Order Error",
+			rules:   []types.CancellationRule{{Before: 2, Fee: 25}, {Before: 48, Fee: 100}},
+			wantErr: true,
+		},
+		{
+			name:    "Lower Later Fee Error",
+			rules:   []types.CancellationRule{{Before: 48, Fee: 50}, {Before: 2, Fee: 25}},
+			wantErr: true,
+		},
+		{
+			name:    "Fee Over 100 Percent Error",
+			rules:   []types.CancellationRule{{Before: 48, Fee: 150}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateCancellationRules(test.rules); (err != nil) != test.wantErr {
+				t.Errorf("validateCancellationRules() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateTaxes(t *testing.T) {
+	tests := []struct {
+		name    string
+		taxes   []types.Tax
+		wantErr bool
+	}{
+		{
+			name:    "Simple Passing Validation",
+			taxes:   []types.Tax{{Name: "State", Rate: 625}, {Name: "City", Rate: 200}},
+			wantErr: false,
+		},
+		{
+			name:    "Missing Name Error",
+			taxes:   []types.Tax{{Rate: 625}},
+			wantErr: true,
+		},
+		{
+			name:    "Zero Rate Error",
+			taxes:   []types.Tax{{Name: "State"}},
+			wantErr: true,
+		},
+		{
+			name:    "Rate Over 100 Percent Error",
+			taxes:   []types.Tax{{Name: "State", Rate: 10001}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateTaxes(test.taxes); (err != nil) != test.wantErr {
+				t.Errorf("validateTaxes() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateGracePeriods(t *testing.T) {
+	tests := []struct {
+		name        string
+		entryGrace  int
+		exitGrace   int
+		freeMinutes int
+		wantErr     bool
+	}{
+		{
+			name:    "No Grace Periods Passing Validation",
+			wantErr: false,
+		},
+		{
+			name:        "Simple Passing Validation",
+			entryGrace:  15,
+			exitGrace:   10,
+			freeMinutes: 30,
+			wantErr:     false,
+		},
+		{
+			name:       "Negative Entry Grace Error",
+			entryGrace: -1,
+			wantErr:    true,
+		},
+		{
+			name:      "Exit Grace Longer Than A Day Error",
+			exitGrace: 24*60 + 1,
+			wantErr:   true,
+		},
+		{
+			name:        "Negative Free Minutes Error",
+			freeMinutes: -5,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateGracePeriods(test.entryGrace, test.exitGrace, test.freeMinutes); (err != nil) != test.wantErr {
+				t.Errorf("validateGracePeriods() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_validateEffectiveDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		until   string
+		wantErr bool
+	}{
+		{
+			name:    "Indefinite Passing Validation",
+			wantErr: false,
+		},
+		{
+			name:    "Simple Passing Validation",
+			from:    "2017-01-01",
+			until:   "2017-02-01",
+			wantErr: false,
+		},
+		{
+			name:    "Open Ended Passing Validation",
+			from:    "2017-01-01",
+			wantErr: false,
+		},
+		{
+			name:    "Invalid Date Error",
+			from:    "01/01/2017",
+			wantErr: true,
+		},
+		{
+			name:    "Until Before From Error",
+			from:    "2017-02-01",
+			until:   "2017-01-01",
+			wantErr: true,
+		},
+		{
+			name:    "Until Equals From Error",
+			from:    "2017-01-01",
+			until:   "2017-01-01",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateEffectiveDates(test.from, test.until); (err != nil) != test.wantErr {
+				t.Errorf("validateEffectiveDates() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+		})
+	}
+}
+
 func Test_validateDays(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -274,9 +843,14 @@ func Test_validateTimespan(t *testing.T) {
 			wantErr:  true,
 		},
 		{
-			name:     "Start After End Error",
-			timespan: "1200-0900",
-			wantErr:  true,
+			name:     "End Of Day Passing Validation",
+			timespan: "1800-2400",
+			wantErr:  false,
+		},
+		{
+			name:     "Wrap Past Midnight Passing Validation",
+			timespan: "2200-0200",
+			wantErr:  false,
 		},
 	}
 
@@ -376,6 +950,59 @@ func Test_validateOverlappingRanges(t *testing.T) {
 	}
 }
 
+func Test_validateOverlappingInstants(t *testing.T) {
+	from := time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name          string
+		existingRates []types.Rate
+		newRate       types.Rate
+		wantDates     string
+		wantErr       bool
+	}{
+		{
+			name:          "Simple Passing Validation",
+			existingRates: []types.Rate{{Days: "fri", Times: "1000-1100", TZ: "America/Mexico_City", Price: 1500}},
+			newRate:       types.Rate{Days: "fri", Times: "0900-1000", TZ: "America/Chicago", Price: 1500},
+			wantErr:       false,
+		},
+		{
+			name:          "Same Timezone Is Skipped",
+			existingRates: []types.Rate{{Days: "fri", Times: "0900-1000", TZ: "America/Chicago", Price: 1500}},
+			newRate:       types.Rate{Days: "fri", Times: "0900-1000", TZ: "America/Chicago", Price: 1500},
+			wantErr:       false,
+		},
+		{
+			name:          "Collides Only While Daylight Saving Differs Error",
+			existingRates: []types.Rate{{Days: "fri", Times: "0800-0900", TZ: "America/Mexico_City", Price: 1500}},
+			newRate:       types.Rate{Days: "fri", Times: "0900-1000", TZ: "America/Chicago", Price: 1500},
+			wantDates:     "on 2017-03-17, 2017-03-24, 2017-03-31, 2017-11-03",
+			wantErr:       true,
+		},
+		{
+			name:          "Collision Dates Are Capped Error",
+			existingRates: []types.Rate{{Days: "fri", Times: "0900-1000", TZ: "America/Mexico_City", Price: 1500}},
+			newRate:       types.Rate{Days: "fri", Times: "0900-1000", TZ: "America/Chicago", Price: 1500},
+			wantDates:     "on 2017-01-06, 2017-01-13, 2017-01-20, 2017-01-27, 2017-02-03 and 43 more dates",
+			wantErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateOverlappingInstants(test.existingRates, test.newRate, from, to)
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateOverlappingInstants() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if err != nil && !strings.HasSuffix(err.Error(), test.wantDates) {
+				t.Errorf("validateOverlappingInstants() error = %v, want dates %q", err, test.wantDates)
+			}
+		})
+	}
+}
+
 func Test_validateTimeRange(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -402,16 +1029,16 @@ func Test_validateTimeRange(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "Different Year Error",
-			start:   "2015-07-01T07:00:00-05:00",
-			end:     "2016-07-01T12:00:00-05:00",
-			wantErr: true,
+			name:    "Different Year Passing Validation",
+			start:   "2015-12-31T07:00:00-05:00",
+			end:     "2016-01-01T12:00:00-05:00",
+			wantErr: false,
 		},
 		{
-			name:    "Different Day Error",
+			name:    "Different Day Passing Validation",
 			start:   "2015-07-01T07:00:00-05:00",
 			end:     "2015-08-01T12:00:00-05:00",
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "Equal Start/End Error",
@@ -426,9 +1053,27 @@ func Test_validateTimeRange(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "Different Offset Error",
+			name:    "Different Offset Passing Validation",
 			start:   "2015-07-01T07:00:00-05:00",
 			end:     "2015-07-01T12:00:00-06:00",
+			wantErr: false,
+		},
+		{
+			name:    "Start After End Across Offsets Error",
+			start:   "2015-07-01T07:00:00-06:00",
+			end:     "2015-07-01T07:30:00-05:00",
+			wantErr: true,
+		},
+		{
+			name:    "Longest Stay Passing Validation",
+			start:   "2015-07-01T07:00:00-05:00",
+			end:     "2016-07-01T07:00:00-05:00",
+			wantErr: false,
+		},
+		{
+			name:    "Too Long Stay Error",
+			start:   "2015-07-01T07:00:00-05:00",
+			end:     "2115-07-01T07:00:00-05:00",
 			wantErr: true,
 		},
 	}
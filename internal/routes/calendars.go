@@ -0,0 +1,171 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// maxCalendarFileSize is the largest calendar file that can be imported
+const maxCalendarFileSize = 1 << 20
+
+// GetCalendarsRoute is the api handler that returns all existing calendars from the DB
+func GetCalendarsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetCalendarsRouteName)
+	var (
+		err       error
+		calendars []types.Calendar
+		out       types.GetCalendarsOutput
+	)
+
+	if calendars, err = helpers.GetCalendars(); err != nil {
+		out.Error = fmt.Sprintf("Could not get calendars from %s with error: %v", config.Config.CalendarsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetCalendarsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Calendars = calendars
+	log.Infof("Successfully got all %d calendars from %s", len(out.Calendars), config.Config.CalendarsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetCalendarsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetCalendarRoute is the api handler that returns a single calendar by its name
+func GetCalendarRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetCalendarRouteName)
+	var (
+		err      error
+		calendar types.Calendar
+		out      types.GetCalendarOutput
+	)
+
+	if calendar, err = helpers.GetCalendar(c.Param("name")); err != nil {
+		out.Error = fmt.Sprintf("Could not get calendar %s from %s with error: %v", c.Param("name"), config.Config.CalendarsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetCalendarRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Calendar = calendar
+	log.Infof("Successfully got calendar %s from %s", out.Calendar.Name, config.Config.CalendarsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetCalendarRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// PutCalendarRoute is the api handler that creates a calendar or replaces its dates
+func PutCalendarRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.PutCalendarRouteName)
+	var (
+		err      error
+		in       types.PutCalendarInput
+		calendar types.Calendar
+		out      types.PutCalendarOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not put calendar %s with error: %v", c.Param("name"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.PutCalendarRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if calendar, err = helpers.PutCalendar(c.Param("name"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not put calendar %s in %s with error: %v", c.Param("name"), config.Config.CalendarsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.PutCalendarRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Calendar = calendar
+	log.Infof("Successfully put calendar %s with %d dates in %s", out.Calendar.Name, len(out.Calendar.Dates), config.Config.CalendarsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.PutCalendarRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// ImportCalendarRoute is the api handler that adds the dates in a calendar file to a
+// calendar. The file is either the request body or a multipart form file named "file"
+func ImportCalendarRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.ImportCalendarRouteName)
+	var (
+		err      error
+		file     string
+		calendar types.Calendar
+		out      types.PutCalendarOutput
+	)
+
+	if file, err = readCalendarFile(c); err != nil {
+		out.Error = fmt.Sprintf("Could not import calendar %s with error: %v", c.Param("name"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ImportCalendarRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if calendar, err = helpers.ImportCalendar(c.Param("name"), file); err != nil {
+		out.Error = fmt.Sprintf("Could not import calendar %s in %s with error: %v", c.Param("name"), config.Config.CalendarsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ImportCalendarRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Calendar = calendar
+	log.Infof("Successfully imported calendar %s with %d dates in %s", out.Calendar.Name, len(out.Calendar.Dates), config.Config.CalendarsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.ImportCalendarRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeleteCalendarRoute is the api handler that deletes a single calendar by its name
+func DeleteCalendarRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeleteCalendarRouteName)
+	var (
+		err      error
+		calendar types.Calendar
+		out      types.DeleteCalendarOutput
+	)
+
+	if calendar, err = helpers.DeleteCalendar(c.Param("name")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete calendar %s from %s with error: %v", c.Param("name"), config.Config.CalendarsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteCalendarRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Calendar = calendar
+	log.Infof("Successfully deleted calendar %s from %s", out.Calendar.Name, config.Config.CalendarsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeleteCalendarRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// readCalendarFile reads a calendar file from a multipart form file named "file",
+// or from the request body
+func readCalendarFile(c echo.Context) (string, error) {
+	body := c.Request().Body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return "", err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := ioutil.ReadAll(http.MaxBytesReader(c.Response(), body, maxCalendarFileSize))
+	return string(data), err
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_CalendarsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+
+	tests := []struct {
+		name        string
+		handler     echo.HandlerFunc
+		method      string
+		calendar    string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "Put Calendar",
+			handler:     PutCalendarRoute,
+			method:      http.MethodPut,
+			calendar:    "holidays",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"dates": ["2017-11-23"]}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `"dates":["2017-11-23"]`,
+		},
+		{
+			name:        "Import Calendar",
+			handler:     ImportCalendarRoute,
+			method:      http.MethodPost,
+			calendar:    "holidays",
+			contentType: echo.MIMETextPlain,
+			body:        "2017-07-04\n2017-12-25\n",
+			wantStatus:  http.StatusOK,
+			wantBody:    `"dates":["2017-07-04","2017-11-23","2017-12-25"]`,
+		},
+		{
+			name:       "Get Calendars",
+			handler:    GetCalendarsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"holidays"`,
+		},
+		{
+			name:       "Get Missing Calendar Error",
+			handler:    GetCalendarRoute,
+			method:     http.MethodGet,
+			calendar:   "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Calendar",
+			handler:    DeleteCalendarRoute,
+			method:     http.MethodDelete,
+			calendar:   "holidays",
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"holidays"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, test.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues(test.calendar)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
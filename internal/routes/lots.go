@@ -0,0 +1,198 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetLotsRoute is the api handler that returns all existing lots from the DB
+func GetLotsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetLotsRouteName)
+	var (
+		err  error
+		lots []types.Lot
+		out  types.GetLotsOutput
+	)
+
+	if lots, err = helpers.GetLots(); err != nil {
+		out.Error = fmt.Sprintf("Could not get lots from %s with error: %v", config.Config.LotsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetLotsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Lots = lots
+	log.Infof("Successfully got all %d lots from %s", len(out.Lots), config.Config.LotsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetLotsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreateLotRoute is the api handler that creates a new lot
+func CreateLotRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreateLotRouteName)
+	var (
+		err error
+		in  types.LotInput
+		lot types.Lot
+		out types.LotOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create lot with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateLotRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if lot, err = helpers.CreateLot(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create lot in %s with error: %v", config.Config.LotsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateLotRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Lot = lot
+	log.Infof("Successfully created lot %s in %s", out.Lot.UUID, config.Config.LotsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreateLotRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetLotRoute is the api handler that returns a single lot by its uuid
+func GetLotRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetLotRouteName)
+	var (
+		err error
+		lot types.Lot
+		out types.LotOutput
+	)
+
+	if lot, err = helpers.GetLot(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get lot %s from %s with error: %v", c.Param("uuid"), config.Config.LotsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetLotRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Lot = lot
+	log.Infof("Successfully got lot %s from %s", out.Lot.UUID, config.Config.LotsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetLotRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdateLotRoute is the api handler that replaces the name and address of a single lot
+func UpdateLotRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdateLotRouteName)
+	var (
+		err error
+		in  types.LotInput
+		lot types.Lot
+		out types.LotOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update lot %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateLotRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if lot, err = helpers.UpdateLot(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update lot %s in %s with error: %v", c.Param("uuid"), config.Config.LotsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateLotRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Lot = lot
+	log.Infof("Successfully updated lot %s in %s", out.Lot.UUID, config.Config.LotsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdateLotRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeleteLotRoute is the api handler that deletes a single lot by its uuid
+func DeleteLotRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeleteLotRouteName)
+	var (
+		err error
+		lot types.Lot
+		out types.LotOutput
+	)
+
+	if lot, err = helpers.DeleteLot(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete lot %s from %s with error: %v", c.Param("uuid"), config.Config.LotsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteLotRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Lot = lot
+	log.Infof("Successfully deleted lot %s from %s", out.Lot.UUID, config.Config.LotsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeleteLotRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// ReportOccupancyRoute is the api handler that stores the number of spaces occupied in a single lot by its uuid
+func ReportOccupancyRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.ReportOccupancyRouteName)
+	var (
+		err       error
+		in        types.ReportOccupancyInput
+		occupancy types.Occupancy
+		out       types.OccupancyOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not report occupancy of lot %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ReportOccupancyRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if occupancy, err = helpers.ReportOccupancy(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not report occupancy of lot %s in %s with error: %v", c.Param("uuid"), config.Config.OccupanciesTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ReportOccupancyRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Occupancy = occupancy
+	log.Infof("Successfully reported occupancy of %d%% for lot %s in %s", out.Occupancy.Percent, out.Occupancy.LotID, config.Config.OccupanciesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.ReportOccupancyRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetOccupancyRoute is the api handler that returns the most recently reported occupancy of a single lot by its uuid
+func GetOccupancyRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetOccupancyRouteName)
+	var (
+		err       error
+		occupancy types.Occupancy
+		out       types.OccupancyOutput
+	)
+
+	if occupancy, err = helpers.GetOccupancy(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get occupancy of lot %s from %s with error: %v", c.Param("uuid"), config.Config.OccupanciesTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetOccupancyRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Occupancy = occupancy
+	log.Infof("Successfully got occupancy of lot %s from %s", out.Occupancy.LotID, config.Config.OccupanciesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetOccupancyRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_LotsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Lots.Put(types.Lot{UUID: "garage", Name: "Garage"}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Lot",
+			handler:    CreateLotRoute,
+			method:     http.MethodPost,
+			body:       `{"name": "Main Street Garage", "address": "100 Main St"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Main Street Garage"`,
+		},
+		{
+			name:       "Get Lots",
+			handler:    GetLotsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Garage"`,
+		},
+		{
+			name:       "Update Lot",
+			handler:    UpdateLotRoute,
+			method:     http.MethodPut,
+			uuid:       "garage",
+			body:       `{"name": "North Garage", "capacity": 200, "surgeRules": [{"above": 80, "increase": 25}]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"surgeRules":[{"above":80,"increase":25}]`,
+		},
+		{
+			name:       "Report Occupancy",
+			handler:    ReportOccupancyRoute,
+			method:     http.MethodPost,
+			uuid:       "garage",
+			body:       `{"occupied": 170}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"percent":85`,
+		},
+		{
+			name:       "Get Occupancy",
+			handler:    GetOccupancyRoute,
+			method:     http.MethodGet,
+			uuid:       "garage",
+			wantStatus: http.StatusOK,
+			wantBody:   `"occupied":170`,
+		},
+		{
+			name:       "Report Missing Lot Occupancy Error",
+			handler:    ReportOccupancyRoute,
+			method:     http.MethodPost,
+			uuid:       "missing",
+			body:       `{"occupied": 170}`,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Missing Lot Error",
+			handler:    GetLotRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Lot",
+			handler:    DeleteLotRoute,
+			method:     http.MethodDelete,
+			uuid:       "garage",
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"garage"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
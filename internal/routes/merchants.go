@@ -0,0 +1,222 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetMerchantsRoute is the api handler that returns all existing merchants from the DB
+func GetMerchantsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetMerchantsRouteName)
+	var (
+		err       error
+		merchants []types.Merchant
+		out       types.GetMerchantsOutput
+	)
+
+	if merchants, err = helpers.GetMerchants(); err != nil {
+		out.Error = fmt.Sprintf("Could not get merchants from %s with error: %v", config.Config.MerchantsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetMerchantsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Merchants = merchants
+	log.Infof("Successfully got all %d merchants from %s", len(out.Merchants), config.Config.MerchantsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetMerchantsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreateMerchantRoute is the api handler that creates a new merchant
+func CreateMerchantRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreateMerchantRouteName)
+	var (
+		err      error
+		in       types.MerchantInput
+		merchant types.Merchant
+		out      types.MerchantOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create merchant with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateMerchantRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if merchant, err = helpers.CreateMerchant(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create merchant in %s with error: %v", config.Config.MerchantsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateMerchantRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Merchant = merchant
+	log.Infof("Successfully created merchant %s in %s", out.Merchant.UUID, config.Config.MerchantsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreateMerchantRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetMerchantRoute is the api handler that returns a single merchant by its uuid
+func GetMerchantRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetMerchantRouteName)
+	var (
+		err      error
+		merchant types.Merchant
+		out      types.MerchantOutput
+	)
+
+	if merchant, err = helpers.GetMerchant(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get merchant %s from %s with error: %v", c.Param("uuid"), config.Config.MerchantsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetMerchantRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Merchant = merchant
+	log.Infof("Successfully got merchant %s from %s", out.Merchant.UUID, config.Config.MerchantsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetMerchantRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdateMerchantRoute is the api handler that replaces every field of a single merchant
+func UpdateMerchantRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdateMerchantRouteName)
+	var (
+		err      error
+		in       types.MerchantInput
+		merchant types.Merchant
+		out      types.MerchantOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update merchant %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateMerchantRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if merchant, err = helpers.UpdateMerchant(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update merchant %s in %s with error: %v", c.Param("uuid"), config.Config.MerchantsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateMerchantRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Merchant = merchant
+	log.Infof("Successfully updated merchant %s in %s", out.Merchant.UUID, config.Config.MerchantsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdateMerchantRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeleteMerchantRoute is the api handler that deletes a single merchant by its uuid
+func DeleteMerchantRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeleteMerchantRouteName)
+	var (
+		err      error
+		merchant types.Merchant
+		out      types.MerchantOutput
+	)
+
+	if merchant, err = helpers.DeleteMerchant(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete merchant %s from %s with error: %v", c.Param("uuid"), config.Config.MerchantsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteMerchantRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Merchant = merchant
+	log.Infof("Successfully deleted merchant %s from %s", out.Merchant.UUID, config.Config.MerchantsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeleteMerchantRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// IssueValidationsRoute is the api handler that issues new validation codes to a single merchant by its uuid
+func IssueValidationsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.IssueValidationsRouteName)
+	var (
+		err         error
+		in          types.IssueValidationsInput
+		validations []types.Validation
+		out         types.ValidationsOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not issue validations to merchant %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.IssueValidationsRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if validations, err = helpers.IssueValidations(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not issue validations to merchant %s in %s with error: %v", c.Param("uuid"), config.Config.ValidationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.IssueValidationsRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Validations = validations
+	log.Infof("Successfully issued %d validations to merchant %s in %s", len(out.Validations), c.Param("uuid"), config.Config.ValidationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.IssueValidationsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetMerchantValidationsRoute is the api handler that returns every validation issued to a single merchant by its uuid
+func GetMerchantValidationsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetMerchantValidationsRouteName)
+	var (
+		err         error
+		validations []types.Validation
+		out         types.ValidationsOutput
+	)
+
+	if validations, err = helpers.GetMerchantValidations(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get validations of merchant %s from %s with error: %v", c.Param("uuid"), config.Config.ValidationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetMerchantValidationsRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Validations = validations
+	log.Infof("Successfully got all %d validations of merchant %s from %s", len(out.Validations), c.Param("uuid"), config.Config.ValidationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetMerchantValidationsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetMerchantStatementRoute is the api handler that returns the statement of a single merchant
+// by its uuid for the month query param ("YYYY-MM"), which defaults to the current month
+func GetMerchantStatementRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetMerchantStatementRouteName)
+	var (
+		err       error
+		statement types.MerchantStatement
+		out       types.MerchantStatementOutput
+	)
+
+	if statement, err = helpers.GetMerchantStatement(c.Param("uuid"), c.QueryParam("month")); err != nil {
+		out.Error = fmt.Sprintf("Could not get statement of merchant %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetMerchantStatementRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Statement = statement
+	log.Infof("Successfully got the %s statement of merchant %s for %d cents", out.Statement.Month, out.Statement.MerchantID, out.Statement.Total)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetMerchantStatementRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
@@ -0,0 +1,189 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_MerchantsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Merchants.Put(types.Merchant{UUID: "cafe", Name: "Cafe", TZ: "America/Chicago"}); err != nil {
+		t.Fatalf("Merchants.Put() setup error = %v", err)
+	}
+	if err := config.Config.Validations.Create(types.Validation{Code: "CAFE5OFF", MerchantID: "cafe", Type: "amount", Value: 500, State: "issued"}); err != nil {
+		t.Fatalf("Validations.Create() setup error = %v", err)
+	}
+	if err := config.Config.Sessions.Create(types.Session{UUID: "parked", Plate: "ABC123", State: "active", EntryTime: "2017-01-06T17:00:00-06:00"}); err != nil {
+		t.Fatalf("Sessions.Create() setup error = %v", err)
+	}
+	if _, err := helpers.CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+		t.Fatalf("CreateRate() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		query      string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Merchant",
+			handler:    CreateMerchantRoute,
+			method:     http.MethodPost,
+			body:       `{"name": "Bookshop", "budget": 50000, "tz": "America/Chicago"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"budget":50000`,
+		},
+		{
+			name:       "Create Merchant Without Name Error",
+			handler:    CreateMerchantRoute,
+			method:     http.MethodPost,
+			body:       `{"tz": "America/Chicago"}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Merchants",
+			handler:    GetMerchantsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Cafe"`,
+		},
+		{
+			name:       "Update Merchant",
+			handler:    UpdateMerchantRoute,
+			method:     http.MethodPut,
+			uuid:       "cafe",
+			body:       `{"name": "Corner Cafe", "tz": "America/Chicago"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Corner Cafe"`,
+		},
+		{
+			name:       "Issue Validations",
+			handler:    IssueValidationsRoute,
+			method:     http.MethodPost,
+			uuid:       "cafe",
+			body:       `{"type": "minutes", "value": 120, "count": 2}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"state":"issued"`,
+		},
+		{
+			name:       "Issue Validations To Missing Merchant Error",
+			handler:    IssueValidationsRoute,
+			method:     http.MethodPost,
+			uuid:       "missing",
+			body:       `{"type": "minutes", "value": 120}`,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Merchant Validations",
+			handler:    GetMerchantValidationsRoute,
+			method:     http.MethodGet,
+			uuid:       "cafe",
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":"CAFE5OFF"`,
+		},
+		{
+			name:       "Redeem Validation",
+			handler:    RedeemValidationRoute,
+			method:     http.MethodPost,
+			uuid:       "parked",
+			body:       `{"code": "cafe-5off"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"state":"redeemed"`,
+		},
+		{
+			name:       "Redeem Missing Validation Error",
+			handler:    RedeemValidationRoute,
+			method:     http.MethodPost,
+			uuid:       "parked",
+			body:       `{"code": "NOSUCHCODE"}`,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "End Validated Session",
+			handler:    EndSessionRoute,
+			method:     http.MethodPost,
+			uuid:       "parked",
+			body:       `{"exitTime": "2017-01-06T18:00:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"fee":1300`,
+		},
+		{
+			name:       "Get Merchant Statement",
+			handler:    GetMerchantStatementRoute,
+			method:     http.MethodGet,
+			uuid:       "cafe",
+			query:      "month=2017-01",
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":500`,
+		},
+		{
+			name:       "Get Merchant Statement Invalid Month Error",
+			handler:    GetMerchantStatementRoute,
+			method:     http.MethodGet,
+			uuid:       "cafe",
+			query:      "month=January",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Merchant With Issued Validations Error",
+			handler:    DeleteMerchantRoute,
+			method:     http.MethodDelete,
+			uuid:       "cafe",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/?"+test.query, strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
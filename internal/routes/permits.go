@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetPermitsRoute is the api handler that returns all existing permits from the DB
+func GetPermitsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetPermitsRouteName)
+	var (
+		err     error
+		permits []types.Permit
+		out     types.GetPermitsOutput
+	)
+
+	if permits, err = helpers.GetPermits(); err != nil {
+		out.Error = fmt.Sprintf("Could not get permits from %s with error: %v", config.Config.PermitsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetPermitsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Permits = permits
+	log.Infof("Successfully got all %d permits from %s", len(out.Permits), config.Config.PermitsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetPermitsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreatePermitRoute is the api handler that creates a new permit
+func CreatePermitRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreatePermitRouteName)
+	var (
+		err    error
+		in     types.PermitInput
+		permit types.Permit
+		out    types.PermitOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create permit with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreatePermitRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if permit, err = helpers.CreatePermit(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create permit in %s with error: %v", config.Config.PermitsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreatePermitRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Permit = permit
+	log.Infof("Successfully created permit %s in %s", out.Permit.UUID, config.Config.PermitsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreatePermitRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetPermitRoute is the api handler that returns a single permit by its uuid
+func GetPermitRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetPermitRouteName)
+	var (
+		err    error
+		permit types.Permit
+		out    types.PermitOutput
+	)
+
+	if permit, err = helpers.GetPermit(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get permit %s from %s with error: %v", c.Param("uuid"), config.Config.PermitsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetPermitRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Permit = permit
+	log.Infof("Successfully got permit %s from %s", out.Permit.UUID, config.Config.PermitsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetPermitRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdatePermitRoute is the api handler that replaces every field of a single permit
+func UpdatePermitRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdatePermitRouteName)
+	var (
+		err    error
+		in     types.PermitInput
+		permit types.Permit
+		out    types.PermitOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update permit %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdatePermitRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if permit, err = helpers.UpdatePermit(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update permit %s in %s with error: %v", c.Param("uuid"), config.Config.PermitsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdatePermitRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Permit = permit
+	log.Infof("Successfully updated permit %s in %s", out.Permit.UUID, config.Config.PermitsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdatePermitRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeletePermitRoute is the api handler that deletes a single permit by its uuid
+func DeletePermitRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeletePermitRouteName)
+	var (
+		err    error
+		permit types.Permit
+		out    types.PermitOutput
+	)
+
+	if permit, err = helpers.DeletePermit(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete permit %s from %s with error: %v", c.Param("uuid"), config.Config.PermitsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeletePermitRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Permit = permit
+	log.Infof("Successfully deleted permit %s from %s", out.Permit.UUID, config.Config.PermitsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeletePermitRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
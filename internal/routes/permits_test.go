@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_PermitsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Permits.Put(types.Permit{UUID: "monthly", Holder: "Jane Doe", Plates: []string{"ABC123"}, Days: "mon,tues,wed,thurs,fri", TZ: "America/Chicago", ValidFrom: "2017-01-01", ValidUntil: "2017-02-01"}); err != nil {
+		t.Fatalf("Permits.Put() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Permit",
+			handler:    CreatePermitRoute,
+			method:     http.MethodPost,
+			body:       `{"holder": "Night Shift", "plates": ["def 456"], "days": "sat,sun", "times": "2200-0600", "tz": "America/Chicago", "validFrom": "2017-01-01", "validUntil": "2018-01-01"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"plates":["DEF456"]`,
+		},
+		{
+			name:       "Create Permit Without Plates Error",
+			handler:    CreatePermitRoute,
+			method:     http.MethodPost,
+			body:       `{"holder": "Night Shift", "days": "sat,sun", "tz": "America/Chicago", "validFrom": "2017-01-01", "validUntil": "2018-01-01"}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Permits",
+			handler:    GetPermitsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"holder":"Jane Doe"`,
+		},
+		{
+			name:       "Update Permit",
+			handler:    UpdatePermitRoute,
+			method:     http.MethodPut,
+			uuid:       "monthly",
+			body:       `{"holder": "Jane Doe", "plates": ["ABC123", "XYZ789"], "days": "mon,tues,wed,thurs,fri", "tz": "America/Chicago", "validFrom": "2017-01-01", "validUntil": "2017-03-01"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"validUntil":"2017-03-01"`,
+		},
+		{
+			name:       "Get Permit",
+			handler:    GetPermitRoute,
+			method:     http.MethodGet,
+			uuid:       "monthly",
+			wantStatus: http.StatusOK,
+			wantBody:   `"plates":["ABC123","XYZ789"]`,
+		},
+		{
+			name:       "Get Missing Permit Error",
+			handler:    GetPermitRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Permit",
+			handler:    DeletePermitRoute,
+			method:     http.MethodDelete,
+			uuid:       "monthly",
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"monthly"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetProductsRoute is the api handler that returns all existing products from the DB
+func GetProductsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetProductsRouteName)
+	var (
+		err      error
+		products []types.Product
+		out      types.GetProductsOutput
+	)
+
+	if products, err = helpers.GetProducts(); err != nil {
+		out.Error = fmt.Sprintf("Could not get products from %s with error: %v", config.Config.ProductsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetProductsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Products = products
+	log.Infof("Successfully got all %d products from %s", len(out.Products), config.Config.ProductsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetProductsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreateProductRoute is the api handler that creates a new product
+func CreateProductRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreateProductRouteName)
+	var (
+		err     error
+		in      types.ProductInput
+		product types.Product
+		out     types.ProductOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create product with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateProductRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if product, err = helpers.CreateProduct(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create product in %s with error: %v", config.Config.ProductsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateProductRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Product = product
+	log.Infof("Successfully created product %s in %s", out.Product.UUID, config.Config.ProductsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreateProductRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetProductRoute is the api handler that returns a single product by its uuid
+func GetProductRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetProductRouteName)
+	var (
+		err     error
+		product types.Product
+		out     types.ProductOutput
+	)
+
+	if product, err = helpers.GetProduct(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get product %s from %s with error: %v", c.Param("uuid"), config.Config.ProductsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetProductRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Product = product
+	log.Infof("Successfully got product %s from %s", out.Product.UUID, config.Config.ProductsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetProductRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdateProductRoute is the api handler that replaces every field of a single product
+func UpdateProductRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdateProductRouteName)
+	var (
+		err     error
+		in      types.ProductInput
+		product types.Product
+		out     types.ProductOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update product %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateProductRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if product, err = helpers.UpdateProduct(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update product %s in %s with error: %v", c.Param("uuid"), config.Config.ProductsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateProductRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Product = product
+	log.Infof("Successfully updated product %s in %s", out.Product.UUID, config.Config.ProductsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdateProductRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeleteProductRoute is the api handler that deletes a single product by its uuid
+func DeleteProductRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeleteProductRouteName)
+	var (
+		err     error
+		product types.Product
+		out     types.ProductOutput
+	)
+
+	if product, err = helpers.DeleteProduct(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete product %s from %s with error: %v", c.Param("uuid"), config.Config.ProductsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteProductRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Product = product
+	log.Infof("Successfully deleted product %s from %s", out.Product.UUID, config.Config.ProductsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeleteProductRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
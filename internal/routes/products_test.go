@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_ProductsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Products.Put(types.Product{UUID: "earlybird", Name: "Early Bird", Days: "fri", EntryWindow: "0500-0900", ExitWindow: "1500-2000", TZ: "America/Chicago", Price: 1200}); err != nil {
+		t.Fatalf("Products.Put() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Product",
+			handler:    CreateProductRoute,
+			method:     http.MethodPost,
+			body:       `{"name": "Evening", "days": "fri", "entryWindow": "1700-0200", "exitWindow": "1700-0600", "tz": "America/Chicago", "price": 800}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Evening"`,
+		},
+		{
+			name:       "Create Invalid Product Error",
+			handler:    CreateProductRoute,
+			method:     http.MethodPost,
+			body:       `{"name": "Evening", "days": "fri", "entryWindow": "1700-0200", "tz": "America/Chicago", "price": 800}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Products",
+			handler:    GetProductsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Early Bird"`,
+		},
+		{
+			name:       "Update Product",
+			handler:    UpdateProductRoute,
+			method:     http.MethodPut,
+			uuid:       "earlybird",
+			body:       `{"name": "Early Bird", "days": "mon,fri", "entryWindow": "0500-0930", "exitWindow": "1500-2000", "tz": "America/Chicago", "price": 1000}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"entryWindow":"0500-0930"`,
+		},
+		{
+			name:       "Get Missing Product Error",
+			handler:    GetProductRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Product",
+			handler:    DeleteProductRoute,
+			method:     http.MethodDelete,
+			uuid:       "earlybird",
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"earlybird"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetPromosRoute is the api handler that returns all existing promos from the DB
+func GetPromosRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetPromosRouteName)
+	var (
+		err    error
+		promos []types.Promo
+		out    types.GetPromosOutput
+	)
+
+	if promos, err = helpers.GetPromos(); err != nil {
+		out.Error = fmt.Sprintf("Could not get promos from %s with error: %v", config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetPromosRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Promos = promos
+	log.Infof("Successfully got all %d promos from %s", len(out.Promos), config.Config.PromosTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetPromosRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreatePromoRoute is the api handler that creates a new promo
+func CreatePromoRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreatePromoRouteName)
+	var (
+		err   error
+		in    types.PromoInput
+		promo types.Promo
+		out   types.PromoOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create promo with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreatePromoRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if promo, err = helpers.CreatePromo(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create promo in %s with error: %v", config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreatePromoRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Promo = promo
+	log.Infof("Successfully created promo %s in %s", out.Promo.UUID, config.Config.PromosTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreatePromoRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetPromoRoute is the api handler that returns a single promo by its uuid
+func GetPromoRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetPromoRouteName)
+	var (
+		err   error
+		promo types.Promo
+		out   types.PromoOutput
+	)
+
+	if promo, err = helpers.GetPromo(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get promo %s from %s with error: %v", c.Param("uuid"), config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetPromoRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Promo = promo
+	log.Infof("Successfully got promo %s from %s", out.Promo.UUID, config.Config.PromosTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetPromoRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdatePromoRoute is the api handler that replaces every field of a single promo
+func UpdatePromoRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdatePromoRouteName)
+	var (
+		err   error
+		in    types.PromoInput
+		promo types.Promo
+		out   types.PromoOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update promo %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdatePromoRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if promo, err = helpers.UpdatePromo(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update promo %s in %s with error: %v", c.Param("uuid"), config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdatePromoRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Promo = promo
+	log.Infof("Successfully updated promo %s in %s", out.Promo.UUID, config.Config.PromosTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdatePromoRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeletePromoRoute is the api handler that deletes a single promo by its uuid
+func DeletePromoRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeletePromoRouteName)
+	var (
+		err   error
+		promo types.Promo
+		out   types.PromoOutput
+	)
+
+	if promo, err = helpers.DeletePromo(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not delete promo %s from %s with error: %v", c.Param("uuid"), config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeletePromoRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Promo = promo
+	log.Infof("Successfully deleted promo %s from %s", out.Promo.UUID, config.Config.PromosTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeletePromoRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// RedeemPromoRoute is the api handler that records one use of a single promo by its uuid
+func RedeemPromoRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.RedeemPromoRouteName)
+	var (
+		err   error
+		promo types.Promo
+		out   types.PromoOutput
+	)
+
+	if promo, err = helpers.RedeemPromo(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not redeem promo %s in %s with error: %v", c.Param("uuid"), config.Config.PromosTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.RedeemPromoRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Promo = promo
+	log.Infof("Successfully redeemed promo %s, which has been used %d times", out.Promo.UUID, out.Promo.Uses)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.RedeemPromoRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_PromosRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Promos.Put(types.Promo{UUID: "weekend", Code: "WEEKEND20", Name: "20% off weekends", Type: "percent", Value: 20, Days: "sat,sun", TZ: "America/Chicago", MaxUses: 1}); err != nil {
+		t.Fatalf("Promos.Put() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Promo",
+			handler:    CreatePromoRoute,
+			method:     http.MethodPost,
+			body:       `{"code": "FIRST5", "name": "$5 off", "type": "amount", "value": 500, "tz": "America/Chicago"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":"FIRST5"`,
+		},
+		{
+			name:       "Create Duplicate Code Promo Error",
+			handler:    CreatePromoRoute,
+			method:     http.MethodPost,
+			body:       `{"code": "weekend20", "name": "Copy", "type": "amount", "value": 500, "tz": "America/Chicago"}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Promos",
+			handler:    GetPromosRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":"WEEKEND20"`,
+		},
+		{
+			name:       "Update Promo",
+			handler:    UpdatePromoRoute,
+			method:     http.MethodPut,
+			uuid:       "weekend",
+			body:       `{"code": "WEEKEND25", "name": "25% off weekends", "type": "percent", "value": 25, "days": "sat,sun", "tz": "America/Chicago", "maxUses": 1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":"WEEKEND25"`,
+		},
+		{
+			name:       "Redeem Promo",
+			handler:    RedeemPromoRoute,
+			method:     http.MethodPost,
+			uuid:       "weekend",
+			wantStatus: http.StatusOK,
+			wantBody:   `"uses":1`,
+		},
+		{
+			name:       "Redeem Used Up Promo Error",
+			handler:    RedeemPromoRoute,
+			method:     http.MethodPost,
+			uuid:       "weekend",
+			wantStatus: http.StatusConflict,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Missing Promo Error",
+			handler:    GetPromoRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Promo",
+			handler:    DeletePromoRoute,
+			method:     http.MethodDelete,
+			uuid:       "weekend",
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"weekend"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
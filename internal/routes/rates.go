@@ -3,9 +3,13 @@ package routes
 import (
 	"charlie-parker/internal/config"
 	"charlie-parker/internal/helpers"
+	"charlie-parker/internal/store"
 	"charlie-parker/pkg/types"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/gommon/log"
@@ -57,16 +61,179 @@ func CreateRateRoute(c echo.Context) error {
 		out.Error = fmt.Sprintf("Could not create rate in %s with error: %v", config.Config.RatesTable, err)
 		log.Error(out.Error)
 		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateRateRouteName)
-		return c.JSON(http.StatusInternalServerError, &out)
+		return c.JSON(getErrorStatus(err), &out)
 	}
 
 	out.Ok = true
 	out.Rate = newRate
+	setRateETag(c, out.Rate.Version)
 	log.Infof("Successfully created rate %s rates in %s", out.Rate.UUID, config.Config.RatesTable)
 	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreateRateRouteName)
 	return c.JSON(http.StatusOK, &out)
 }
 
+// GetUpcomingRateChangesRoute is the api handler that lists the dates on which rates are
+// scheduled to start or stop applying
+func GetUpcomingRateChangesRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetUpcomingRateChangesRouteName)
+	var (
+		err     error
+		changes []types.RateChange
+		out     types.GetUpcomingRateChangesOutput
+	)
+
+	if changes, err = helpers.GetUpcomingRateChanges(); err != nil {
+		out.Error = fmt.Sprintf("Could not get upcoming rate changes from %s with error: %v", config.Config.RatesTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetUpcomingRateChangesRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Changes = changes
+	log.Infof("Successfully got %d upcoming rate changes from %s", len(out.Changes), config.Config.RatesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetUpcomingRateChangesRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetRateRoute is the api handler that returns a single rate by its UUID
+func GetRateRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetRateRouteName)
+	var (
+		err  error
+		rate types.Rate
+		out  types.GetRateOutput
+	)
+
+	if rate, err = helpers.GetRate(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get rate %s from %s with error: %v", c.Param("uuid"), config.Config.RatesTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetRateRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Rate = rate
+	setRateETag(c, out.Rate.Version)
+	log.Infof("Successfully got rate %s from %s", out.Rate.UUID, config.Config.RatesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetRateRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// UpdateRateRoute is the api handler that replaces every field of a single rate
+func UpdateRateRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.UpdateRateRouteName)
+	var (
+		err     error
+		ifMatch int
+		in      types.CreateRateInput
+		rate    types.Rate
+		out     types.UpdateRateOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not update rate %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateRateRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if ifMatch, err = getIfMatchVersion(c); err != nil {
+		out.Error = fmt.Sprintf("Could not update rate %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateRateRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if rate, err = helpers.UpdateRate(c.Param("uuid"), ifMatch, &in); err != nil {
+		out.Error = fmt.Sprintf("Could not update rate %s in %s with error: %v", c.Param("uuid"), config.Config.RatesTable, err)
+		out.CurrentVersion = getCurrentVersion(c, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.UpdateRateRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Rate = rate
+	setRateETag(c, out.Rate.Version)
+	log.Infof("Successfully updated rate %s in %s", out.Rate.UUID, config.Config.RatesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.UpdateRateRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// PatchRateRoute is the api handler that changes only the given fields of a single rate
+func PatchRateRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.PatchRateRouteName)
+	var (
+		err     error
+		ifMatch int
+		in      types.PatchRateInput
+		rate    types.Rate
+		out     types.UpdateRateOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not patch rate %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.PatchRateRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if ifMatch, err = getIfMatchVersion(c); err != nil {
+		out.Error = fmt.Sprintf("Could not patch rate %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.PatchRateRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if rate, err = helpers.PatchRate(c.Param("uuid"), ifMatch, &in); err != nil {
+		out.Error = fmt.Sprintf("Could not patch rate %s in %s with error: %v", c.Param("uuid"), config.Config.RatesTable, err)
+		out.CurrentVersion = getCurrentVersion(c, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.PatchRateRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Rate = rate
+	setRateETag(c, out.Rate.Version)
+	log.Infof("Successfully patched rate %s in %s", out.Rate.UUID, config.Config.RatesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.PatchRateRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// DeleteRateRoute is the api handler that deletes a single rate by its UUID
+func DeleteRateRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.DeleteRateRouteName)
+	var (
+		err     error
+		ifMatch int
+		rate    types.Rate
+		out     types.DeleteRateOutput
+	)
+
+	if ifMatch, err = getIfMatchVersion(c); err != nil {
+		out.Error = fmt.Sprintf("Could not delete rate %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteRateRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if rate, err = helpers.DeleteRate(c.Param("uuid"), ifMatch); err != nil {
+		out.Error = fmt.Sprintf("Could not delete rate %s from %s with error: %v", c.Param("uuid"), config.Config.RatesTable, err)
+		out.CurrentVersion = getCurrentVersion(c, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.DeleteRateRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Rate = rate
+	log.Infof("Successfully deleted rate %s from %s", out.Rate.UUID, config.Config.RatesTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.DeleteRateRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
 // OverwriteRatesRoute is the api handler that overwrites the existing rates in the DB
 func OverwriteRatesRoute(c echo.Context) error {
 	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.OverwriteRatesRouteName)
@@ -88,7 +255,7 @@ func OverwriteRatesRoute(c echo.Context) error {
 		out.Error = fmt.Sprintf("Could not overwrite rates in %s with error: %v", config.Config.RatesTable, err)
 		log.Error(out.Error)
 		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.OverwriteRatesRouteName)
-		return c.JSON(http.StatusInternalServerError, &out)
+		return c.JSON(getErrorStatus(err), &out)
 	}
 
 	out.Ok = true
@@ -103,7 +270,7 @@ func GetTimespanPriceRoute(c echo.Context) error {
 	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetTimespanPriceRouteName)
 	var (
 		err   error
-		price string
+		quote types.Quote
 		in    types.GetTimespanPriceInput
 		out   types.GetTimespanPriceOutput
 	)
@@ -115,16 +282,66 @@ func GetTimespanPriceRoute(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, &out)
 	}
 
-	if price, err = helpers.GetTimespanPrice(&in); err != nil {
+	if quote, err = helpers.GetTimespanPrice(&in); err != nil {
 		out.Error = fmt.Sprintf("Could not get price with error: %v", err)
 		log.Error(out.Error)
 		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetTimespanPriceRouteName)
+		if errors.Is(err, helpers.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, &out)
+		}
 		return c.JSON(http.StatusInternalServerError, &out)
 	}
 
 	out.Ok = true
-	out.Price = price
+	out.Price = strconv.Itoa(quote.Total)
+	out.Quote = &quote
 	log.Infof("Successfully got price %s for time range %v -- %v from %s", out.Price, *in.Start, *in.End, config.Config.RatesTable)
 	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetTimespanPriceRouteName)
 	return c.JSON(http.StatusOK, &out)
 }
+
+// getErrorStatus is the status code for an error from a helper
+func getErrorStatus(err error) int {
+	if errors.Is(err, store.ErrNotFound) {
+		return http.StatusNotFound
+	} else if errors.Is(err, store.ErrConflict) || errors.Is(err, store.ErrLimitReached) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+const (
+	headerIfMatch = "If-Match"
+	headerETag    = "ETag"
+)
+
+// getIfMatchVersion returns the rate version in the request's If-Match header, or 0
+// if the header is not set or is "*". Weak and unquoted versions are accepted
+func getIfMatchVersion(c echo.Context) (int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match header: %s", header)
+	}
+	return version, nil
+}
+
+// setRateETag sets the response's ETag header to a rate version
+func setRateETag(c echo.Context, version int) {
+	c.Response().Header().Set(headerETag, fmt.Sprintf(`"%d"`, version))
+}
+
+// getCurrentVersion returns the stored rate version if err is a version conflict, and
+// sets it as the response's ETag so the client can retry against it
+func getCurrentVersion(c echo.Context, err error) int {
+	var conflict *store.VersionConflictError
+	if errors.As(err, &conflict) {
+		setRateETag(c, conflict.Current)
+		return conflict.Current
+	}
+	return 0
+}
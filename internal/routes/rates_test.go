@@ -0,0 +1,203 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/store"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_RatesRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Rate",
+			handler:    CreateRateRoute,
+			method:     http.MethodPost,
+			body:       `{"days": "fri", "times": "1600-1800", "tz": "America/Chicago", "price": 1800}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"times":"1600-1800"`,
+		},
+		{
+			name:       "Create Overlapping Rate Error",
+			handler:    CreateRateRoute,
+			method:     http.MethodPost,
+			body:       `{"days": "fri", "times": "1700-1900", "tz": "America/Chicago", "price": 1800}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Rates",
+			handler:    GetRatesRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"times":"1600-1800"`,
+		},
+		{
+			name:       "Get Upcoming Rate Changes",
+			handler:    GetUpcomingRateChangesRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"ok":true`,
+		},
+		{
+			name:       "Get Missing Rate Error",
+			handler:    GetRateRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Patch Missing Rate Error",
+			handler:    PatchRateRoute,
+			method:     http.MethodPatch,
+			uuid:       "missing",
+			body:       `{"price": 900}`,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Delete Missing Rate Error",
+			handler:    DeleteRateRoute,
+			method:     http.MethodDelete,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Timespan Price",
+			handler:    GetTimespanPriceRoute,
+			method:     http.MethodPost,
+			body:       `{"start": "2017-01-06T17:00:00-06:00", "end": "2017-01-06T18:00:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"price":"1800"`,
+		},
+		{
+			name:       "Get Too Long Timespan Price Error",
+			handler:    GetTimespanPriceRoute,
+			method:     http.MethodPost,
+			body:       `{"start": "2017-01-06T17:00:00-06:00", "end": "2117-01-06T18:00:00-06:00"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Timespan Price Invalid Vehicle Class Error",
+			handler:    GetTimespanPriceRoute,
+			method:     http.MethodPost,
+			body:       `{"start": "2017-01-06T17:00:00-06:00", "end": "2017-01-06T18:00:00-06:00", "vehicleClass": "bicycle"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Route Metrics",
+			handler:    GetAllRouteMetricsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"routeName":"GetTimespanPriceRoute"`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
+
+func Test_getIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "No Header",
+			want: 0,
+		},
+		{
+			name:   "Any Version",
+			header: "*",
+			want:   0,
+		},
+		{
+			name:   "Quoted Version",
+			header: `"3"`,
+			want:   3,
+		},
+		{
+			name:   "Weak Version",
+			header: `W/"3"`,
+			want:   3,
+		},
+		{
+			name:    "Invalid Version Error",
+			header:  `"abc"`,
+			wantErr: true,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if test.header != "" {
+				req.Header.Set(headerIfMatch, test.header)
+			}
+
+			got, err := getIfMatchVersion(e.NewContext(req, httptest.NewRecorder()))
+			if (err != nil) != test.wantErr {
+				t.Errorf("getIfMatchVersion() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+			if got != test.want {
+				t.Errorf("getIfMatchVersion() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
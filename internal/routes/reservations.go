@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetReservationsRoute is the api handler that returns all existing reservations from the DB
+func GetReservationsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetReservationsRouteName)
+	var (
+		err          error
+		reservations []types.Reservation
+		out          types.GetReservationsOutput
+	)
+
+	if reservations, err = helpers.GetReservations(); err != nil {
+		out.Error = fmt.Sprintf("Could not get reservations from %s with error: %v", config.Config.ReservationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetReservationsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Reservations = reservations
+	log.Infof("Successfully got all %d reservations from %s", len(out.Reservations), config.Config.ReservationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetReservationsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CreateReservationRoute is the api handler that books a new reservation
+func CreateReservationRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CreateReservationRouteName)
+	var (
+		err         error
+		in          types.CreateReservationInput
+		reservation types.Reservation
+		out         types.ReservationOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create reservation with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateReservationRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if reservation, err = helpers.CreateReservation(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not create reservation in %s with error: %v", config.Config.ReservationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CreateReservationRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Reservation = reservation
+	log.Infof("Successfully created reservation %s for %s in %s", out.Reservation.UUID, out.Reservation.Plate, config.Config.ReservationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CreateReservationRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetReservationRoute is the api handler that returns a single reservation by its uuid
+func GetReservationRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetReservationRouteName)
+	var (
+		err         error
+		reservation types.Reservation
+		out         types.ReservationOutput
+	)
+
+	if reservation, err = helpers.GetReservation(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get reservation %s from %s with error: %v", c.Param("uuid"), config.Config.ReservationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetReservationRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Reservation = reservation
+	log.Infof("Successfully got reservation %s from %s", out.Reservation.UUID, config.Config.ReservationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetReservationRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// ModifyReservationRoute is the api handler that changes a single booked reservation by its uuid
+func ModifyReservationRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.ModifyReservationRouteName)
+	var (
+		err         error
+		in          types.ModifyReservationInput
+		reservation types.Reservation
+		fee         int
+		out         types.ReservationOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not modify reservation %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ModifyReservationRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if reservation, fee, err = helpers.ModifyReservation(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not modify reservation %s in %s with error: %v", c.Param("uuid"), config.Config.ReservationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.ModifyReservationRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Reservation = reservation
+	out.Fee = fee
+	log.Infof("Successfully modified reservation %s with a fee of %d in %s", out.Reservation.UUID, out.Fee, config.Config.ReservationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.ModifyReservationRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// CancelReservationRoute is the api handler that cancels a single booked reservation by its uuid
+func CancelReservationRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.CancelReservationRouteName)
+	var (
+		err         error
+		reservation types.Reservation
+		fee         int
+		out         types.ReservationOutput
+	)
+
+	if reservation, fee, err = helpers.CancelReservation(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not cancel reservation %s in %s with error: %v", c.Param("uuid"), config.Config.ReservationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.CancelReservationRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Reservation = reservation
+	out.Fee = fee
+	log.Infof("Successfully cancelled reservation %s with a fee of %d in %s", out.Reservation.UUID, out.Fee, config.Config.ReservationsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.CancelReservationRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_ReservationsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Lots.Put(types.Lot{UUID: "garage", Name: "Garage", ReservationCapacity: 1}); err != nil {
+		t.Fatalf("Lots.Put() setup error = %v", err)
+	}
+	if _, err := helpers.CreateRate(&types.CreateRateInput{LotID: "garage", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+		t.Fatalf("CreateRate() setup error = %v", err)
+	}
+	booked := types.Reservation{UUID: "booked", LotID: "garage", Plate: "ABC123", Start: "2031-01-03T16:00:00-06:00", End: "2031-01-03T17:00:00-06:00", State: "booked", Price: 1800, Version: 1}
+	if err := config.Config.Reservations.Write(booked, 0, map[string]int{"garage#2031-01-03T22:00:00Z": 1}, nil); err != nil {
+		t.Fatalf("Reservations.Write() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Create Reservation",
+			handler:    CreateReservationRoute,
+			method:     http.MethodPost,
+			body:       `{"lotID": "garage", "plate": "xyz 789", "start": "2031-01-03T17:00:00-06:00", "end": "2031-01-03T18:00:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"price":1800`,
+		},
+		{
+			name:       "Create Full Reservation Error",
+			handler:    CreateReservationRoute,
+			method:     http.MethodPost,
+			body:       `{"lotID": "garage", "plate": "DEF456", "start": "2031-01-03T16:00:00-06:00", "end": "2031-01-03T17:00:00-06:00"}`,
+			wantStatus: http.StatusConflict,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Reservations",
+			handler:    GetReservationsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"booked"`,
+		},
+		{
+			name:       "Modify Reservation",
+			handler:    ModifyReservationRoute,
+			method:     http.MethodPut,
+			uuid:       "booked",
+			body:       `{"plate": "ABC123", "start": "2031-01-03T16:30:00-06:00", "end": "2031-01-03T17:00:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"version":2`,
+		},
+		{
+			name:       "Get Reservation",
+			handler:    GetReservationRoute,
+			method:     http.MethodGet,
+			uuid:       "booked",
+			wantStatus: http.StatusOK,
+			wantBody:   `"start":"2031-01-03T16:30:00-06:00"`,
+		},
+		{
+			name:       "Cancel Reservation",
+			handler:    CancelReservationRoute,
+			method:     http.MethodPost,
+			uuid:       "booked",
+			wantStatus: http.StatusOK,
+			wantBody:   `"state":"cancelled"`,
+		},
+		{
+			name:       "Get Missing Reservation Error",
+			handler:    GetReservationRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
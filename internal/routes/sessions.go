@@ -0,0 +1,241 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/pkg/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetSessionsRoute is the api handler that returns all existing sessions from the DB
+func GetSessionsRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetSessionsRouteName)
+	var (
+		err      error
+		sessions []types.Session
+		out      types.GetSessionsOutput
+	)
+
+	if sessions, err = helpers.GetSessions(); err != nil {
+		out.Error = fmt.Sprintf("Could not get sessions from %s with error: %v", config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetSessionsRouteName)
+		return c.JSON(http.StatusInternalServerError, &out)
+	}
+
+	out.Ok = true
+	out.Sessions = sessions
+	log.Infof("Successfully got all %d sessions from %s", len(out.Sessions), config.Config.SessionsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetSessionsRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// StartSessionRoute is the api handler that starts a new session
+func StartSessionRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.StartSessionRouteName)
+	var (
+		err     error
+		in      types.StartSessionInput
+		session types.Session
+		out     types.SessionOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not start session with error: %v", err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.StartSessionRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if session, err = helpers.StartSession(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not start session in %s with error: %v", config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.StartSessionRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Session = session
+	log.Infof("Successfully started session %s for %s in %s", out.Session.UUID, out.Session.Plate, config.Config.SessionsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.StartSessionRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// GetSessionRoute is the api handler that returns a single session by its uuid
+func GetSessionRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetSessionRouteName)
+	var (
+		err     error
+		session types.Session
+		out     types.SessionOutput
+	)
+
+	if session, err = helpers.GetSession(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get session %s from %s with error: %v", c.Param("uuid"), config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetSessionRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Session = session
+	log.Infof("Successfully got session %s from %s", out.Session.UUID, config.Config.SessionsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetSessionRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// EndSessionRoute is the api handler that ends a single active session by its uuid and charges it
+func EndSessionRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.EndSessionRouteName)
+	var (
+		err     error
+		in      types.EndSessionInput
+		session types.Session
+		out     types.SessionOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not end session %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.EndSessionRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if session, err = helpers.EndSession(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not end session %s in %s with error: %v", c.Param("uuid"), config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.EndSessionRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Session = session
+	log.Infof("Successfully ended session %s with a fee of %d in %s", out.Session.UUID, out.Session.Fee, config.Config.SessionsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.EndSessionRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// VoidSessionRoute is the api handler that voids a single session by its uuid
+func VoidSessionRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.VoidSessionRouteName)
+	var (
+		err     error
+		in      types.VoidSessionInput
+		session types.Session
+		out     types.SessionOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not void session %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.VoidSessionRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if session, err = helpers.VoidSession(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not void session %s in %s with error: %v", c.Param("uuid"), config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.VoidSessionRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Session = session
+	log.Infof("Successfully voided session %s in %s", out.Session.UUID, config.Config.SessionsTable)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.VoidSessionRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// RedeemValidationRoute is the api handler that redeems a merchant validation code for a single active session by its uuid
+func RedeemValidationRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.RedeemValidationRouteName)
+	var (
+		err        error
+		in         types.RedeemValidationInput
+		validation types.Validation
+		out        types.ValidationOutput
+	)
+
+	if err = c.Bind(&in); err != nil {
+		out.Error = fmt.Sprintf("Could not redeem validation for session %s with error: %v", c.Param("uuid"), err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.RedeemValidationRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if validation, err = helpers.RedeemValidation(c.Param("uuid"), &in); err != nil {
+		out.Error = fmt.Sprintf("Could not redeem validation for session %s in %s with error: %v", c.Param("uuid"), config.Config.ValidationsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.RedeemValidationRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	out.Ok = true
+	out.Validation = validation
+	log.Infof("Successfully redeemed validation %s for session %s", out.Validation.Code, out.Validation.SessionID)
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.RedeemValidationRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
+
+// receipt formats that the GetSessionReceiptRoute renders
+const (
+	receiptFormatJSON = "json"
+	receiptFormatText = "text"
+	receiptFormatHTML = "html"
+)
+
+// GetSessionReceiptRoute is the api handler that returns the receipt of a single session by
+// its uuid as JSON, or as plain text or printable HTML when the format query param says so
+func GetSessionReceiptRoute(c echo.Context) error {
+	defer helpers.UpdateRouteResponseTime(time.Now(), helpers.GetSessionReceiptRouteName)
+	var (
+		err     error
+		receipt types.Receipt
+		html    string
+		out     types.ReceiptOutput
+	)
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = receiptFormatJSON
+	}
+	if format != receiptFormatJSON && format != receiptFormatText && format != receiptFormatHTML {
+		out.Error = fmt.Sprintf("Could not get receipt of session %s with error: format must be %s, %s or %s: %s", c.Param("uuid"), receiptFormatJSON, receiptFormatText, receiptFormatHTML, format)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetSessionReceiptRouteName)
+		return c.JSON(http.StatusBadRequest, &out)
+	}
+
+	if receipt, err = helpers.GetSessionReceipt(c.Param("uuid")); err != nil {
+		out.Error = fmt.Sprintf("Could not get receipt of session %s from %s with error: %v", c.Param("uuid"), config.Config.SessionsTable, err)
+		log.Error(out.Error)
+		defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetSessionReceiptRouteName)
+		return c.JSON(getErrorStatus(err), &out)
+	}
+
+	log.Infof("Successfully got receipt %d of session %s from %s as %s", receipt.Number, c.Param("uuid"), config.Config.SessionsTable, format)
+	switch format {
+	case receiptFormatText:
+		defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetSessionReceiptRouteName)
+		return c.String(http.StatusOK, helpers.RenderReceiptText(receipt))
+	case receiptFormatHTML:
+		if html, err = helpers.RenderReceiptHTML(receipt); err != nil {
+			out.Error = fmt.Sprintf("Could not render receipt of session %s with error: %v", c.Param("uuid"), err)
+			log.Error(out.Error)
+			defer helpers.UpdateRouteSuccessFailureCount(false, helpers.GetSessionReceiptRouteName)
+			return c.JSON(http.StatusInternalServerError, &out)
+		}
+		defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetSessionReceiptRouteName)
+		return c.HTML(http.StatusOK, html)
+	}
+
+	out.Ok = true
+	out.Receipt = receipt
+	defer helpers.UpdateRouteSuccessFailureCount(true, helpers.GetSessionReceiptRouteName)
+	return c.JSON(http.StatusOK, &out)
+}
@@ -0,0 +1,176 @@
+package routes
+
+import (
+	"charlie-parker/internal/config"
+	"charlie-parker/internal/helpers"
+	"charlie-parker/internal/store"
+	"charlie-parker/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func Test_SessionsRoutes(t *testing.T) {
+	config.Config.Rates = store.NewMemoryRateStore()
+	config.Config.RouteMetrics = store.NewMemoryRouteMetricsStore()
+	config.Config.Calendars = store.NewMemoryCalendarStore()
+	config.Config.Lots = store.NewMemoryLotStore()
+	config.Config.Products = store.NewMemoryProductStore()
+	config.Config.Promos = store.NewMemoryPromoStore()
+	config.Config.Occupancies = store.NewMemoryOccupancyStore()
+	config.Config.Sessions = store.NewMemorySessionStore()
+	config.Config.Reservations = store.NewMemoryReservationStore()
+	config.Config.Permits = store.NewMemoryPermitStore()
+	config.Config.Merchants = store.NewMemoryMerchantStore()
+	config.Config.Validations = store.NewMemoryValidationStore()
+	if err := config.Config.Sessions.Create(types.Session{UUID: "parked", Plate: "ABC123", State: "active", EntryTime: "2017-01-06T17:00:00-06:00"}); err != nil {
+		t.Fatalf("Sessions.Create() setup error = %v", err)
+	}
+	if err := config.Config.Sessions.Create(types.Session{UUID: "waiting", Plate: "DEF456", State: "active", EntryTime: "2017-01-06T17:00:00-06:00"}); err != nil {
+		t.Fatalf("Sessions.Create() setup error = %v", err)
+	}
+	if _, err := helpers.CreateRate(&types.CreateRateInput{Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}, true, true); err != nil {
+		t.Fatalf("CreateRate() setup error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		method     string
+		uuid       string
+		query      string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Start Session",
+			handler:    StartSessionRoute,
+			method:     http.MethodPost,
+			body:       `{"plate": "xyz 789", "entryTime": "2017-01-06T16:30:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"plate":"XYZ789"`,
+		},
+		{
+			name:       "Start Already Parked Session Error",
+			handler:    StartSessionRoute,
+			method:     http.MethodPost,
+			body:       `{"plate": "ABC123"}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Sessions",
+			handler:    GetSessionsRoute,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `"UUID":"parked"`,
+		},
+		{
+			name:       "End Session",
+			handler:    EndSessionRoute,
+			method:     http.MethodPost,
+			uuid:       "parked",
+			body:       `{"exitTime": "2017-01-06T18:00:00-06:00"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"fee":1800`,
+		},
+		{
+			name:       "Get Session",
+			handler:    GetSessionRoute,
+			method:     http.MethodGet,
+			uuid:       "parked",
+			wantStatus: http.StatusOK,
+			wantBody:   `"state":"closed"`,
+		},
+		{
+			name:       "Get Session Receipt",
+			handler:    GetSessionReceiptRoute,
+			method:     http.MethodGet,
+			uuid:       "parked",
+			wantStatus: http.StatusOK,
+			wantBody:   `"number":1`,
+		},
+		{
+			name:       "Get Session Receipt As Text",
+			handler:    GetSessionReceiptRoute,
+			method:     http.MethodGet,
+			uuid:       "parked",
+			query:      "format=text",
+			wantStatus: http.StatusOK,
+			wantBody:   "RECEIPT 000001",
+		},
+		{
+			name:       "Get Session Receipt As HTML",
+			handler:    GetSessionReceiptRoute,
+			method:     http.MethodGet,
+			uuid:       "parked",
+			query:      "format=html",
+			wantStatus: http.StatusOK,
+			wantBody:   "<h1>Receipt 000001</h1>",
+		},
+		{
+			name:       "Get Session Receipt Unknown Format Error",
+			handler:    GetSessionReceiptRoute,
+			method:     http.MethodGet,
+			uuid:       "parked",
+			query:      "format=pdf",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Void Session",
+			handler:    VoidSessionRoute,
+			method:     http.MethodPost,
+			uuid:       "waiting",
+			body:       `{"reason": "gate malfunction"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"state":"voided"`,
+		},
+		{
+			name:       "Void Closed Session Error",
+			handler:    VoidSessionRoute,
+			method:     http.MethodPost,
+			uuid:       "parked",
+			body:       `{"reason": "gate malfunction"}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `"error":`,
+		},
+		{
+			name:       "Get Missing Session Error",
+			handler:    GetSessionRoute,
+			method:     http.MethodGet,
+			uuid:       "missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"error":`,
+		},
+	}
+
+	e := echo.New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/?"+test.query, strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("uuid")
+			c.SetParamValues(test.uuid)
+
+			if err := test.handler(c); err != nil {
+				t.Errorf("%s error = %v", test.name, err)
+				return
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("%s status = %d, want %d (body: %s)", test.name, rec.Code, test.wantStatus, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), test.wantBody) {
+				t.Errorf("%s body = %s, want it to contain %s", test.name, rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
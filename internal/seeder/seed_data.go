@@ -61,6 +61,377 @@ var routeMetricsSeed = []types.RouteMetrics{
 		LastUpdated:     time.Now().Unix(),
 		AvgResponseTime: "0s",
 	},
+	{
+		UUID:            "4fa1f374-b62a-4a2f-8249-d3cb54a5058c",
+		RouteName:       helpers.GetUpcomingRateChangesRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "99bfb968-ce9f-4997-ba42-063a1904977a",
+		RouteName:       helpers.GetRateRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "d853ce3e-6c97-4603-914d-fddb385d5ef4",
+		RouteName:       helpers.UpdateRateRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "60214eec-0aea-449d-a6c6-bb9992985e75",
+		RouteName:       helpers.PatchRateRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "d17a7438-8f74-4ebc-b80c-5965effff7dd",
+		RouteName:       helpers.DeleteRateRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "7b5d0dbf-90e1-49b7-b43e-fd2850abbbf7",
+		RouteName:       helpers.GetCalendarsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "969627d5-dc4a-488a-9531-b5b51929e772",
+		RouteName:       helpers.GetCalendarRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "a5dc822c-4489-4b4f-ab58-d3599586be2a",
+		RouteName:       helpers.PutCalendarRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "c44c9e6a-d772-41b7-a165-5d9d658d5906",
+		RouteName:       helpers.ImportCalendarRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "cdeece56-60c2-4578-8f70-538e26a66d6f",
+		RouteName:       helpers.DeleteCalendarRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "072e6412-131e-4b64-82a8-fe7305de260c",
+		RouteName:       helpers.GetLotsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "b6cc5486-145b-43b3-9195-dd22509b7acb",
+		RouteName:       helpers.CreateLotRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "39cbf631-8e2f-4c8e-99fd-a5611357d015",
+		RouteName:       helpers.GetLotRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "6855d8e9-ebff-41bb-ae66-a9d52acb1f9e",
+		RouteName:       helpers.UpdateLotRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "e0393f79-07e8-4e04-9d9a-f59649ffeeaf",
+		RouteName:       helpers.DeleteLotRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "34b6a659-8b10-422c-b171-1a31f427065b",
+		RouteName:       helpers.GetProductsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "c5bb0348-09a0-43f5-9342-c34f01219da0",
+		RouteName:       helpers.CreateProductRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "b2c47ca2-22cb-469b-9fd5-7805e4184c5e",
+		RouteName:       helpers.GetProductRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "6c1ba22a-e564-4e0d-92d1-c0d5b85a04cd",
+		RouteName:       helpers.UpdateProductRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "6a84051a-7810-43fd-8205-76b09c1850fc",
+		RouteName:       helpers.DeleteProductRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "811a3d87-6243-4e14-822e-8b4e52d1a16f",
+		RouteName:       helpers.GetPromosRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "2e156cbe-811e-4bca-9883-8bb8aa9aa1bd",
+		RouteName:       helpers.CreatePromoRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "953ab1c6-0606-4157-bbd7-500e84ceb796",
+		RouteName:       helpers.GetPromoRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "8a02446d-0d49-4ff9-a87c-d493e14c9701",
+		RouteName:       helpers.UpdatePromoRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "c0e6e402-9be2-4086-ad8c-bb817d4c0e04",
+		RouteName:       helpers.DeletePromoRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "cf829963-1317-44cd-bf91-d2c69d4b0fd7",
+		RouteName:       helpers.RedeemPromoRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "800598cf-1241-4a71-857c-ad5ef744be9d",
+		RouteName:       helpers.ReportOccupancyRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "22e5d8e7-4050-4077-98af-a6e8df510ad8",
+		RouteName:       helpers.GetOccupancyRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "0373c460-a2c6-4486-9d2d-9f80bc931857",
+		RouteName:       helpers.GetSessionsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "0dc3edf1-52a3-4476-99bc-db3a5b1c9f92",
+		RouteName:       helpers.StartSessionRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "ad37a834-81de-47d5-ab6f-236c870aa7fa",
+		RouteName:       helpers.GetSessionRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "e3b62579-dd84-4154-b1b4-1d3d2049c581",
+		RouteName:       helpers.EndSessionRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "73e1a176-b806-4642-bf71-ce6ab05fa772",
+		RouteName:       helpers.VoidSessionRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "6d8e9dae-5bbf-4823-8f42-6652a1d61c7f",
+		RouteName:       helpers.GetReservationsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "4bac096e-9eff-4699-8427-b3d97f66fa25",
+		RouteName:       helpers.CreateReservationRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "0e281559-0889-4261-bb40-b27d3fccc62c",
+		RouteName:       helpers.GetReservationRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "3528c436-c0a6-4607-b010-7ba8f0028dea",
+		RouteName:       helpers.ModifyReservationRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "d41beb15-7c54-42cb-b762-d199bf42f813",
+		RouteName:       helpers.CancelReservationRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "11ad04c3-74be-4797-8c9a-883373fdfe66",
+		RouteName:       helpers.GetSessionReceiptRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "7e08ee74-1c13-496a-b877-c85e4f422a54",
+		RouteName:       helpers.GetPermitsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "d7a9dd1d-86a8-4c97-94f1-917fdbd98610",
+		RouteName:       helpers.CreatePermitRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "22054b01-b253-4378-a373-a02c437ad2b0",
+		RouteName:       helpers.GetPermitRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "e8312c50-8220-4812-8c42-68a0cef0c32f",
+		RouteName:       helpers.UpdatePermitRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "997a5f52-850f-48ec-8403-e064a5cdd7c3",
+		RouteName:       helpers.DeletePermitRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "f20a3869-e33a-4609-88cd-e5032e55ca1b",
+		RouteName:       helpers.GetMerchantsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "c511a654-3cf2-400f-a493-ed24e3e04e3f",
+		RouteName:       helpers.CreateMerchantRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "12e28ad6-d8af-42b1-be69-210167ac9b61",
+		RouteName:       helpers.GetMerchantRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "8c8f7be0-c320-4f81-9f9e-3e3ea6fd6479",
+		RouteName:       helpers.UpdateMerchantRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "bef87a09-80db-429c-818b-464270fc67c8",
+		RouteName:       helpers.DeleteMerchantRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "81bcd58f-f5c5-42f5-8efb-f451c64c1f0a",
+		RouteName:       helpers.IssueValidationsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "a2e1e698-02c1-42ad-b888-5354dd53842f",
+		RouteName:       helpers.GetMerchantValidationsRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "099b52d2-a553-4856-8bbb-52430744f000",
+		RouteName:       helpers.GetMerchantStatementRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
+	{
+		UUID:            "6ad07d68-da5c-4bd0-948e-049e1eaf6bf2",
+		RouteName:       helpers.RedeemValidationRouteName,
+		CreatedAt:       time.Now().Unix(),
+		LastUpdated:     time.Now().Unix(),
+		AvgResponseTime: "0s",
+	},
 	{
 		UUID:            "623bc8e5-330a-428f-b906-41e2d18293ca",
 		RouteName:       helpers.GetTimespanPriceRouteName,
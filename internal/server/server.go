@@ -31,6 +31,67 @@ func Start() {
 	v1.GET("/rates", routes.GetRatesRoute)
 	v1.POST("/rates/create", routes.CreateRateRoute)
 	v1.POST("/rates/update/all", routes.OverwriteRatesRoute)
+	v1.GET("/rates/upcoming", routes.GetUpcomingRateChangesRoute)
+	v1.GET("/rates/:uuid", routes.GetRateRoute)
+	v1.PUT("/rates/:uuid", routes.UpdateRateRoute)
+	v1.PATCH("/rates/:uuid", routes.PatchRateRoute)
+	v1.DELETE("/rates/:uuid", routes.DeleteRateRoute)
+	// CALENDARS
+	v1.GET("/calendars", routes.GetCalendarsRoute)
+	v1.GET("/calendars/:name", routes.GetCalendarRoute)
+	v1.PUT("/calendars/:name", routes.PutCalendarRoute)
+	v1.POST("/calendars/:name/import", routes.ImportCalendarRoute)
+	v1.DELETE("/calendars/:name", routes.DeleteCalendarRoute)
+	// LOTS
+	v1.GET("/lots", routes.GetLotsRoute)
+	v1.POST("/lots/create", routes.CreateLotRoute)
+	v1.GET("/lots/:uuid", routes.GetLotRoute)
+	v1.PUT("/lots/:uuid", routes.UpdateLotRoute)
+	v1.DELETE("/lots/:uuid", routes.DeleteLotRoute)
+	v1.POST("/lots/:uuid/occupancy", routes.ReportOccupancyRoute)
+	v1.GET("/lots/:uuid/occupancy", routes.GetOccupancyRoute)
+	// PRODUCTS
+	v1.GET("/products", routes.GetProductsRoute)
+	v1.POST("/products/create", routes.CreateProductRoute)
+	v1.GET("/products/:uuid", routes.GetProductRoute)
+	v1.PUT("/products/:uuid", routes.UpdateProductRoute)
+	v1.DELETE("/products/:uuid", routes.DeleteProductRoute)
+	// PROMOS
+	v1.GET("/promos", routes.GetPromosRoute)
+	v1.POST("/promos/create", routes.CreatePromoRoute)
+	v1.GET("/promos/:uuid", routes.GetPromoRoute)
+	v1.PUT("/promos/:uuid", routes.UpdatePromoRoute)
+	v1.DELETE("/promos/:uuid", routes.DeletePromoRoute)
+	v1.POST("/promos/:uuid/redeem", routes.RedeemPromoRoute)
+	// PERMITS
+	v1.GET("/permits", routes.GetPermitsRoute)
+	v1.POST("/permits/create", routes.CreatePermitRoute)
+	v1.GET("/permits/:uuid", routes.GetPermitRoute)
+	v1.PUT("/permits/:uuid", routes.UpdatePermitRoute)
+	v1.DELETE("/permits/:uuid", routes.DeletePermitRoute)
+	// MERCHANTS
+	v1.GET("/merchants", routes.GetMerchantsRoute)
+	v1.POST("/merchants/create", routes.CreateMerchantRoute)
+	v1.GET("/merchants/:uuid", routes.GetMerchantRoute)
+	v1.PUT("/merchants/:uuid", routes.UpdateMerchantRoute)
+	v1.DELETE("/merchants/:uuid", routes.DeleteMerchantRoute)
+	v1.POST("/merchants/:uuid/validations", routes.IssueValidationsRoute)
+	v1.GET("/merchants/:uuid/validations", routes.GetMerchantValidationsRoute)
+	v1.GET("/merchants/:uuid/statement", routes.GetMerchantStatementRoute)
+	// SESSIONS
+	v1.GET("/sessions", routes.GetSessionsRoute)
+	v1.POST("/sessions/start", routes.StartSessionRoute)
+	v1.GET("/sessions/:uuid", routes.GetSessionRoute)
+	v1.POST("/sessions/:uuid/end", routes.EndSessionRoute)
+	v1.POST("/sessions/:uuid/void", routes.VoidSessionRoute)
+	v1.POST("/sessions/:uuid/validate", routes.RedeemValidationRoute)
+	v1.GET("/sessions/:uuid/receipt", routes.GetSessionReceiptRoute)
+	// RESERVATIONS
+	v1.GET("/reservations", routes.GetReservationsRoute)
+	v1.POST("/reservations/create", routes.CreateReservationRoute)
+	v1.GET("/reservations/:uuid", routes.GetReservationRoute)
+	v1.PUT("/reservations/:uuid", routes.ModifyReservationRoute)
+	v1.POST("/reservations/:uuid/cancel", routes.CancelReservationRoute)
 	// PARKING PRICE
 	v1.POST("/park", routes.GetTimespanPriceRoute)
 
@@ -0,0 +1,873 @@
+package store
+
+import (
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/dynamo"
+	"github.com/labstack/gommon/log"
+)
+
+// isConditionFailed reports whether err was caused by a failed condition expression,
+// including one that cancelled a transaction
+func isConditionFailed(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException ||
+			aerr.Code() == dynamodb.ErrCodeTransactionCanceledException
+	}
+	return false
+}
+
+//-----------------------------------------------------------------------------
+// RATES ----------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// activeRateSet is the Name of the RateSet item that points at the active set of rates
+const activeRateSet = "active"
+
+// readAttempts is how many times a read is retried when the data it covers
+// changes underneath it
+const readAttempts = 3
+
+// dynamoRateStore is a RateStore backed by a DynamoDB table. Every rate belongs
+// to a versioned set (Rate.SetID) and only the set named by the active RateSet
+// in the sets table is visible to readers. Rates written before rate sets existed
+// have no SetID and stay visible until the first Replace. The active RateSet's
+// Revision is bumped in the same transaction as every write
+type dynamoRateStore struct {
+	db    *dynamo.DB
+	table dynamo.Table
+	sets  dynamo.Table
+}
+
+// NewDynamoRateStore returns a RateStore that reads and writes rates in table and
+// tracks the active rate set in sets. Both tables must belong to db
+func NewDynamoRateStore(db *dynamo.DB, table, sets dynamo.Table) RateStore {
+	return &dynamoRateStore{db: db, table: table, sets: sets}
+}
+
+func (s *dynamoRateStore) All() ([]types.Rate, error) {
+	for attempt := 0; attempt < readAttempts; attempt++ {
+		before, _, err := s.activeSet()
+		if err != nil {
+			return nil, err
+		}
+
+		rates, err := s.ratesInSet(before.SetID)
+		if err != nil {
+			return nil, err
+		}
+
+		// a set's rates are only garbage collected once it is no longer active,
+		// so if the pointer did not move the scan saw one complete set
+		after, _, err := s.activeSet()
+		if err != nil {
+			return nil, err
+		}
+		if before.SetID == after.SetID {
+			return rates, nil
+		}
+	}
+	return nil, errors.New("rates kept changing while being read, try again")
+}
+
+// Get only finds rates in the active set, the same rates All returns
+func (s *dynamoRateStore) Get(uuid string) (types.Rate, error) {
+	var rate types.Rate
+	set, _, err := s.activeSet()
+	if err != nil {
+		return rate, err
+	}
+
+	err = s.table.Get("UUID", uuid).Consistent(true).One(&rate)
+	if err == dynamo.ErrNotFound || (err == nil && rate.SetID != set.SetID) {
+		return types.Rate{}, ErrNotFound
+	}
+	return rate, err
+}
+
+func (s *dynamoRateStore) Revision() (int, error) {
+	set, _, err := s.activeSet()
+	return set.Revision, err
+}
+
+func (s *dynamoRateStore) Create(rate types.Rate, revision int) error {
+	set, _, err := s.activeSet()
+	if err != nil {
+		return err
+	}
+
+	rate.SetID = set.SetID
+	put := s.table.Put(&rate).If("attribute_not_exists('UUID')")
+	if err = s.db.WriteTx().Put(put).Update(s.bumpRevision(revision)).Run(); isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoRateStore) Update(rate types.Rate, version, revision int) error {
+	set, _, err := s.activeSet()
+	if err != nil {
+		return err
+	}
+
+	rate.SetID = set.SetID
+	put := s.table.Put(&rate)
+	if version == 0 {
+		// rates written before versions existed have no Version
+		put = put.If("attribute_exists('UUID') AND (attribute_not_exists('Version') OR 'Version' = ?)", version)
+	} else {
+		put = put.If("'Version' = ?", version)
+	}
+	if err = s.db.WriteTx().Put(put).Update(s.bumpRevision(revision)).Run(); isConditionFailed(err) {
+		return s.conflict(rate.UUID, version)
+	}
+	return err
+}
+
+func (s *dynamoRateStore) Delete(uuid string, version int) error {
+	del := s.table.Delete("UUID", uuid)
+	if version == 0 {
+		del = del.If("attribute_exists('UUID') AND (attribute_not_exists('Version') OR 'Version' = ?)", version)
+	} else {
+		del = del.If("'Version' = ?", version)
+	}
+	bump := s.sets.Update("Name", activeRateSet).Add("Revision", 1).Set("UpdatedAt", time.Now().Unix())
+	err := s.db.WriteTx().Delete(del).Update(bump).Run()
+	if isConditionFailed(err) {
+		return s.conflict(uuid, version)
+	}
+	return err
+}
+
+// bumpRevision moves the active RateSet from revision to the next revision,
+// creating it if no rate set has been written yet
+func (s *dynamoRateStore) bumpRevision(revision int) *dynamo.Update {
+	update := s.sets.Update("Name", activeRateSet).Add("Revision", 1).Set("UpdatedAt", time.Now().Unix())
+	if revision == 0 {
+		return update.If("attribute_not_exists('Revision') OR 'Revision' = ?", revision)
+	}
+	return update.If("'Revision' = ?", revision)
+}
+
+// conflict works out why a write to the rate with the given uuid at version failed
+func (s *dynamoRateStore) conflict(uuid string, version int) error {
+	current, err := s.Get(uuid)
+	if err != nil {
+		return err
+	}
+	if current.Version != version {
+		return &VersionConflictError{UUID: uuid, Current: current.Version}
+	}
+	return ErrConflict
+}
+
+// Replace writes rates as a new, inactive set and then makes it the active set
+// with one conditional write on the RateSet pointer. A failure before the switch
+// leaves the old set active and untouched; the old set is only garbage collected
+// after the switch succeeds
+func (s *dynamoRateStore) Replace(rates []types.Rate) error {
+	old, exists, err := s.activeSet()
+	if err != nil {
+		return err
+	}
+
+	uu, _ := uuid.NewV4()
+	next := types.RateSet{
+		Name:      activeRateSet,
+		SetID:     uu.String(),
+		Revision:  old.Revision + 1,
+		UpdatedAt: time.Now().Unix(),
+	}
+
+	items := make([]interface{}, len(rates))
+	for i, rate := range rates {
+		rate.SetID = next.SetID
+		items[i] = rate
+	}
+	if len(items) > 0 {
+		if _, err = s.table.Batch("UUID").Write().Put(items...).Run(); err != nil {
+			s.removeSet(next.SetID)
+			return fmt.Errorf("could not write new rate set: %v", err)
+		}
+	}
+
+	put := s.sets.Put(&next)
+	if exists {
+		put = put.If("'Revision' = ?", old.Revision)
+	} else {
+		put = put.If("attribute_not_exists('Name')")
+	}
+	if err = put.Run(); err != nil {
+		s.removeSet(next.SetID)
+		if isConditionFailed(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("could not activate new rate set: %v", err)
+	}
+
+	s.removeSet(old.SetID)
+	return nil
+}
+
+// activeSet returns the active RateSet and whether one has been written yet
+func (s *dynamoRateStore) activeSet() (types.RateSet, bool, error) {
+	var set types.RateSet
+	err := s.sets.Get("Name", activeRateSet).Consistent(true).One(&set)
+	if err == dynamo.ErrNotFound {
+		return set, false, nil
+	}
+	return set, err == nil, err
+}
+
+// ratesInSet scans for the rates that belong to the set with the given setID
+func (s *dynamoRateStore) ratesInSet(setID string) ([]types.Rate, error) {
+	var rates []types.Rate
+	scan := s.table.Scan().Consistent(true)
+	if setID == "" {
+		scan = scan.Filter("attribute_not_exists('SetID')")
+	} else {
+		scan = scan.Filter("'SetID' = ?", setID)
+	}
+	err := scan.All(&rates)
+	return rates, err
+}
+
+// removeSet is a best-effort clean up of every rate in an inactive set. Leftovers
+// are harmless since readers never see rates outside of the active set
+func (s *dynamoRateStore) removeSet(setID string) {
+	rates, err := s.ratesInSet(setID)
+	if err != nil {
+		log.Errorf("Could not find rates in inactive rate set %q to remove: %v", setID, err)
+		return
+	}
+
+	keys := make([]dynamo.Keyed, len(rates))
+	for i, rate := range rates {
+		keys[i] = dynamo.Keys{rate.UUID}
+	}
+	if len(keys) > 0 {
+		if _, err = s.table.Batch("UUID").Write().Delete(keys...).Run(); err != nil {
+			log.Errorf("Could not remove rates in inactive rate set %q: %v", setID, err)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// CALENDARS ------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoCalendarStore is a CalendarStore backed by a DynamoDB table
+type dynamoCalendarStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoCalendarStore returns a CalendarStore that reads and writes calendars in table
+func NewDynamoCalendarStore(table dynamo.Table) CalendarStore {
+	return &dynamoCalendarStore{table: table}
+}
+
+func (s *dynamoCalendarStore) All() ([]types.Calendar, error) {
+	var calendars []types.Calendar
+	err := s.table.Scan().Consistent(true).All(&calendars)
+	return calendars, err
+}
+
+func (s *dynamoCalendarStore) Get(name string) (types.Calendar, error) {
+	var calendar types.Calendar
+	err := s.table.Get("Name", name).Consistent(true).One(&calendar)
+	if err == dynamo.ErrNotFound {
+		return calendar, ErrNotFound
+	}
+	return calendar, err
+}
+
+func (s *dynamoCalendarStore) Put(calendar types.Calendar) error {
+	return s.table.Put(&calendar).Run()
+}
+
+func (s *dynamoCalendarStore) Delete(name string) error {
+	return s.table.Delete("Name", name).Run()
+}
+
+//-----------------------------------------------------------------------------
+// LOTS -----------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoLotStore is a LotStore backed by a DynamoDB table
+type dynamoLotStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoLotStore returns a LotStore that reads and writes lots in table
+func NewDynamoLotStore(table dynamo.Table) LotStore {
+	return &dynamoLotStore{table: table}
+}
+
+func (s *dynamoLotStore) All() ([]types.Lot, error) {
+	var lots []types.Lot
+	err := s.table.Scan().Consistent(true).All(&lots)
+	return lots, err
+}
+
+func (s *dynamoLotStore) Get(uuid string) (types.Lot, error) {
+	var lot types.Lot
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&lot)
+	if err == dynamo.ErrNotFound {
+		return lot, ErrNotFound
+	}
+	return lot, err
+}
+
+func (s *dynamoLotStore) Put(lot types.Lot) error {
+	return s.table.Put(&lot).Run()
+}
+
+func (s *dynamoLotStore) Delete(uuid string) error {
+	return s.table.Delete("UUID", uuid).Run()
+}
+
+//-----------------------------------------------------------------------------
+// PRODUCTS -------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoProductStore is a ProductStore backed by a DynamoDB table
+type dynamoProductStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoProductStore returns a ProductStore that reads and writes products in table
+func NewDynamoProductStore(table dynamo.Table) ProductStore {
+	return &dynamoProductStore{table: table}
+}
+
+func (s *dynamoProductStore) All() ([]types.Product, error) {
+	var products []types.Product
+	err := s.table.Scan().Consistent(true).All(&products)
+	return products, err
+}
+
+func (s *dynamoProductStore) Get(uuid string) (types.Product, error) {
+	var product types.Product
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&product)
+	if err == dynamo.ErrNotFound {
+		return product, ErrNotFound
+	}
+	return product, err
+}
+
+func (s *dynamoProductStore) Put(product types.Product) error {
+	return s.table.Put(&product).Run()
+}
+
+func (s *dynamoProductStore) Delete(uuid string) error {
+	return s.table.Delete("UUID", uuid).Run()
+}
+
+//-----------------------------------------------------------------------------
+// PROMOS ---------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoPromoStore is a PromoStore backed by a DynamoDB table
+type dynamoPromoStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoPromoStore returns a PromoStore that reads and writes promos in table
+func NewDynamoPromoStore(table dynamo.Table) PromoStore {
+	return &dynamoPromoStore{table: table}
+}
+
+func (s *dynamoPromoStore) All() ([]types.Promo, error) {
+	var promos []types.Promo
+	err := s.table.Scan().Consistent(true).All(&promos)
+	return promos, err
+}
+
+func (s *dynamoPromoStore) Get(uuid string) (types.Promo, error) {
+	var promo types.Promo
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&promo)
+	if err == dynamo.ErrNotFound {
+		return promo, ErrNotFound
+	}
+	return promo, err
+}
+
+func (s *dynamoPromoStore) Put(promo types.Promo) error {
+	return s.table.Put(&promo).Run()
+}
+
+func (s *dynamoPromoStore) Update(promo types.Promo, uses int) error {
+	err := s.table.Put(&promo).If("attribute_exists('UUID') AND 'Uses' = ?", uses).Run()
+	if isConditionFailed(err) {
+		if _, err = s.Get(promo.UUID); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoPromoStore) Delete(uuid string) error {
+	return s.table.Delete("UUID", uuid).Run()
+}
+
+func (s *dynamoPromoStore) Redeem(uuid string) (types.Promo, error) {
+	var promo types.Promo
+	err := s.table.Update("UUID", uuid).
+		Add("Uses", 1).
+		If("attribute_exists('UUID') AND (attribute_not_exists('MaxUses') OR 'Uses' < 'MaxUses')").
+		Value(&promo)
+	if isConditionFailed(err) {
+		if promo, err = s.Get(uuid); err != nil {
+			return promo, err
+		}
+		return promo, ErrLimitReached
+	}
+	return promo, err
+}
+
+func (s *dynamoPromoStore) Unredeem(uuid string) error {
+	err := s.table.Update("UUID", uuid).
+		Add("Uses", -1).
+		If("attribute_exists('UUID') AND 'Uses' > ?", 0).
+		Run()
+	if isConditionFailed(err) {
+		if _, err = s.Get(uuid); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return err
+}
+
+//-----------------------------------------------------------------------------
+// OCCUPANCIES ----------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoOccupancyStore is an OccupancyStore backed by a DynamoDB table
+type dynamoOccupancyStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoOccupancyStore returns an OccupancyStore that reads and writes lot occupancies in table
+func NewDynamoOccupancyStore(table dynamo.Table) OccupancyStore {
+	return &dynamoOccupancyStore{table: table}
+}
+
+func (s *dynamoOccupancyStore) All() ([]types.Occupancy, error) {
+	var occupancies []types.Occupancy
+	err := s.table.Scan().Consistent(true).All(&occupancies)
+	return occupancies, err
+}
+
+func (s *dynamoOccupancyStore) Get(lotID string) (types.Occupancy, error) {
+	var occupancy types.Occupancy
+	err := s.table.Get("LotID", lotID).Consistent(true).One(&occupancy)
+	if err == dynamo.ErrNotFound {
+		return occupancy, ErrNotFound
+	}
+	return occupancy, err
+}
+
+func (s *dynamoOccupancyStore) Put(occupancy types.Occupancy) error {
+	return s.table.Put(&occupancy).Run()
+}
+
+func (s *dynamoOccupancyStore) Delete(lotID string) error {
+	return s.table.Delete("LotID", lotID).Run()
+}
+
+//-----------------------------------------------------------------------------
+// SESSIONS -------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// defaultReceiptCounter is the LotID of the ReceiptCounter of sessions without a lot
+const defaultReceiptCounter = "default"
+
+// sessionActive is the state in which a session holds its plate in its lot
+const sessionActive = "active"
+
+// dynamoSessionStore is a SessionStore backed by a DynamoDB table. The last receipt
+// issued in each lot is kept in the counters table, and the plates of active sessions
+// in the plates table, which are written in the same transaction as the session
+type dynamoSessionStore struct {
+	db       *dynamo.DB
+	table    dynamo.Table
+	counters dynamo.Table
+	plates   dynamo.Table
+}
+
+// NewDynamoSessionStore returns a SessionStore that reads and writes sessions in table,
+// counts the receipts issued in each lot in counters, and holds the plates of active
+// sessions in plates. All of the tables must belong to db
+func NewDynamoSessionStore(db *dynamo.DB, table, counters, plates dynamo.Table) SessionStore {
+	return &dynamoSessionStore{db: db, table: table, counters: counters, plates: plates}
+}
+
+func (s *dynamoSessionStore) All() ([]types.Session, error) {
+	var sessions []types.Session
+	err := s.table.Scan().Consistent(true).All(&sessions)
+	return sessions, err
+}
+
+func (s *dynamoSessionStore) Get(uuid string) (types.Session, error) {
+	var session types.Session
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&session)
+	if err == dynamo.ErrNotFound {
+		return session, ErrNotFound
+	}
+	return session, err
+}
+
+func (s *dynamoSessionStore) Create(session types.Session) error {
+	tx := s.db.WriteTx().Put(s.table.Put(&session).If("attribute_not_exists('UUID')"))
+	if session.State == sessionActive {
+		plate := types.ActivePlate{Key: activePlateKey(session.LotID, session.Plate), SessionID: session.UUID}
+		tx = tx.Put(s.plates.Put(&plate).If("attribute_not_exists('Key')"))
+	}
+
+	err := tx.Run()
+	if isConditionFailed(err) {
+		if _, err = s.Get(session.UUID); err == nil {
+			return fmt.Errorf("session %s already exists", session.UUID)
+		}
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoSessionStore) Transition(session types.Session, from string) error {
+	err := s.releasePlate(s.db.WriteTx().Put(s.table.Put(&session).If("'State' = ?", from)), session, from).Run()
+	if isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoSessionStore) LastReceipt(lotID string) (int, error) {
+	var counter types.ReceiptCounter
+	err := s.counters.Get("LotID", receiptCounterID(lotID)).Consistent(true).One(&counter)
+	if err == dynamo.ErrNotFound {
+		return 0, nil
+	}
+	return counter.Last, err
+}
+
+func (s *dynamoSessionStore) Issue(session types.Session, from string) error {
+	if session.Receipt == nil {
+		return fmt.Errorf("session %s has no receipt to issue", session.UUID)
+	}
+
+	number := session.Receipt.Number
+	update := s.counters.Update("LotID", receiptCounterID(session.LotID)).Set("Last", number)
+	if number == 1 {
+		update = update.If("attribute_not_exists('Last')")
+	} else {
+		update = update.If("'Last' = ?", number-1)
+	}
+	err := s.releasePlate(s.db.WriteTx().Put(s.table.Put(&session).If("'State' = ?", from)).Update(update), session, from).Run()
+	if isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+// releasePlate adds giving up the plate of session to tx when session is transitioned
+// out of the active state, unless another session holds the plate
+func (s *dynamoSessionStore) releasePlate(tx *dynamo.WriteTx, session types.Session, from string) *dynamo.WriteTx {
+	if from != sessionActive || session.State == sessionActive {
+		return tx
+	}
+	return tx.Delete(s.plates.Delete("Key", activePlateKey(session.LotID, session.Plate)).
+		If("attribute_not_exists('Key') OR 'SessionID' = ?", session.UUID))
+}
+
+// activePlateKey returns the Key of the ActivePlate of plate in the lot with the given
+// lotID, which is "default" for sessions without a lot
+func activePlateKey(lotID, plate string) string {
+	if lotID == "" {
+		lotID = "default"
+	}
+	return lotID + "#" + plate
+}
+
+// receiptCounterID returns the LotID of the ReceiptCounter of the lot with the given lotID
+func receiptCounterID(lotID string) string {
+	if lotID == "" {
+		return defaultReceiptCounter
+	}
+	return lotID
+}
+
+//-----------------------------------------------------------------------------
+// RESERVATIONS ---------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoReservationStore is a ReservationStore backed by a DynamoDB table. The
+// spaces held in each slot are counted in the slots table, which is updated in
+// the same transaction as the reservation
+type dynamoReservationStore struct {
+	db    *dynamo.DB
+	table dynamo.Table
+	slots dynamo.Table
+}
+
+// NewDynamoReservationStore returns a ReservationStore that reads and writes reservations
+// in table and counts the spaces they hold in slots. Both tables must belong to db
+func NewDynamoReservationStore(db *dynamo.DB, table, slots dynamo.Table) ReservationStore {
+	return &dynamoReservationStore{db: db, table: table, slots: slots}
+}
+
+func (s *dynamoReservationStore) All() ([]types.Reservation, error) {
+	var reservations []types.Reservation
+	err := s.table.Scan().Consistent(true).All(&reservations)
+	return reservations, err
+}
+
+func (s *dynamoReservationStore) Get(uuid string) (types.Reservation, error) {
+	var reservation types.Reservation
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&reservation)
+	if err == dynamo.ErrNotFound {
+		return reservation, ErrNotFound
+	}
+	return reservation, err
+}
+
+func (s *dynamoReservationStore) Write(reservation types.Reservation, version int, hold map[string]int, release []string) error {
+	put := s.table.Put(&reservation)
+	if version == 0 {
+		put = put.If("attribute_not_exists('UUID')")
+	} else {
+		put = put.If("'Version' = ?", version)
+	}
+
+	tx := s.db.WriteTx().Put(put)
+	held := make([]string, 0, len(hold))
+	for slot := range hold {
+		held = append(held, slot)
+	}
+	sort.Strings(held)
+	for _, slot := range held {
+		tx = tx.Update(s.slots.Update("Slot", slot).Add("Count", 1).
+			If("attribute_not_exists('Count') OR 'Count' < ?", hold[slot]))
+	}
+	for _, slot := range release {
+		tx = tx.Update(s.slots.Update("Slot", slot).Add("Count", -1))
+	}
+
+	err := tx.Run()
+	if isConditionFailed(err) {
+		current, err := s.Get(reservation.UUID)
+		if err == ErrNotFound && version == 0 {
+			return ErrLimitReached
+		}
+		if err != nil || current.Version != version {
+			return ErrConflict
+		}
+		return ErrLimitReached
+	}
+	return err
+}
+
+//-----------------------------------------------------------------------------
+// PERMITS --------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoPermitStore is a PermitStore backed by a DynamoDB table
+type dynamoPermitStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoPermitStore returns a PermitStore that reads and writes permits in table
+func NewDynamoPermitStore(table dynamo.Table) PermitStore {
+	return &dynamoPermitStore{table: table}
+}
+
+func (s *dynamoPermitStore) All() ([]types.Permit, error) {
+	var permits []types.Permit
+	err := s.table.Scan().Consistent(true).All(&permits)
+	return permits, err
+}
+
+func (s *dynamoPermitStore) Get(uuid string) (types.Permit, error) {
+	var permit types.Permit
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&permit)
+	if err == dynamo.ErrNotFound {
+		return permit, ErrNotFound
+	}
+	return permit, err
+}
+
+func (s *dynamoPermitStore) Put(permit types.Permit) error {
+	return s.table.Put(&permit).Run()
+}
+
+func (s *dynamoPermitStore) Delete(uuid string) error {
+	return s.table.Delete("UUID", uuid).Run()
+}
+
+//-----------------------------------------------------------------------------
+// MERCHANTS ------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoMerchantStore is a MerchantStore backed by a DynamoDB table
+type dynamoMerchantStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoMerchantStore returns a MerchantStore that reads and writes merchants in table
+func NewDynamoMerchantStore(table dynamo.Table) MerchantStore {
+	return &dynamoMerchantStore{table: table}
+}
+
+func (s *dynamoMerchantStore) All() ([]types.Merchant, error) {
+	var merchants []types.Merchant
+	err := s.table.Scan().Consistent(true).All(&merchants)
+	return merchants, err
+}
+
+func (s *dynamoMerchantStore) Get(uuid string) (types.Merchant, error) {
+	var merchant types.Merchant
+	err := s.table.Get("UUID", uuid).Consistent(true).One(&merchant)
+	if err == dynamo.ErrNotFound {
+		return merchant, ErrNotFound
+	}
+	return merchant, err
+}
+
+func (s *dynamoMerchantStore) Put(merchant types.Merchant) error {
+	return s.table.Put(&merchant).Run()
+}
+
+func (s *dynamoMerchantStore) Delete(uuid string) error {
+	return s.table.Delete("UUID", uuid).Run()
+}
+
+//-----------------------------------------------------------------------------
+// VALIDATIONS ----------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoValidationStore is a ValidationStore backed by a DynamoDB table. What each
+// merchant has spent in a month is kept in the spend table, which is updated in the
+// same transaction as the validation that is applied
+type dynamoValidationStore struct {
+	db    *dynamo.DB
+	table dynamo.Table
+	spend dynamo.Table
+}
+
+// NewDynamoValidationStore returns a ValidationStore that reads and writes validations in
+// table and adds up what they were worth to each merchant in spend. Both tables must belong to db
+func NewDynamoValidationStore(db *dynamo.DB, table, spend dynamo.Table) ValidationStore {
+	return &dynamoValidationStore{db: db, table: table, spend: spend}
+}
+
+func (s *dynamoValidationStore) All() ([]types.Validation, error) {
+	var validations []types.Validation
+	err := s.table.Scan().Consistent(true).All(&validations)
+	return validations, err
+}
+
+func (s *dynamoValidationStore) Get(code string) (types.Validation, error) {
+	var validation types.Validation
+	err := s.table.Get("Code", code).Consistent(true).One(&validation)
+	if err == dynamo.ErrNotFound {
+		return validation, ErrNotFound
+	}
+	return validation, err
+}
+
+func (s *dynamoValidationStore) Create(validation types.Validation) error {
+	err := s.table.Put(&validation).If("attribute_not_exists('Code')").Run()
+	if isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoValidationStore) Transition(validation types.Validation, from string) error {
+	err := s.table.Put(&validation).If("'State' = ?", from).Run()
+	if isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *dynamoValidationStore) Spent(merchantID, month string) (int, error) {
+	var spend types.MerchantSpend
+	err := s.spend.Get("Key", merchantSpendKey(merchantID, month)).Consistent(true).One(&spend)
+	if err == dynamo.ErrNotFound {
+		return 0, nil
+	}
+	return spend.Spent, err
+}
+
+func (s *dynamoValidationStore) Apply(validation types.Validation, from string, budget int) error {
+	tx := s.db.WriteTx().Put(s.table.Put(&validation).If("'State' = ?", from))
+	if validation.Worth > 0 {
+		update := s.spend.Update("Key", merchantSpendKey(validation.MerchantID, validation.Month)).Add("Spent", validation.Worth)
+		if budget > 0 {
+			update = update.If("attribute_not_exists('Spent') OR 'Spent' <= ?", budget-validation.Worth)
+		}
+		tx = tx.Update(update)
+	}
+
+	err := tx.Run()
+	if isConditionFailed(err) {
+		current, err := s.Get(validation.Code)
+		if err != nil || current.State != from {
+			return ErrConflict
+		}
+		return ErrLimitReached
+	}
+	return err
+}
+
+func (s *dynamoValidationStore) Unapply(validation, applied types.Validation) error {
+	tx := s.db.WriteTx().Put(s.table.Put(&validation).If("'State' = ?", applied.State))
+	if applied.Worth > 0 {
+		tx = tx.Update(s.spend.Update("Key", merchantSpendKey(applied.MerchantID, applied.Month)).Add("Spent", -applied.Worth))
+	}
+
+	err := tx.Run()
+	if isConditionFailed(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+// merchantSpendKey returns the Key of the MerchantSpend of the merchant with the given merchantID in month
+func merchantSpendKey(merchantID, month string) string {
+	return merchantID + "#" + month
+}
+
+//-----------------------------------------------------------------------------
+// ROUTE METRICS --------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// dynamoRouteMetricsStore is a RouteMetricsStore backed by a DynamoDB table
+type dynamoRouteMetricsStore struct {
+	table dynamo.Table
+}
+
+// NewDynamoRouteMetricsStore returns a RouteMetricsStore that reads and writes metrics in table
+func NewDynamoRouteMetricsStore(table dynamo.Table) RouteMetricsStore {
+	return &dynamoRouteMetricsStore{table: table}
+}
+
+func (s *dynamoRouteMetricsStore) All() ([]types.RouteMetrics, error) {
+	var metrics []types.RouteMetrics
+	err := s.table.Scan().All(&metrics)
+	return metrics, err
+}
+
+func (s *dynamoRouteMetricsStore) Put(metrics types.RouteMetrics) error {
+	return s.table.Put(&metrics).Run()
+}
@@ -0,0 +1,827 @@
+package store
+
+import (
+	"charlie-parker/pkg/types"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// memoryTable is a concurrency-safe, in-process stand-in for a DynamoDB table
+// with a string hash key. Items are kept in their marshalled dynamo form so
+// that every read hands back a fresh copy and field tags (omitempty, renames)
+// behave exactly as they would against a real table
+type memoryTable struct {
+	mu      sync.RWMutex
+	hashKey string
+	keys    []string
+	items   map[string]map[string]*dynamodb.AttributeValue
+}
+
+// newMemoryTable creates an empty memoryTable keyed by hashKey
+func newMemoryTable(hashKey string) *memoryTable {
+	return &memoryTable{
+		hashKey: hashKey,
+		items:   make(map[string]map[string]*dynamodb.AttributeValue),
+	}
+}
+
+// all unmarshals every item, in insertion order, into out (a pointer to a slice)
+func (t *memoryTable) all(out interface{}) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.allLocked(out)
+}
+
+func (t *memoryTable) allLocked(out interface{}) error {
+	slice := reflect.ValueOf(out).Elem()
+	for _, key := range t.keys {
+		elem := reflect.New(slice.Type().Elem())
+		if err := dynamo.UnmarshalItem(t.items[key], elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+	return nil
+}
+
+// get unmarshals the item with the given hash key into out, or returns ErrNotFound
+func (t *memoryTable) get(key string, out interface{}) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	item, exists := t.items[key]
+	if !exists {
+		return ErrNotFound
+	}
+	return dynamo.UnmarshalItem(item, out)
+}
+
+// put creates or replaces item
+func (t *memoryTable) put(item interface{}) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.putLocked(item)
+}
+
+func (t *memoryTable) putLocked(item interface{}) error {
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return err
+	}
+
+	hash, ok := av[t.hashKey]
+	if !ok || hash.S == nil || *hash.S == "" {
+		return fmt.Errorf("item is missing hash key %s", t.hashKey)
+	}
+
+	key := *hash.S
+	if _, exists := t.items[key]; !exists {
+		t.keys = append(t.keys, key)
+	}
+	t.items[key] = av
+	return nil
+}
+
+// build returns a new table with the same hash key that holds only items.
+// It errors if any item cannot be stored
+func (t *memoryTable) build(items ...interface{}) (*memoryTable, error) {
+	next := newMemoryTable(t.hashKey)
+	for _, item := range items {
+		if err := next.putLocked(item); err != nil {
+			return nil, err
+		}
+	}
+	return next, nil
+}
+
+// swapLocked swaps every item for the items in next
+func (t *memoryTable) swapLocked(next *memoryTable) {
+	t.keys, t.items = next.keys, next.items
+}
+
+// delete removes the items with the given hash keys, ignoring keys that do not exist
+func (t *memoryTable) delete(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deleteLocked(keys...)
+}
+
+func (t *memoryTable) deleteLocked(keys ...string) {
+	for _, key := range keys {
+		if _, exists := t.items[key]; !exists {
+			continue
+		}
+		delete(t.items, key)
+		for i, k := range t.keys {
+			if k == key {
+				t.keys = append(t.keys[:i], t.keys[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// RATES ----------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryRateStore is a RateStore that keeps rates in process memory. The
+// revision is guarded by the table's lock
+type memoryRateStore struct {
+	table    *memoryTable
+	revision int
+}
+
+// NewMemoryRateStore returns an empty RateStore that keeps rates in process memory
+func NewMemoryRateStore() RateStore {
+	return &memoryRateStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryRateStore) All() ([]types.Rate, error) {
+	var rates []types.Rate
+	err := s.table.all(&rates)
+	return rates, err
+}
+
+func (s *memoryRateStore) Get(uuid string) (types.Rate, error) {
+	var rate types.Rate
+	err := s.table.get(uuid, &rate)
+	return rate, err
+}
+
+func (s *memoryRateStore) Revision() (int, error) {
+	s.table.mu.RLock()
+	defer s.table.mu.RUnlock()
+	return s.revision, nil
+}
+
+func (s *memoryRateStore) Create(rate types.Rate, revision int) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if revision != s.revision {
+		return ErrConflict
+	}
+	if _, exists := s.table.items[rate.UUID]; exists {
+		return fmt.Errorf("rate %s already exists", rate.UUID)
+	}
+
+	if err := s.table.putLocked(rate); err != nil {
+		return err
+	}
+	s.revision++
+	return nil
+}
+
+func (s *memoryRateStore) Update(rate types.Rate, version, revision int) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if err := s.checkVersionLocked(rate.UUID, version); err != nil {
+		return err
+	}
+	if revision != s.revision {
+		return ErrConflict
+	}
+
+	if err := s.table.putLocked(rate); err != nil {
+		return err
+	}
+	s.revision++
+	return nil
+}
+
+func (s *memoryRateStore) Delete(uuid string, version int) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if err := s.checkVersionLocked(uuid, version); err != nil {
+		return err
+	}
+
+	s.table.deleteLocked(uuid)
+	s.revision++
+	return nil
+}
+
+func (s *memoryRateStore) Replace(rates []types.Rate) error {
+	items := make([]interface{}, len(rates))
+	for i, rate := range rates {
+		items[i] = rate
+	}
+	next, err := s.table.build(items...)
+	if err != nil {
+		return err
+	}
+
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	s.table.swapLocked(next)
+	s.revision++
+	return nil
+}
+
+// checkVersionLocked errors if the rate with the given uuid does not exist or is not at version
+func (s *memoryRateStore) checkVersionLocked(uuid string, version int) error {
+	item, exists := s.table.items[uuid]
+	if !exists {
+		return ErrNotFound
+	}
+
+	var stored types.Rate
+	if err := dynamo.UnmarshalItem(item, &stored); err != nil {
+		return err
+	}
+	if stored.Version != version {
+		return &VersionConflictError{UUID: uuid, Current: stored.Version}
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// CALENDARS ------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryCalendarStore is a CalendarStore that keeps calendars in process memory
+type memoryCalendarStore struct {
+	table *memoryTable
+}
+
+// NewMemoryCalendarStore returns an empty CalendarStore that keeps calendars in process memory
+func NewMemoryCalendarStore() CalendarStore {
+	return &memoryCalendarStore{table: newMemoryTable("Name")}
+}
+
+func (s *memoryCalendarStore) All() ([]types.Calendar, error) {
+	var calendars []types.Calendar
+	err := s.table.all(&calendars)
+	return calendars, err
+}
+
+func (s *memoryCalendarStore) Get(name string) (types.Calendar, error) {
+	var calendar types.Calendar
+	err := s.table.get(name, &calendar)
+	return calendar, err
+}
+
+func (s *memoryCalendarStore) Put(calendar types.Calendar) error {
+	return s.table.put(calendar)
+}
+
+func (s *memoryCalendarStore) Delete(name string) error {
+	s.table.delete(name)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// LOTS -----------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryLotStore is a LotStore that keeps lots in process memory
+type memoryLotStore struct {
+	table *memoryTable
+}
+
+// NewMemoryLotStore returns an empty LotStore that keeps lots in process memory
+func NewMemoryLotStore() LotStore {
+	return &memoryLotStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryLotStore) All() ([]types.Lot, error) {
+	var lots []types.Lot
+	err := s.table.all(&lots)
+	return lots, err
+}
+
+func (s *memoryLotStore) Get(uuid string) (types.Lot, error) {
+	var lot types.Lot
+	err := s.table.get(uuid, &lot)
+	return lot, err
+}
+
+func (s *memoryLotStore) Put(lot types.Lot) error {
+	return s.table.put(lot)
+}
+
+func (s *memoryLotStore) Delete(uuid string) error {
+	s.table.delete(uuid)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// PRODUCTS -------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryProductStore is a ProductStore that keeps products in process memory
+type memoryProductStore struct {
+	table *memoryTable
+}
+
+// NewMemoryProductStore returns an empty ProductStore that keeps products in process memory
+func NewMemoryProductStore() ProductStore {
+	return &memoryProductStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryProductStore) All() ([]types.Product, error) {
+	var products []types.Product
+	err := s.table.all(&products)
+	return products, err
+}
+
+func (s *memoryProductStore) Get(uuid string) (types.Product, error) {
+	var product types.Product
+	err := s.table.get(uuid, &product)
+	return product, err
+}
+
+func (s *memoryProductStore) Put(product types.Product) error {
+	return s.table.put(product)
+}
+
+func (s *memoryProductStore) Delete(uuid string) error {
+	s.table.delete(uuid)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// PROMOS ---------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryPromoStore is a PromoStore that keeps promos in process memory
+type memoryPromoStore struct {
+	table *memoryTable
+}
+
+// NewMemoryPromoStore returns an empty PromoStore that keeps promos in process memory
+func NewMemoryPromoStore() PromoStore {
+	return &memoryPromoStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryPromoStore) All() ([]types.Promo, error) {
+	var promos []types.Promo
+	err := s.table.all(&promos)
+	return promos, err
+}
+
+func (s *memoryPromoStore) Get(uuid string) (types.Promo, error) {
+	var promo types.Promo
+	err := s.table.get(uuid, &promo)
+	return promo, err
+}
+
+func (s *memoryPromoStore) Put(promo types.Promo) error {
+	return s.table.put(promo)
+}
+
+func (s *memoryPromoStore) Update(promo types.Promo, uses int) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	item, exists := s.table.items[promo.UUID]
+	if !exists {
+		return ErrNotFound
+	}
+
+	var stored types.Promo
+	if err := dynamo.UnmarshalItem(item, &stored); err != nil {
+		return err
+	}
+	if stored.Uses != uses {
+		return ErrConflict
+	}
+	return s.table.putLocked(promo)
+}
+
+func (s *memoryPromoStore) Delete(uuid string) error {
+	s.table.delete(uuid)
+	return nil
+}
+
+func (s *memoryPromoStore) Redeem(uuid string) (types.Promo, error) {
+	var promo types.Promo
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	item, exists := s.table.items[uuid]
+	if !exists {
+		return promo, ErrNotFound
+	}
+	if err := dynamo.UnmarshalItem(item, &promo); err != nil {
+		return promo, err
+	}
+	if promo.MaxUses > 0 && promo.Uses >= promo.MaxUses {
+		return promo, ErrLimitReached
+	}
+
+	promo.Uses++
+	return promo, s.table.putLocked(promo)
+}
+
+func (s *memoryPromoStore) Unredeem(uuid string) error {
+	var promo types.Promo
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	item, exists := s.table.items[uuid]
+	if !exists {
+		return ErrNotFound
+	}
+	if err := dynamo.UnmarshalItem(item, &promo); err != nil {
+		return err
+	}
+	if promo.Uses <= 0 {
+		return ErrConflict
+	}
+
+	promo.Uses--
+	return s.table.putLocked(promo)
+}
+
+//-----------------------------------------------------------------------------
+// OCCUPANCIES ----------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryOccupancyStore is an OccupancyStore that keeps lot occupancies in process memory
+type memoryOccupancyStore struct {
+	table *memoryTable
+}
+
+// NewMemoryOccupancyStore returns an empty OccupancyStore that keeps lot occupancies in process memory
+func NewMemoryOccupancyStore() OccupancyStore {
+	return &memoryOccupancyStore{table: newMemoryTable("LotID")}
+}
+
+func (s *memoryOccupancyStore) All() ([]types.Occupancy, error) {
+	var occupancies []types.Occupancy
+	err := s.table.all(&occupancies)
+	return occupancies, err
+}
+
+func (s *memoryOccupancyStore) Get(lotID string) (types.Occupancy, error) {
+	var occupancy types.Occupancy
+	err := s.table.get(lotID, &occupancy)
+	return occupancy, err
+}
+
+func (s *memoryOccupancyStore) Put(occupancy types.Occupancy) error {
+	return s.table.put(occupancy)
+}
+
+func (s *memoryOccupancyStore) Delete(lotID string) error {
+	s.table.delete(lotID)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// SESSIONS -------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memorySessionStore is a SessionStore that keeps sessions in process memory. The last
+// receipt issued in each lot and the plates of active sessions are guarded by the table's lock
+type memorySessionStore struct {
+	table    *memoryTable
+	receipts map[string]int
+	plates   map[string]string
+}
+
+// NewMemorySessionStore returns an empty SessionStore that keeps sessions in process memory
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{table: newMemoryTable("UUID"), receipts: make(map[string]int), plates: make(map[string]string)}
+}
+
+func (s *memorySessionStore) All() ([]types.Session, error) {
+	var sessions []types.Session
+	err := s.table.all(&sessions)
+	return sessions, err
+}
+
+func (s *memorySessionStore) Get(uuid string) (types.Session, error) {
+	var session types.Session
+	err := s.table.get(uuid, &session)
+	return session, err
+}
+
+func (s *memorySessionStore) Create(session types.Session) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if _, exists := s.table.items[session.UUID]; exists {
+		return fmt.Errorf("session %s already exists", session.UUID)
+	}
+
+	key := activePlateKey(session.LotID, session.Plate)
+	if _, held := s.plates[key]; held && session.State == sessionActive {
+		return ErrConflict
+	}
+	if err := s.table.putLocked(session); err != nil {
+		return err
+	}
+	if session.State == sessionActive {
+		s.plates[key] = session.UUID
+	}
+	return nil
+}
+
+func (s *memorySessionStore) Transition(session types.Session, from string) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	return s.transitionLocked(session, from)
+}
+
+func (s *memorySessionStore) LastReceipt(lotID string) (int, error) {
+	s.table.mu.RLock()
+	defer s.table.mu.RUnlock()
+	return s.receipts[lotID], nil
+}
+
+func (s *memorySessionStore) Issue(session types.Session, from string) error {
+	if session.Receipt == nil {
+		return fmt.Errorf("session %s has no receipt to issue", session.UUID)
+	}
+
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if s.receipts[session.LotID] != session.Receipt.Number-1 {
+		return ErrConflict
+	}
+	if err := s.transitionLocked(session, from); err != nil {
+		return err
+	}
+	s.receipts[session.LotID] = session.Receipt.Number
+	return nil
+}
+
+func (s *memorySessionStore) transitionLocked(session types.Session, from string) error {
+	item, exists := s.table.items[session.UUID]
+	if !exists {
+		return ErrNotFound
+	}
+	var current types.Session
+	if err := dynamo.UnmarshalItem(item, &current); err != nil {
+		return err
+	}
+	if current.State != from {
+		return ErrConflict
+	}
+	if err := s.table.putLocked(session); err != nil {
+		return err
+	}
+
+	key := activePlateKey(session.LotID, session.Plate)
+	if from == sessionActive && session.State != sessionActive && s.plates[key] == session.UUID {
+		delete(s.plates, key)
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// RESERVATIONS ---------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryReservationStore is a ReservationStore that keeps reservations in process memory.
+// The spaces held in each slot are guarded by the table's lock
+type memoryReservationStore struct {
+	table *memoryTable
+	slots map[string]int
+}
+
+// NewMemoryReservationStore returns an empty ReservationStore that keeps reservations in process memory
+func NewMemoryReservationStore() ReservationStore {
+	return &memoryReservationStore{table: newMemoryTable("UUID"), slots: make(map[string]int)}
+}
+
+func (s *memoryReservationStore) All() ([]types.Reservation, error) {
+	var reservations []types.Reservation
+	err := s.table.all(&reservations)
+	return reservations, err
+}
+
+func (s *memoryReservationStore) Get(uuid string) (types.Reservation, error) {
+	var reservation types.Reservation
+	err := s.table.get(uuid, &reservation)
+	return reservation, err
+}
+
+func (s *memoryReservationStore) Write(reservation types.Reservation, version int, hold map[string]int, release []string) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	item, exists := s.table.items[reservation.UUID]
+	if exists != (version != 0) {
+		return ErrConflict
+	}
+	if exists {
+		var current types.Reservation
+		if err := dynamo.UnmarshalItem(item, &current); err != nil {
+			return err
+		}
+		if current.Version != version {
+			return ErrConflict
+		}
+	}
+	for slot, capacity := range hold {
+		if s.slots[slot] >= capacity {
+			return ErrLimitReached
+		}
+	}
+
+	if err := s.table.putLocked(reservation); err != nil {
+		return err
+	}
+	for slot := range hold {
+		s.slots[slot]++
+	}
+	for _, slot := range release {
+		s.slots[slot]--
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// PERMITS --------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryPermitStore is a PermitStore that keeps permits in process memory
+type memoryPermitStore struct {
+	table *memoryTable
+}
+
+// NewMemoryPermitStore returns an empty PermitStore that keeps permits in process memory
+func NewMemoryPermitStore() PermitStore {
+	return &memoryPermitStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryPermitStore) All() ([]types.Permit, error) {
+	var permits []types.Permit
+	err := s.table.all(&permits)
+	return permits, err
+}
+
+func (s *memoryPermitStore) Get(uuid string) (types.Permit, error) {
+	var permit types.Permit
+	err := s.table.get(uuid, &permit)
+	return permit, err
+}
+
+func (s *memoryPermitStore) Put(permit types.Permit) error {
+	return s.table.put(permit)
+}
+
+func (s *memoryPermitStore) Delete(uuid string) error {
+	s.table.delete(uuid)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// MERCHANTS ------------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryMerchantStore is a MerchantStore that keeps merchants in process memory
+type memoryMerchantStore struct {
+	table *memoryTable
+}
+
+// NewMemoryMerchantStore returns an empty MerchantStore that keeps merchants in process memory
+func NewMemoryMerchantStore() MerchantStore {
+	return &memoryMerchantStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryMerchantStore) All() ([]types.Merchant, error) {
+	var merchants []types.Merchant
+	err := s.table.all(&merchants)
+	return merchants, err
+}
+
+func (s *memoryMerchantStore) Get(uuid string) (types.Merchant, error) {
+	var merchant types.Merchant
+	err := s.table.get(uuid, &merchant)
+	return merchant, err
+}
+
+func (s *memoryMerchantStore) Put(merchant types.Merchant) error {
+	return s.table.put(merchant)
+}
+
+func (s *memoryMerchantStore) Delete(uuid string) error {
+	s.table.delete(uuid)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// VALIDATIONS ----------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryValidationStore is a ValidationStore that keeps validations in process memory.
+// What each merchant has spent in a month is guarded by the table's lock
+type memoryValidationStore struct {
+	table *memoryTable
+	spend map[string]int
+}
+
+// NewMemoryValidationStore returns an empty ValidationStore that keeps validations in process memory
+func NewMemoryValidationStore() ValidationStore {
+	return &memoryValidationStore{table: newMemoryTable("Code"), spend: make(map[string]int)}
+}
+
+func (s *memoryValidationStore) All() ([]types.Validation, error) {
+	var validations []types.Validation
+	err := s.table.all(&validations)
+	return validations, err
+}
+
+func (s *memoryValidationStore) Get(code string) (types.Validation, error) {
+	var validation types.Validation
+	err := s.table.get(code, &validation)
+	return validation, err
+}
+
+func (s *memoryValidationStore) Create(validation types.Validation) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if _, exists := s.table.items[validation.Code]; exists {
+		return ErrConflict
+	}
+	return s.table.putLocked(validation)
+}
+
+func (s *memoryValidationStore) Transition(validation types.Validation, from string) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	return s.transitionLocked(validation, from)
+}
+
+func (s *memoryValidationStore) Spent(merchantID, month string) (int, error) {
+	s.table.mu.RLock()
+	defer s.table.mu.RUnlock()
+	return s.spend[merchantSpendKey(merchantID, month)], nil
+}
+
+func (s *memoryValidationStore) Apply(validation types.Validation, from string, budget int) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	key := merchantSpendKey(validation.MerchantID, validation.Month)
+	if validation.Worth > 0 && budget > 0 && s.spend[key]+validation.Worth > budget {
+		if err := s.checkStateLocked(validation.Code, from); err != nil {
+			return err
+		}
+		return ErrLimitReached
+	}
+	if err := s.transitionLocked(validation, from); err != nil {
+		return err
+	}
+	s.spend[key] += validation.Worth
+	return nil
+}
+
+func (s *memoryValidationStore) Unapply(validation, applied types.Validation) error {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if err := s.transitionLocked(validation, applied.State); err != nil {
+		return err
+	}
+	s.spend[merchantSpendKey(applied.MerchantID, applied.Month)] -= applied.Worth
+	return nil
+}
+
+func (s *memoryValidationStore) transitionLocked(validation types.Validation, from string) error {
+	if err := s.checkStateLocked(validation.Code, from); err != nil {
+		return err
+	}
+	return s.table.putLocked(validation)
+}
+
+// checkStateLocked returns ErrNotFound or ErrConflict unless the stored validation with
+// the given code is in the state from
+func (s *memoryValidationStore) checkStateLocked(code, from string) error {
+	item, exists := s.table.items[code]
+	if !exists {
+		return ErrNotFound
+	}
+	var current types.Validation
+	if err := dynamo.UnmarshalItem(item, &current); err != nil {
+		return err
+	}
+	if current.State != from {
+		return ErrConflict
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// ROUTE METRICS --------------------------------------------------------------
+//-----------------------------------------------------------------------------
+
+// memoryRouteMetricsStore is a RouteMetricsStore that keeps metrics in process memory
+type memoryRouteMetricsStore struct {
+	table *memoryTable
+}
+
+// NewMemoryRouteMetricsStore returns an empty RouteMetricsStore that keeps metrics in process memory
+func NewMemoryRouteMetricsStore() RouteMetricsStore {
+	return &memoryRouteMetricsStore{table: newMemoryTable("UUID")}
+}
+
+func (s *memoryRouteMetricsStore) All() ([]types.RouteMetrics, error) {
+	var metrics []types.RouteMetrics
+	err := s.table.all(&metrics)
+	return metrics, err
+}
+
+func (s *memoryRouteMetricsStore) Put(metrics types.RouteMetrics) error {
+	return s.table.put(metrics)
+}
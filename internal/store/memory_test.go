@@ -0,0 +1,640 @@
+package store
+
+import (
+	"charlie-parker/pkg/types"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func Test_memoryRateStore_Replace(t *testing.T) {
+	old := []types.Rate{
+		{UUID: "0000001", Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500},
+		{UUID: "0000002", Days: "tues", Times: "0900-1200", TZ: "America/Chicago", Price: 1500},
+	}
+	tests := []struct {
+		name    string
+		rates   []types.Rate
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "Simple Passing Replace",
+			rates: []types.Rate{{UUID: "0000003", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800}},
+			want:  1,
+		},
+		{
+			name: "Missing Hash Key Leaves Old Rates",
+			rates: []types.Rate{
+				{UUID: "0000003", Days: "fri", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+				{Days: "sat", Times: "1600-1800", TZ: "America/Chicago", Price: 1800},
+			},
+			want:    len(old),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryRateStore()
+			if err := s.Replace(old); err != nil {
+				t.Fatalf("Replace() setup error = %v", err)
+			}
+
+			if err := s.Replace(test.rates); (err != nil) != test.wantErr {
+				t.Errorf("Replace() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if got, _ := s.All(); len(got) != test.want {
+				t.Errorf("Replace() left %d rates, want %d", len(got), test.want)
+			}
+		})
+	}
+}
+
+func Test_memoryRateStore_ReplaceIsNeverSeenHalfDone(t *testing.T) {
+	setA := []types.Rate{{UUID: "a1"}, {UUID: "a2"}, {UUID: "a3"}}
+	setB := []types.Rate{{UUID: "b1"}, {UUID: "b2"}}
+	s := NewMemoryRateStore()
+	s.Replace(setA)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			if i%2 == 0 {
+				s.Replace(setB)
+			} else {
+				s.Replace(setA)
+			}
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		rates, err := s.All()
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		prefix := rates[0].UUID[0]
+		for _, rate := range rates {
+			if rate.UUID[0] != prefix {
+				t.Fatalf("All() returned a mix of rate sets: %v", rates)
+			}
+		}
+	}
+	wg.Wait()
+}
+
+func Test_memoryRateStore_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int
+		revision    int
+		wantCurrent int
+		wantErr     error
+	}{
+		{
+			name:     "Simple Passing Update",
+			version:  1,
+			revision: 1,
+		},
+		{
+			name:        "Stale Version Error",
+			version:     2,
+			revision:    1,
+			wantCurrent: 1,
+			wantErr:     ErrConflict,
+		},
+		{
+			name:     "Stale Revision Error",
+			version:  1,
+			revision: 0,
+			wantErr:  ErrConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryRateStore()
+			rate := types.Rate{UUID: "0000001", Days: "mon", Times: "0900-1200", TZ: "America/Chicago", Price: 1500, Version: 1}
+			if err := s.Create(rate, 0); err != nil {
+				t.Fatalf("Create() setup error = %v", err)
+			}
+
+			rate.Price, rate.Version = 2000, test.version+1
+			err := s.Update(rate, test.version, test.revision)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Update() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			var conflict *VersionConflictError
+			if errors.As(err, &conflict) != (test.wantCurrent != 0) || (conflict != nil && conflict.Current != test.wantCurrent) {
+				t.Errorf("Update() error = %v, want current version %d", err, test.wantCurrent)
+			}
+		})
+	}
+}
+
+func Test_memoryPromoStore_Redeem(t *testing.T) {
+	tests := []struct {
+		name     string
+		uuid     string
+		maxUses  int
+		wantUses int
+		wantErr  error
+	}{
+		{
+			name:     "Unlimited Passing Redeem",
+			uuid:     "0000001",
+			wantUses: 3,
+		},
+		{
+			name:     "Last Use Passing Redeem",
+			uuid:     "0000001",
+			maxUses:  3,
+			wantUses: 3,
+		},
+		{
+			name:     "Used Up Error",
+			uuid:     "0000001",
+			maxUses:  2,
+			wantUses: 2,
+			wantErr:  ErrLimitReached,
+		},
+		{
+			name:    "Missing Promo Error",
+			uuid:    "missing",
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryPromoStore()
+			promo := types.Promo{UUID: "0000001", Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago", MaxUses: test.maxUses, Uses: 2}
+			if err := s.Put(promo); err != nil {
+				t.Fatalf("Put() setup error = %v", err)
+			}
+
+			got, err := s.Redeem(test.uuid)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Redeem() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := s.Get(promo.UUID); got.Uses != test.wantUses || (test.wantErr == nil && stored.Uses != test.wantUses) {
+				t.Errorf("Redeem() uses = %d, stored %d, want %d", got.Uses, stored.Uses, test.wantUses)
+			}
+		})
+	}
+}
+
+func Test_memoryPromoStore_Unredeem(t *testing.T) {
+	tests := []struct {
+		name     string
+		uuid     string
+		uses     int
+		wantUses int
+		wantErr  error
+	}{
+		{
+			name:     "Simple Passing Unredeem",
+			uuid:     "0000001",
+			uses:     2,
+			wantUses: 1,
+		},
+		{
+			name:    "Unused Promo Error",
+			uuid:    "0000001",
+			wantErr: ErrConflict,
+		},
+		{
+			name:     "Missing Promo Error",
+			uuid:     "missing",
+			uses:     2,
+			wantUses: 2,
+			wantErr:  ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryPromoStore()
+			promo := types.Promo{UUID: "0000001", Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago", Uses: test.uses}
+			if err := s.Put(promo); err != nil {
+				t.Fatalf("Put() setup error = %v", err)
+			}
+
+			if err := s.Unredeem(test.uuid); !errors.Is(err, test.wantErr) {
+				t.Errorf("Unredeem() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := s.Get(promo.UUID); stored.Uses != test.wantUses {
+				t.Errorf("Unredeem() stored uses %d, want %d", stored.Uses, test.wantUses)
+			}
+		})
+	}
+}
+
+func Test_memoryPromoStore_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		uuid     string
+		uses     int
+		wantCode string
+		wantErr  error
+	}{
+		{
+			name:     "Simple Passing Update",
+			uuid:     "0000001",
+			uses:     2,
+			wantCode: "FIRST10",
+		},
+		{
+			name:     "Redeemed Since Read Error",
+			uuid:     "0000001",
+			uses:     1,
+			wantCode: "FIRST5",
+			wantErr:  ErrConflict,
+		},
+		{
+			name:    "Missing Promo Error",
+			uuid:    "missing",
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryPromoStore()
+			promo := types.Promo{UUID: "0000001", Code: "FIRST5", Type: "amount", Value: 500, TZ: "America/Chicago", Uses: 2}
+			if err := s.Put(promo); err != nil {
+				t.Fatalf("Put() setup error = %v", err)
+			}
+
+			updated := promo
+			updated.UUID, updated.Code, updated.Value, updated.Uses = test.uuid, "FIRST10", 1000, test.uses
+			if err := s.Update(updated, test.uses); !errors.Is(err, test.wantErr) {
+				t.Errorf("Update() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if stored, _ := s.Get(promo.UUID); test.wantErr != ErrNotFound && (stored.Code != test.wantCode || stored.Uses != 2) {
+				t.Errorf("Update() stored %v, want code %s with 2 uses", stored, test.wantCode)
+			}
+		})
+	}
+}
+
+func Test_memorySessionStore_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		lotID   string
+		ended   string
+		wantErr error
+	}{
+		{
+			name:    "Active Plate Error",
+			wantErr: ErrConflict,
+		},
+		{
+			name:  "Other Lot Passing Create",
+			lotID: "garage",
+		},
+		{
+			name:  "Closed Plate Passing Create",
+			ended: "closed",
+		},
+		{
+			name:  "Voided Plate Passing Create",
+			ended: "voided",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemorySessionStore()
+			first := types.Session{UUID: "0000001", Plate: "ABC123", State: "active", EntryTime: "2017-01-06T17:00:00-06:00"}
+			if err := s.Create(first); err != nil {
+				t.Fatalf("Create() setup error = %v", err)
+			}
+			if test.ended != "" {
+				ended := first
+				ended.State = test.ended
+				if err := s.Transition(ended, "active"); err != nil {
+					t.Fatalf("Transition() setup error = %v", err)
+				}
+			}
+
+			second := types.Session{UUID: "0000002", LotID: test.lotID, Plate: "ABC123", State: "active", EntryTime: "2017-01-06T18:00:00-06:00"}
+			if err := s.Create(second); !errors.Is(err, test.wantErr) {
+				t.Errorf("Create() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if _, err := s.Get(second.UUID); (err == nil) != (test.wantErr == nil) {
+				t.Errorf("Create() stored = %v, wantErr %v", err == nil, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_memorySessionStore_Transition(t *testing.T) {
+	tests := []struct {
+		name    string
+		uuid    string
+		from    string
+		wantErr error
+	}{
+		{
+			name: "Simple Passing Transition",
+			uuid: "0000001",
+			from: "active",
+		},
+		{
+			name:    "Stale State Error",
+			uuid:    "0000001",
+			from:    "closed",
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "Missing Session Error",
+			uuid:    "missing",
+			from:    "active",
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemorySessionStore()
+			session := types.Session{UUID: "0000001", Plate: "ABC123", State: "active", EntryTime: "2017-01-06T17:00:00-06:00"}
+			if err := s.Create(session); err != nil {
+				t.Fatalf("Create() setup error = %v", err)
+			}
+
+			closed := session
+			closed.UUID, closed.State, closed.Fee = test.uuid, "closed", 1800
+			if err := s.Transition(closed, test.from); !errors.Is(err, test.wantErr) {
+				t.Errorf("Transition() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			want := closed
+			if test.wantErr != nil {
+				want = session
+			}
+			if stored, _ := s.Get(session.UUID); stored != want {
+				t.Errorf("Transition() stored = %v, want %v", stored, want)
+			}
+		})
+	}
+}
+
+func Test_memorySessionStore_Issue(t *testing.T) {
+	tests := []struct {
+		name     string
+		number   int
+		from     string
+		wantErr  error
+		wantLast int
+	}{
+		{
+			name:     "Simple Passing Issue",
+			number:   2,
+			from:     "active",
+			wantLast: 2,
+		},
+		{
+			name:     "Reused Number Error",
+			number:   1,
+			from:     "active",
+			wantErr:  ErrConflict,
+			wantLast: 1,
+		},
+		{
+			name:     "Stale State Error",
+			number:   2,
+			from:     "closed",
+			wantErr:  ErrConflict,
+			wantLast: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemorySessionStore()
+			for uuid, plate := range map[string]string{"0000001": "ABC123", "0000002": "XYZ789"} {
+				if err := s.Create(types.Session{UUID: uuid, LotID: "garage", Plate: plate, State: "active"}); err != nil {
+					t.Fatalf("Create() setup error = %v", err)
+				}
+			}
+			first := types.Session{UUID: "0000001", LotID: "garage", Plate: "ABC123", State: "closed", Fee: 1800, Receipt: &types.Receipt{Number: 1}}
+			if err := s.Issue(first, "active"); err != nil {
+				t.Fatalf("Issue() setup error = %v", err)
+			}
+
+			second := types.Session{UUID: "0000002", LotID: "garage", Plate: "XYZ789", State: "closed", Fee: 1800, Receipt: &types.Receipt{Number: test.number}}
+			if err := s.Issue(second, test.from); !errors.Is(err, test.wantErr) {
+				t.Errorf("Issue() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			if last, _ := s.LastReceipt("garage"); last != test.wantLast {
+				t.Errorf("Issue() left the last receipt at %d, want %d", last, test.wantLast)
+			}
+		})
+	}
+}
+
+func Test_memoryReservationStore_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		hold    map[string]int
+		release []string
+		wantErr error
+		// wantFreed is whether the 22:00 slot that booked held has a space again afterwards
+		wantFreed bool
+	}{
+		{
+			name:    "Simple Passing Write",
+			version: 1,
+			hold:    map[string]int{"garage#2031-01-03T23:00:00Z": 2},
+		},
+		{
+			name:      "Release Passing Write",
+			version:   1,
+			hold:      map[string]int{"garage#2031-01-03T21:00:00Z": 1},
+			release:   []string{"garage#2031-01-03T22:00:00Z"},
+			wantFreed: true,
+		},
+		{
+			name:    "Full Slot Error",
+			version: 1,
+			hold:    map[string]int{"garage#2031-01-03T23:00:00Z": 1},
+			wantErr: ErrLimitReached,
+		},
+		{
+			name:    "Stale Version Error",
+			version: 2,
+			wantErr: ErrConflict,
+		},
+		{
+			name:    "Existing Reservation Error",
+			version: 0,
+			wantErr: ErrConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryReservationStore()
+			booked := types.Reservation{UUID: "0000001", LotID: "garage", Plate: "ABC123", State: "booked", Version: 1}
+			if err := s.Write(booked, 0, map[string]int{"garage#2031-01-03T22:00:00Z": 1}, nil); err != nil {
+				t.Fatalf("Write() setup error = %v", err)
+			}
+			other := types.Reservation{UUID: "0000002", LotID: "garage", Plate: "XYZ789", State: "booked", Version: 1}
+			if err := s.Write(other, 0, map[string]int{"garage#2031-01-03T23:00:00Z": 1}, nil); err != nil {
+				t.Fatalf("Write() setup error = %v", err)
+			}
+
+			modified := booked
+			modified.Version = 2
+			if err := s.Write(modified, test.version, test.hold, test.release); !errors.Is(err, test.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			want := modified
+			if test.wantErr != nil {
+				want = booked
+			}
+			if stored, _ := s.Get(booked.UUID); stored != want {
+				t.Errorf("Write() stored = %v, want %v", stored, want)
+			}
+
+			next := types.Reservation{UUID: "0000003", LotID: "garage", Plate: "DEF456", State: "booked", Version: 1}
+			if err := s.Write(next, 0, map[string]int{"garage#2031-01-03T22:00:00Z": 1}, nil); (err == nil) != test.wantFreed {
+				t.Errorf("Write() left the held slot freed = %v, want %v", err == nil, test.wantFreed)
+			}
+		})
+	}
+}
+
+func Test_memoryValidationStore_Apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		worth     int
+		budget    int
+		wantSpent int
+		wantErr   error
+	}{
+		{
+			name:      "Unlimited Passing Apply",
+			from:      "redeemed",
+			worth:     800,
+			wantSpent: 1400,
+		},
+		{
+			name:      "Last Of Budget Passing Apply",
+			from:      "redeemed",
+			worth:     400,
+			budget:    1000,
+			wantSpent: 1000,
+		},
+		{
+			name:      "Over Budget Error",
+			from:      "redeemed",
+			worth:     500,
+			budget:    1000,
+			wantSpent: 600,
+			wantErr:   ErrLimitReached,
+		},
+		{
+			name:      "Changed State Error",
+			from:      "issued",
+			worth:     100,
+			wantSpent: 600,
+			wantErr:   ErrConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryValidationStore()
+			for _, code := range []string{"SPENT", "APPLIED"} {
+				if err := s.Create(types.Validation{Code: code, MerchantID: "cafe", Type: "amount", Value: 600, State: "redeemed"}); err != nil {
+					t.Fatalf("Create() setup error = %v", err)
+				}
+			}
+			if err := s.Apply(types.Validation{Code: "SPENT", MerchantID: "cafe", State: "applied", Worth: 600, Month: "2017-01"}, "redeemed", 0); err != nil {
+				t.Fatalf("Apply() setup error = %v", err)
+			}
+
+			applied := types.Validation{Code: "APPLIED", MerchantID: "cafe", State: "applied", Worth: test.worth, Month: "2017-01"}
+			err := s.Apply(applied, test.from, test.budget)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Apply() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			stored, _ := s.Get(applied.Code)
+			if spent, _ := s.Spent("cafe", "2017-01"); spent != test.wantSpent || (stored.State == "applied") != (test.wantErr == nil) {
+				t.Errorf("Apply() spent = %d, stored %v, want %d spent", spent, stored, test.wantSpent)
+			}
+		})
+	}
+}
+
+func Test_memoryValidationStore_Unapply(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     string
+		wantSpent int
+		wantErr   error
+	}{
+		{
+			name:      "Simple Passing Unapply",
+			state:     "applied",
+			wantSpent: 600,
+		},
+		{
+			name:      "Changed State Error",
+			state:     "redeemed",
+			wantSpent: 1400,
+			wantErr:   ErrConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewMemoryValidationStore()
+			for code, worth := range map[string]int{"SPENT": 600, "APPLIED": 800} {
+				if err := s.Create(types.Validation{Code: code, MerchantID: "cafe", Type: "amount", Value: worth, State: "redeemed"}); err != nil {
+					t.Fatalf("Create() setup error = %v", err)
+				}
+				if err := s.Apply(types.Validation{Code: code, MerchantID: "cafe", State: "applied", Worth: worth, Month: "2017-01"}, "redeemed", 0); err != nil {
+					t.Fatalf("Apply() setup error = %v", err)
+				}
+			}
+
+			redeemed := types.Validation{Code: "APPLIED", MerchantID: "cafe", Type: "amount", Value: 800, State: "redeemed"}
+			applied := types.Validation{Code: "APPLIED", MerchantID: "cafe", State: test.state, Worth: 800, Month: "2017-01"}
+			if err := s.Unapply(redeemed, applied); !errors.Is(err, test.wantErr) {
+				t.Errorf("Unapply() error = %v, wantErr %v", err, test.wantErr)
+				return
+			}
+
+			stored, _ := s.Get(applied.Code)
+			if spent, _ := s.Spent("cafe", "2017-01"); spent != test.wantSpent || (stored.State == "redeemed") != (test.wantErr == nil) {
+				t.Errorf("Unapply() spent = %d, stored %v, want %d spent", spent, stored, test.wantSpent)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"charlie-parker/pkg/types"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when the item being looked up does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a write is rejected because what it was
+// based on was changed by another write in the meantime
+var ErrConflict = errors.New("changed by another request, try again")
+
+// ErrLimitReached is returned when an item cannot be used again because it
+// has already been used as many times as it is allowed to be
+var ErrLimitReached = errors.New("usage limit reached")
+
+// VersionConflictError is returned when a rate is written or deleted
+// expecting a version that is no longer the stored version
+type VersionConflictError struct {
+	UUID    string
+	Current int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("rate %s is at version %d", e.UUID, e.Current)
+}
+
+// Unwrap lets errors.Is(err, ErrConflict) match a VersionConflictError
+func (e *VersionConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// RateStore persists and retrieves rates. Every write moves the stored rates to a new
+// revision, and writes that are validated against the other rates are conditioned on the
+// revision those rates were read at, so no write can land between a check and its write
+type RateStore interface {
+	// All returns every stored rate
+	All() ([]types.Rate, error)
+	// Get returns the rate with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Rate, error)
+	// Revision returns the current revision of the stored rates
+	Revision() (int, error)
+	// Create stores a new rate, or returns ErrConflict if the rates are no longer at revision
+	Create(rate types.Rate, revision int) error
+	// Update replaces the stored rate that is at version with rate. It returns a
+	// *VersionConflictError if the stored rate is at another version, or ErrConflict
+	// if the rates are no longer at revision
+	Update(rate types.Rate, version, revision int) error
+	// Delete removes the rate with the given UUID if it is at version, and otherwise
+	// returns a *VersionConflictError
+	Delete(uuid string, version int) error
+	// Replace swaps every stored rate for rates in one all-or-nothing operation;
+	// readers see either the old rates or the new ones, never a mix of both
+	Replace(rates []types.Rate) error
+}
+
+// CalendarStore persists and retrieves calendars
+type CalendarStore interface {
+	// All returns every stored calendar
+	All() ([]types.Calendar, error)
+	// Get returns the calendar with the given name, or ErrNotFound
+	Get(name string) (types.Calendar, error)
+	// Put creates or replaces a calendar
+	Put(calendar types.Calendar) error
+	// Delete removes the calendar with the given name
+	Delete(name string) error
+}
+
+// LotStore persists and retrieves lots
+type LotStore interface {
+	// All returns every stored lot
+	All() ([]types.Lot, error)
+	// Get returns the lot with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Lot, error)
+	// Put creates or replaces a lot
+	Put(lot types.Lot) error
+	// Delete removes the lot with the given UUID
+	Delete(uuid string) error
+}
+
+// ProductStore persists and retrieves products
+type ProductStore interface {
+	// All returns every stored product
+	All() ([]types.Product, error)
+	// Get returns the product with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Product, error)
+	// Put creates or replaces a product
+	Put(product types.Product) error
+	// Delete removes the product with the given UUID
+	Delete(uuid string) error
+}
+
+// PromoStore persists and retrieves promos
+type PromoStore interface {
+	// All returns every stored promo
+	All() ([]types.Promo, error)
+	// Get returns the promo with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Promo, error)
+	// Put creates or replaces a promo
+	Put(promo types.Promo) error
+	// Update replaces a promo if it has still been used uses times, so that a redemption
+	// made since it was read is not lost. It returns ErrNotFound, or ErrConflict if the
+	// promo has been redeemed since
+	Update(promo types.Promo, uses int) error
+	// Delete removes the promo with the given UUID
+	Delete(uuid string) error
+	// Redeem adds one to the uses of the promo with the given UUID in a single conditional
+	// write and returns the promo. It returns ErrNotFound, or ErrLimitReached if the promo
+	// has already been used MaxUses times
+	Redeem(uuid string) (types.Promo, error)
+	// Unredeem takes one off the uses of the promo with the given UUID in a single
+	// conditional write, giving back a use that Redeem recorded. It returns ErrNotFound,
+	// or ErrConflict if the promo has not been used
+	Unredeem(uuid string) error
+}
+
+// OccupancyStore persists and retrieves lot occupancies
+type OccupancyStore interface {
+	// All returns every stored lot occupancy
+	All() ([]types.Occupancy, error)
+	// Get returns the lot occupancy with the given LotID, or ErrNotFound
+	Get(lotID string) (types.Occupancy, error)
+	// Put creates or replaces a lot occupancy
+	Put(occupancy types.Occupancy) error
+	// Delete removes the lot occupancy with the given LotID
+	Delete(lotID string) error
+}
+
+// SessionStore persists and retrieves sessions
+type SessionStore interface {
+	// All returns every stored session
+	All() ([]types.Session, error)
+	// Get returns the session with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Session, error)
+	// Create stores a new session, which holds its plate in its lot while it is "active".
+	// It returns ErrConflict if another active session holds the plate
+	Create(session types.Session) error
+	// Transition replaces the stored session with session if the stored session is still
+	// in the state from, and otherwise returns ErrConflict. A session transitioned out of
+	// "active" gives up its plate in the same write. Sessions are never deleted
+	Transition(session types.Session, from string) error
+	// LastReceipt returns the number of the last receipt issued in the lot with the given
+	// lotID, or 0 if none has been
+	LastReceipt(lotID string) (int, error)
+	// Issue transitions session like Transition while issuing its receipt, whose number
+	// must be the one after the last receipt issued in its lot. It returns ErrConflict if
+	// the session's state or the lot's last receipt has changed
+	Issue(session types.Session, from string) error
+}
+
+// ReservationStore persists and retrieves reservations
+type ReservationStore interface {
+	// All returns every stored reservation
+	All() ([]types.Reservation, error)
+	// Get returns the reservation with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Reservation, error)
+	// Write stores reservation if the stored reservation is still at version (0 for a new
+	// reservation) while holding a space in every slot of hold that has fewer spaces held
+	// than the capacity it maps to, and giving back a space in every slot of release. It
+	// does all of that or nothing, returning ErrConflict if the reservation has changed
+	// and ErrLimitReached if a slot is full. Reservations are never deleted
+	Write(reservation types.Reservation, version int, hold map[string]int, release []string) error
+}
+
+// PermitStore persists and retrieves permits
+type PermitStore interface {
+	// All returns every stored permit
+	All() ([]types.Permit, error)
+	// Get returns the permit with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Permit, error)
+	// Put creates or replaces a permit
+	Put(permit types.Permit) error
+	// Delete removes the permit with the given UUID
+	Delete(uuid string) error
+}
+
+// MerchantStore persists and retrieves merchants
+type MerchantStore interface {
+	// All returns every stored merchant
+	All() ([]types.Merchant, error)
+	// Get returns the merchant with the given UUID, or ErrNotFound
+	Get(uuid string) (types.Merchant, error)
+	// Put creates or replaces a merchant
+	Put(merchant types.Merchant) error
+	// Delete removes the merchant with the given UUID
+	Delete(uuid string) error
+}
+
+// ValidationStore persists and retrieves merchant validations
+type ValidationStore interface {
+	// All returns every stored validation
+	All() ([]types.Validation, error)
+	// Get returns the validation with the given Code, or ErrNotFound
+	Get(code string) (types.Validation, error)
+	// Create stores a new validation, returning ErrConflict if its code is already taken
+	Create(validation types.Validation) error
+	// Transition replaces the stored validation with validation if the stored validation is
+	// still in the state from, and otherwise returns ErrConflict. Validations are never deleted
+	Transition(validation types.Validation, from string) error
+	// Spent returns what the validations of the merchant with the given merchantID have
+	// been worth in month, or 0 if none have been applied in it
+	Spent(merchantID, month string) (int, error)
+	// Apply transitions validation like Transition while adding its Worth to what its
+	// merchant has spent in its Month, as long as that stays within budget (0 for no
+	// limit). It does both or neither, returning ErrConflict if the validation's state
+	// has changed and ErrLimitReached if the budget would be exceeded
+	Apply(validation types.Validation, from string, budget int) error
+	// Unapply puts validation back in place of applied, as long as it is still stored in
+	// the state applied was stored in, while taking applied's Worth off what its merchant
+	// has spent in its Month. It does both or neither, returning ErrConflict if the
+	// validation's state has changed
+	Unapply(validation, applied types.Validation) error
+}
+
+// RouteMetricsStore persists and retrieves route metrics
+type RouteMetricsStore interface {
+	// All returns the metrics for every route
+	All() ([]types.RouteMetrics, error)
+	// Put creates or replaces the metrics for a route
+	Put(metrics types.RouteMetrics) error
+}
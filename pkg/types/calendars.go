@@ -0,0 +1,39 @@
+package types
+
+// Calendar is a named list of dates, such as holidays, that rates can target
+type Calendar struct {
+	Name string `dynamo:"Name,hash" json:"name"`
+	// Dates are in the format YYYY-MM-DD and kept in order
+	Dates     []string `dynamo:"Dates" json:"dates"`
+	UpdatedAt int64    `dynamo:"UpdatedAt" json:"updatedAt"`
+}
+
+// GetCalendarsOutput is the output from the GetCalendarsRoute
+type GetCalendarsOutput struct {
+	BaseOutput
+	Calendars []Calendar `json:"calendars"`
+}
+
+// GetCalendarOutput is the output from the GetCalendarRoute
+type GetCalendarOutput struct {
+	BaseOutput
+	Calendar Calendar `json:"calendar"`
+}
+
+// PutCalendarInput is the input to the PutCalendarRoute and contains
+// every date the calendar should have
+type PutCalendarInput struct {
+	Dates []string `json:"dates"`
+}
+
+// PutCalendarOutput is the output from the PutCalendarRoute and ImportCalendarRoute
+type PutCalendarOutput struct {
+	BaseOutput
+	Calendar Calendar `json:"calendar"`
+}
+
+// DeleteCalendarOutput is the output from the DeleteCalendarRoute
+type DeleteCalendarOutput struct {
+	BaseOutput
+	Calendar Calendar `json:"calendar"`
+}
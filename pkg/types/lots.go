@@ -0,0 +1,93 @@
+package types
+
+// Lot is a parking facility, such as a garage or a zone of one, with its own rates
+type Lot struct {
+	UUID    string `dynamo:"UUID,hash" json:"UUID"`
+	Name    string `dynamo:"Name" json:"name"`
+	Address string `dynamo:"Address,omitempty" json:"address,omitempty"`
+	// DailyMax caps what a stay is charged on any one day, in cents; unset means no cap
+	DailyMax int `dynamo:"DailyMax,omitempty" json:"dailyMax,omitempty"`
+	// MinCharge is the least a stay is charged, in cents; unset means no minimum
+	MinCharge int `dynamo:"MinCharge,omitempty" json:"minCharge,omitempty"`
+	// EntryGrace is the number of minutes a stay may last and still be free
+	EntryGrace int `dynamo:"EntryGrace,omitempty" json:"entryGrace,omitempty"`
+	// ExitGrace is the number of minutes a stay may overrun its last billing increment without being charged for it
+	ExitGrace int `dynamo:"ExitGrace,omitempty" json:"exitGrace,omitempty"`
+	// FreeMinutes is the number of minutes at the start of every stay that are free
+	FreeMinutes int `dynamo:"FreeMinutes,omitempty" json:"freeMinutes,omitempty"`
+	// Capacity is the number of spaces in the lot, which reported occupancy is a share of
+	Capacity int `dynamo:"Capacity,omitempty" json:"capacity,omitempty"`
+	// SurgeRules raise the lot's rates as it fills up, ordered by the occupancy they start above
+	SurgeRules []SurgeRule `dynamo:"SurgeRules,omitempty" json:"surgeRules,omitempty"`
+	// MaxSurge is the most, in percent, that surge rules may raise the rates by; unset means no limit
+	MaxSurge int `dynamo:"MaxSurge,omitempty" json:"maxSurge,omitempty"`
+	// ReservationCapacity is the number of spaces that may be reserved in each hour; unset means none
+	ReservationCapacity int `dynamo:"ReservationCapacity,omitempty" json:"reservationCapacity,omitempty"`
+	// ReservationSlots set the number of spaces that may be reserved in some hours instead
+	ReservationSlots []ReservationSlot `dynamo:"ReservationSlots,omitempty" json:"reservationSlots,omitempty"`
+	// CancellationRules are the fees for cancelling or changing a reservation shortly before it starts
+	CancellationRules []CancellationRule `dynamo:"CancellationRules,omitempty" json:"cancellationRules,omitempty"`
+	// Taxes are included in the prices of stays in the lot, and are broken out on receipts
+	Taxes     []Tax `dynamo:"Taxes,omitempty" json:"taxes,omitempty"`
+	CreatedAt int64 `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// Tax is a tax included in prices, at Rate hundredths of a percent (825 is 8.25%)
+type Tax struct {
+	Name string `dynamo:"Name" json:"name"`
+	Rate int    `dynamo:"Rate" json:"rate"`
+}
+
+// ReservationSlot is the number of spaces that may be reserved in each hour of Times
+// ("HHMM-HHMM" on the hour, on the wall clock of TZ) on Days
+type ReservationSlot struct {
+	Days     string `dynamo:"Days" json:"days"`
+	Times    string `dynamo:"Times" json:"times"`
+	TZ       string `dynamo:"TZ" json:"tz"`
+	Capacity int    `dynamo:"Capacity" json:"capacity"`
+}
+
+// CancellationRule charges Fee percent of a reservation's price for cancelling or
+// changing it less than Before hours before it starts
+type CancellationRule struct {
+	Before int `dynamo:"Before" json:"before"`
+	Fee    int `dynamo:"Fee" json:"fee"`
+}
+
+// SurgeRule raises the prices of a lot's rates by Increase percent while more than
+// Above percent of the lot is occupied
+type SurgeRule struct {
+	Above    int `dynamo:"Above" json:"above"`
+	Increase int `dynamo:"Increase" json:"increase"`
+}
+
+// GetLotsOutput is the output from the GetLotsRoute
+type GetLotsOutput struct {
+	BaseOutput
+	Lots []Lot `json:"lots"`
+}
+
+// LotInput is the input to the CreateLotRoute and UpdateLotRoute
+type LotInput struct {
+	Name        string      `json:"name"`
+	Address     string      `json:"address"`
+	DailyMax    int         `json:"dailyMax"`
+	MinCharge   int         `json:"minCharge"`
+	EntryGrace  int         `json:"entryGrace"`
+	ExitGrace   int         `json:"exitGrace"`
+	FreeMinutes int         `json:"freeMinutes"`
+	Capacity    int         `json:"capacity"`
+	SurgeRules  []SurgeRule `json:"surgeRules"`
+	MaxSurge    int         `json:"maxSurge"`
+
+	ReservationCapacity int                `json:"reservationCapacity"`
+	ReservationSlots    []ReservationSlot  `json:"reservationSlots"`
+	CancellationRules   []CancellationRule `json:"cancellationRules"`
+	Taxes               []Tax              `json:"taxes"`
+}
+
+// LotOutput is the output from the CreateLotRoute, GetLotRoute, UpdateLotRoute, and DeleteLotRoute
+type LotOutput struct {
+	BaseOutput
+	Lot Lot `json:"lot"`
+}
@@ -0,0 +1,114 @@
+package types
+
+// Merchant is a restaurant or shop that gives its customers validations that take
+// minutes or cents off their parking, and that is billed for what they were worth
+type Merchant struct {
+	UUID string `dynamo:"UUID,hash" json:"UUID"`
+	Name string `dynamo:"Name" json:"name"`
+	// LotIDs are the UUIDs of the lots the merchant's validations are good in; unset means every lot
+	LotIDs []string `dynamo:"LotIDs,omitempty" json:"lotIDs,omitempty"`
+	// Budget is the most in cents the merchant's validations can be worth in a calendar
+	// month; 0 means no limit
+	Budget int `dynamo:"Budget,omitempty" json:"budget,omitempty"`
+	// TZ is the timezone that the merchant's months and validation dates are in
+	TZ        string `dynamo:"TZ" json:"tz"`
+	CreatedAt int64  `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// GetMerchantsOutput is the output from the GetMerchantsRoute
+type GetMerchantsOutput struct {
+	BaseOutput
+	Merchants []Merchant `json:"merchants"`
+}
+
+// MerchantInput is the input to the CreateMerchantRoute and UpdateMerchantRoute
+type MerchantInput struct {
+	Name   string   `json:"name"`
+	LotIDs []string `json:"lotIDs"`
+	Budget int      `json:"budget"`
+	TZ     string   `json:"tz"`
+}
+
+// MerchantOutput is the output from the CreateMerchantRoute, GetMerchantRoute, UpdateMerchantRoute, and DeleteMerchantRoute
+type MerchantOutput struct {
+	BaseOutput
+	Merchant Merchant `json:"merchant"`
+}
+
+// Validation is a code a merchant gives a customer that takes "minutes" of parking or an
+// "amount" of cents off a session. It is "issued" until a session redeems it, "redeemed"
+// until that session is closed, and then "applied" with what it was worth
+type Validation struct {
+	Code       string `dynamo:"Code,hash" json:"code"`
+	MerchantID string `dynamo:"MerchantID" json:"merchantID"`
+	Type       string `dynamo:"Type" json:"type"`
+	Value      int    `dynamo:"Value" json:"value"`
+	// ValidUntil is the date (YYYY-MM-DD, in the merchant's TZ) the validation can no
+	// longer be redeemed on; unset means it does not expire
+	ValidUntil string `dynamo:"ValidUntil,omitempty" json:"validUntil,omitempty"`
+	State      string `dynamo:"State" json:"state"`
+	// SessionID is the UUID of the session that redeemed the validation
+	SessionID  string `dynamo:"SessionID,omitempty" json:"sessionID,omitempty"`
+	RedeemedAt int64  `dynamo:"RedeemedAt,omitempty" json:"redeemedAt,omitempty"`
+	// Worth is what the validation took off the session in cents, billed to the merchant
+	// for Month ("YYYY-MM", in the merchant's TZ), once it is applied
+	Worth     int    `dynamo:"Worth" json:"worth"`
+	Month     string `dynamo:"Month,omitempty" json:"month,omitempty"`
+	AppliedAt int64  `dynamo:"AppliedAt,omitempty" json:"appliedAt,omitempty"`
+	CreatedAt int64  `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// MerchantSpend is what the validations of a merchant have been worth in a month. Key is
+// the merchant's UUID and the month, such as "<UUID>#2017-01"
+type MerchantSpend struct {
+	Key   string `dynamo:"Key,hash" json:"key"`
+	Spent int    `dynamo:"Spent" json:"spent"`
+}
+
+// IssueValidationsInput is the input to the IssueValidationsRoute. Count defaults to 1
+type IssueValidationsInput struct {
+	Type       string `json:"type"`
+	Value      int    `json:"value"`
+	Count      int    `json:"count"`
+	ValidUntil string `json:"validUntil"`
+}
+
+// ValidationsOutput is the output from the IssueValidationsRoute and GetMerchantValidationsRoute
+type ValidationsOutput struct {
+	BaseOutput
+	Validations []Validation `json:"validations"`
+}
+
+// RedeemValidationInput is the input to the RedeemValidationRoute
+type RedeemValidationInput struct {
+	Code string `json:"code"`
+}
+
+// ValidationOutput is the output from the RedeemValidationRoute
+type ValidationOutput struct {
+	BaseOutput
+	Validation Validation `json:"validation"`
+}
+
+// MerchantStatement bills a merchant for what its validations were worth in a month
+type MerchantStatement struct {
+	MerchantID   string          `json:"merchantID"`
+	MerchantName string          `json:"merchantName"`
+	Month        string          `json:"month"`
+	Lines        []StatementLine `json:"lines"`
+	Total        int             `json:"total"`
+}
+
+// StatementLine is a validation billed on a statement
+type StatementLine struct {
+	Code      string `json:"code"`
+	SessionID string `json:"sessionID"`
+	AppliedAt string `json:"appliedAt"`
+	Amount    int    `json:"amount"`
+}
+
+// MerchantStatementOutput is the output from the GetMerchantStatementRoute
+type MerchantStatementOutput struct {
+	BaseOutput
+	Statement MerchantStatement `json:"statement"`
+}
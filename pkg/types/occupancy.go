@@ -0,0 +1,23 @@
+package types
+
+// Occupancy is the most recently reported number of occupied spaces in a lot
+type Occupancy struct {
+	LotID    string `dynamo:"LotID,hash" json:"lotID"`
+	Occupied int    `dynamo:"Occupied" json:"occupied"`
+	// Capacity is the lot's capacity when the occupancy was reported
+	Capacity int `dynamo:"Capacity" json:"capacity"`
+	// Percent is Occupied as a percentage of Capacity, rounded down
+	Percent    int   `dynamo:"Percent" json:"percent"`
+	ReportedAt int64 `dynamo:"ReportedAt" json:"reportedAt"`
+}
+
+// ReportOccupancyInput is the input to the ReportOccupancyRoute
+type ReportOccupancyInput struct {
+	Occupied *int `json:"occupied"`
+}
+
+// OccupancyOutput is the output from the ReportOccupancyRoute and GetOccupancyRoute
+type OccupancyOutput struct {
+	BaseOutput
+	Occupancy Occupancy `json:"occupancy"`
+}
@@ -0,0 +1,48 @@
+package types
+
+// Permit is a monthly or annual pass that lets the vehicles with its plates park for
+// free during its hours, between the dates it is valid from and until
+type Permit struct {
+	UUID   string `dynamo:"UUID,hash" json:"UUID"`
+	Holder string `dynamo:"Holder" json:"holder"`
+	// Plates are the license plates the permit covers, in upper case without spaces
+	Plates []string `dynamo:"Plates" json:"plates"`
+	// LotIDs are the UUIDs of the lots the permit is good in; unset means every lot
+	LotIDs []string `dynamo:"LotIDs,omitempty" json:"lotIDs,omitempty"`
+	// Days is a comma separated list of the days the permit's hours start on
+	Days string `dynamo:"Days" json:"days"`
+	// Times are the permit's hours ("HHMM-HHMM", which may wrap past midnight) on each of
+	// its days; unset means all day
+	Times string `dynamo:"Times,omitempty" json:"times,omitempty"`
+	// TZ is the timezone that Times and the valid dates are in
+	TZ string `dynamo:"TZ" json:"tz"`
+	// ValidFrom is the first date (YYYY-MM-DD, in TZ) the permit is good on, and
+	// ValidUntil is the date it stops being good on
+	ValidFrom  string `dynamo:"ValidFrom" json:"validFrom"`
+	ValidUntil string `dynamo:"ValidUntil" json:"validUntil"`
+	CreatedAt  int64  `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// GetPermitsOutput is the output from the GetPermitsRoute
+type GetPermitsOutput struct {
+	BaseOutput
+	Permits []Permit `json:"permits"`
+}
+
+// PermitInput is the input to the CreatePermitRoute and UpdatePermitRoute
+type PermitInput struct {
+	Holder     string   `json:"holder"`
+	Plates     []string `json:"plates"`
+	LotIDs     []string `json:"lotIDs"`
+	Days       string   `json:"days"`
+	Times      string   `json:"times"`
+	TZ         string   `json:"tz"`
+	ValidFrom  string   `json:"validFrom"`
+	ValidUntil string   `json:"validUntil"`
+}
+
+// PermitOutput is the output from the CreatePermitRoute, GetPermitRoute, UpdatePermitRoute, and DeletePermitRoute
+type PermitOutput struct {
+	BaseOutput
+	Permit Permit `json:"permit"`
+}
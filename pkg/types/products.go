@@ -0,0 +1,47 @@
+package types
+
+// Product is a flat price for a stay that enters and exits within set windows,
+// such as an early bird or an evening rate, whatever rates cover the stay
+type Product struct {
+	UUID string `dynamo:"UUID,hash" json:"UUID"`
+	Name string `dynamo:"Name" json:"name"`
+	// LotID is the UUID of the lot the product is for; products without one are for the default schedule
+	LotID string `dynamo:"LotID,omitempty" json:"lotID,omitempty"`
+	// VehicleClass limits the product to one class of vehicle; products without one are for every class
+	VehicleClass string `dynamo:"VehicleClass,omitempty" json:"vehicleClass,omitempty"`
+	// Days is a comma separated list of the days a stay may enter on
+	Days string `dynamo:"Days" json:"days"`
+	// EntryWindow is the "HHMM-HHMM" range a stay must enter within
+	EntryWindow string `dynamo:"EntryWindow" json:"entryWindow"`
+	// ExitWindow is the "HHMM-HHMM" range a stay must exit within, on the day it entered;
+	// a range that wraps past midnight ends on the next day
+	ExitWindow string `dynamo:"ExitWindow" json:"exitWindow"`
+	TZ         string `dynamo:"TZ" json:"tz"`
+	// Price is the flat price of the stay in cents
+	Price     int   `dynamo:"Price" json:"price"`
+	CreatedAt int64 `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// GetProductsOutput is the output from the GetProductsRoute
+type GetProductsOutput struct {
+	BaseOutput
+	Products []Product `json:"products"`
+}
+
+// ProductInput is the input to the CreateProductRoute and UpdateProductRoute
+type ProductInput struct {
+	Name         string `json:"name"`
+	LotID        string `json:"lotID"`
+	VehicleClass string `json:"vehicleClass"`
+	Days         string `json:"days"`
+	EntryWindow  string `json:"entryWindow"`
+	ExitWindow   string `json:"exitWindow"`
+	TZ           string `json:"tz"`
+	Price        int    `json:"price"`
+}
+
+// ProductOutput is the output from the CreateProductRoute, GetProductRoute, UpdateProductRoute, and DeleteProductRoute
+type ProductOutput struct {
+	BaseOutput
+	Product Product `json:"product"`
+}
@@ -0,0 +1,54 @@
+package types
+
+// Promo is a code that takes a percentage or a fixed amount off the price of a stay
+type Promo struct {
+	UUID string `dynamo:"UUID,hash" json:"UUID"`
+	// Code is what customers enter to get the discount; codes are unique regardless of case
+	Code string `dynamo:"Code" json:"code"`
+	Name string `dynamo:"Name" json:"name"`
+	// Type is "percent" to take Value percent off, or "amount" to take Value cents off
+	Type  string `dynamo:"Type" json:"type"`
+	Value int    `dynamo:"Value" json:"value"`
+	// LotID limits the promo to stays in one lot; promos without one are for every lot
+	LotID string `dynamo:"LotID,omitempty" json:"lotID,omitempty"`
+	// Days is a comma separated list of the days a stay must start on; unset means every day
+	Days string `dynamo:"Days,omitempty" json:"days,omitempty"`
+	// TZ is the timezone that Days and the effective dates are in
+	TZ string `dynamo:"TZ" json:"tz"`
+	// EffectiveFrom is the first date (YYYY-MM-DD, in TZ) a stay may start on; unset means always
+	EffectiveFrom string `dynamo:"EffectiveFrom,omitempty" json:"effectiveFrom,omitempty"`
+	// EffectiveUntil is the date (YYYY-MM-DD, in TZ) the promo stops applying on; unset means never
+	EffectiveUntil string `dynamo:"EffectiveUntil,omitempty" json:"effectiveUntil,omitempty"`
+	// MaxUses is how many times the promo may be redeemed; unset means no limit
+	MaxUses int `dynamo:"MaxUses,omitempty" json:"maxUses,omitempty"`
+	// Uses is how many times the promo has been redeemed
+	Uses      int   `dynamo:"Uses" json:"uses"`
+	CreatedAt int64 `dynamo:"CreatedAt" json:"createdAt"`
+}
+
+// GetPromosOutput is the output from the GetPromosRoute
+type GetPromosOutput struct {
+	BaseOutput
+	Promos []Promo `json:"promos"`
+}
+
+// PromoInput is the input to the CreatePromoRoute and UpdatePromoRoute
+type PromoInput struct {
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Value          int    `json:"value"`
+	LotID          string `json:"lotID"`
+	Days           string `json:"days"`
+	TZ             string `json:"tz"`
+	EffectiveFrom  string `json:"effectiveFrom"`
+	EffectiveUntil string `json:"effectiveUntil"`
+	MaxUses        int    `json:"maxUses"`
+}
+
+// PromoOutput is the output from the CreatePromoRoute, GetPromoRoute, UpdatePromoRoute,
+// DeletePromoRoute, and RedeemPromoRoute
+type PromoOutput struct {
+	BaseOutput
+	Promo Promo `json:"promo"`
+}
@@ -7,6 +7,59 @@ type Rate struct {
 	Times string `dynamo:"Times" json:"times"`
 	TZ    string `dynamo:"TZ" json:"tz"`
 	Price int    `dynamo:"Price" json:"price"`
+	// LotID is the UUID of the lot the rate is for; rates without one make up the default schedule
+	LotID string `dynamo:"LotID,omitempty" json:"lotID,omitempty"`
+	// VehicleClass is the class of vehicle the rate is for: "motorcycle", "oversize", or "ev";
+	// rates without one are for the default class
+	VehicleClass string `dynamo:"VehicleClass,omitempty" json:"vehicleClass,omitempty"`
+	// Dates is a comma separated list of YYYY-MM-DD dates the rate applies on instead of Days
+	Dates string `dynamo:"Dates,omitempty" json:"dates,omitempty"`
+	// Calendar is the name of a calendar whose dates the rate applies on instead of Days
+	Calendar string `dynamo:"Calendar,omitempty" json:"calendar,omitempty"`
+	// BillingIncrement is the number of minutes billed at a time (defaults to 60)
+	BillingIncrement int `dynamo:"BillingIncrement,omitempty" json:"billingIncrement,omitempty"`
+	// Rounding is how a partial increment is billed: "up" (default), "down", or "nearest"
+	Rounding string `dynamo:"Rounding,omitempty" json:"rounding,omitempty"`
+	// Tiers are ordered hourly prices for stretches of a stay; time no tier covers is charged Price
+	Tiers []PriceTier `dynamo:"Tiers,omitempty" json:"tiers,omitempty"`
+	// DailyMax caps what the rate charges on any one day of a stay, in cents; unset means no cap
+	DailyMax int `dynamo:"DailyMax,omitempty" json:"dailyMax,omitempty"`
+	// MinCharge is the least the rate charges for a stay it covers any of, in cents; unset means no minimum
+	MinCharge int `dynamo:"MinCharge,omitempty" json:"minCharge,omitempty"`
+	// EntryGrace, ExitGrace and FreeMinutes replace the lot's grace periods, in minutes, for stays
+	// that the rate covers the start (entry grace and free minutes) or the end (exit grace) of
+	EntryGrace  int `dynamo:"EntryGrace,omitempty" json:"entryGrace,omitempty"`
+	ExitGrace   int `dynamo:"ExitGrace,omitempty" json:"exitGrace,omitempty"`
+	FreeMinutes int `dynamo:"FreeMinutes,omitempty" json:"freeMinutes,omitempty"`
+	// EffectiveFrom is the first date (YYYY-MM-DD, in TZ) the rate applies on; unset means always
+	EffectiveFrom string `dynamo:"EffectiveFrom,omitempty" json:"effectiveFrom,omitempty"`
+	// EffectiveUntil is the date (YYYY-MM-DD, in TZ) the rate stops applying on; unset means never
+	EffectiveUntil string `dynamo:"EffectiveUntil,omitempty" json:"effectiveUntil,omitempty"`
+	// Version goes up by one every time the rate is changed
+	Version int    `dynamo:"Version" json:"version"`
+	SetID   string `dynamo:"SetID,omitempty" json:"-"`
+	// ExceptDates lists the dates a weekday rate is overridden on. It is never stored
+	// and is only filled in while rates are matched
+	ExceptDates string `dynamo:"-" json:"-"`
+}
+
+// PriceTier is the hourly price, in cents, charged for the minutes of a stay from From
+// until Until, counted from when the rate starts covering the stay. Until may be 0 on
+// the last tier for a tier that never ends
+type PriceTier struct {
+	From  int `dynamo:"From" json:"from"`
+	Until int `dynamo:"Until,omitempty" json:"until,omitempty"`
+	Price int `dynamo:"Price" json:"price"`
+}
+
+// RateSet points at the set of rates that is currently active. Replacing every
+// rate at once writes a brand new set and then switches this pointer in a single
+// conditional write, so readers only ever see one complete set of rates
+type RateSet struct {
+	Name      string `dynamo:"Name,hash" json:"name"`
+	SetID     string `dynamo:"SetID" json:"setID"`
+	Revision  int    `dynamo:"Revision" json:"revision"`
+	UpdatedAt int64  `dynamo:"UpdatedAt" json:"updatedAt"`
 }
 
 // GetRatesOutput is the output from the GetAllRatesRoute
@@ -18,10 +71,24 @@ type GetRatesOutput struct {
 // CreateRateInput is the input to the CreateRateRoute and contains
 // the fields necessary to create a new rate
 type CreateRateInput struct {
-	Days  string `json:"days"`
-	Times string `json:"times"`
-	TZ    string `json:"tz"`
-	Price int    `json:"price"`
+	LotID            string      `json:"lotID"`
+	VehicleClass     string      `json:"vehicleClass"`
+	Days             string      `json:"days"`
+	Times            string      `json:"times"`
+	TZ               string      `json:"tz"`
+	Price            int         `json:"price"`
+	BillingIncrement int         `json:"billingIncrement"`
+	Rounding         string      `json:"rounding"`
+	Tiers            []PriceTier `json:"tiers"`
+	DailyMax         int         `json:"dailyMax"`
+	MinCharge        int         `json:"minCharge"`
+	EntryGrace       int         `json:"entryGrace"`
+	ExitGrace        int         `json:"exitGrace"`
+	FreeMinutes      int         `json:"freeMinutes"`
+	EffectiveFrom    string      `json:"effectiveFrom"`
+	EffectiveUntil   string      `json:"effectiveUntil"`
+	Dates            string      `json:"dates"`
+	Calendar         string      `json:"calendar"`
 }
 
 // CreateRateOutput is the output from the CreateRateRoute
@@ -30,6 +97,64 @@ type CreateRateOutput struct {
 	Rate Rate `json:"rate"`
 }
 
+// GetRateOutput is the output from the GetRateRoute
+type GetRateOutput struct {
+	BaseOutput
+	Rate Rate `json:"rate"`
+}
+
+// UpdateRateOutput is the output from the UpdateRateRoute and PatchRateRoute.
+// CurrentVersion is set when the rate was changed by someone else first
+type UpdateRateOutput struct {
+	BaseOutput
+	Rate           Rate `json:"rate"`
+	CurrentVersion int  `json:"currentVersion,omitempty"`
+}
+
+// PatchRateInput is the input to the PatchRateRoute. Only the fields
+// that are given are changed on the rate
+type PatchRateInput struct {
+	LotID            *string      `json:"lotID"`
+	VehicleClass     *string      `json:"vehicleClass"`
+	Days             *string      `json:"days"`
+	Times            *string      `json:"times"`
+	TZ               *string      `json:"tz"`
+	Price            *int         `json:"price"`
+	BillingIncrement *int         `json:"billingIncrement"`
+	Rounding         *string      `json:"rounding"`
+	Tiers            *[]PriceTier `json:"tiers"`
+	DailyMax         *int         `json:"dailyMax"`
+	MinCharge        *int         `json:"minCharge"`
+	EntryGrace       *int         `json:"entryGrace"`
+	ExitGrace        *int         `json:"exitGrace"`
+	FreeMinutes      *int         `json:"freeMinutes"`
+	EffectiveFrom    *string      `json:"effectiveFrom"`
+	EffectiveUntil   *string      `json:"effectiveUntil"`
+	Dates            *string      `json:"dates"`
+	Calendar         *string      `json:"calendar"`
+}
+
+// DeleteRateOutput is the output from the DeleteRateRoute.
+// CurrentVersion is set when the rate was changed by someone else first
+type DeleteRateOutput struct {
+	BaseOutput
+	Rate           Rate `json:"rate"`
+	CurrentVersion int  `json:"currentVersion,omitempty"`
+}
+
+// GetUpcomingRateChangesOutput is the output from the GetUpcomingRateChangesRoute
+type GetUpcomingRateChangesOutput struct {
+	BaseOutput
+	Changes []RateChange `json:"changes"`
+}
+
+// RateChange is a date on which a rate starts or stops applying
+type RateChange struct {
+	Date   string `json:"date"`
+	Change string `json:"change"`
+	Rate   Rate   `json:"rate"`
+}
+
 // OverwriteRatesInput is the input to the OverwriteRatesRoute
 type OverwriteRatesInput struct {
 	Rates *[]CreateRateInput `json:"rates"`
@@ -45,10 +170,134 @@ type OverwriteRatesOutput struct {
 type GetTimespanPriceInput struct {
 	Start *string `json:"start"`
 	End   *string `json:"end"`
+	// LotID picks which lot's rates to quote from; unset quotes from the default schedule
+	LotID string `json:"lotID"`
+	// VehicleClass picks which class of vehicle's rates to quote from; a class with no
+	// rates of its own in the lot is quoted from the default class
+	VehicleClass string `json:"vehicleClass"`
+	// TZ picks which timezone's rates to quote from; it is only needed when rates
+	// exist in several timezones that cannot be told apart by the offset of start
+	TZ string `json:"tz"`
+	// PromoCode is a promo code to take off the price
+	PromoCode string `json:"promoCode"`
+	// Plate is the vehicle's license plate, which does not pay for the hours its permits cover
+	Plate string `json:"plate"`
 }
 
 // GetTimespanPriceOutput is the output from the CalculateTimeSpanCostRoute
 type GetTimespanPriceOutput struct {
 	BaseOutput
 	Price string `json:"price"`
+	Quote *Quote `json:"quote,omitempty"`
+}
+
+// Quote is the itemized price of a timespan. The day subtotals and total include
+// the adjustments made by daily maximums, minimum charges, grace periods, promos and
+// the merchant validations that a closed session redeemed.
+// A stay priced by a product has the product instead of segments, and Reason says why
+// the product or the rates were chosen whenever a product was eligible. A stay that a
+// permit covers has the permit, and only the parts of it outside the permit are priced
+type Quote struct {
+	Total        int                `json:"total"`
+	Days         []DaySubtotal      `json:"days"`
+	Segments     []PriceSegment     `json:"segments"`
+	CapApplied   bool               `json:"capApplied"`
+	FloorApplied bool               `json:"floorApplied"`
+	Adjustments  []PriceAdjustment  `json:"adjustments,omitempty"`
+	Product      *QuotedProduct     `json:"product,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+	Promo        *QuotedPromo       `json:"promo,omitempty"`
+	Surge        *QuotedSurge       `json:"surge,omitempty"`
+	Permit       *QuotedPermit      `json:"permit,omitempty"`
+	Validations  []QuotedValidation `json:"validations,omitempty"`
+}
+
+// QuotedValidation is a merchant validation redeemed by a closed session, and Discount is
+// what it took off the price. A validation that could not take anything off has the
+// reason it was Rejected
+type QuotedValidation struct {
+	Code       string `json:"code"`
+	MerchantID string `json:"merchantID"`
+	Discount   int    `json:"discount"`
+	Rejected   string `json:"rejected,omitempty"`
+}
+
+// QuotedPermit is the permit that covered some or all of a quoted stay. Only the
+// parts of the stay outside the permit's hours are charged
+type QuotedPermit struct {
+	UUID           string `json:"UUID"`
+	Holder         string `json:"holder"`
+	CoveredMinutes int    `json:"coveredMinutes"`
+}
+
+// QuotedSurge is the surge pricing that raised the rates of a quote. Occupancy is the
+// lot's reported occupancy in percent, and the rates were multiplied by Multiplier, which
+// is 1 plus the Increase in percent. Capped is set when the lot's MaxSurge lowered it
+type QuotedSurge struct {
+	Occupancy  int     `json:"occupancy"`
+	Increase   int     `json:"increase"`
+	Multiplier float64 `json:"multiplier"`
+	Capped     bool    `json:"capped"`
+}
+
+// QuotedPromo is the promo code given for a quote. Subtotal is the price before the
+// promo, and Discount is what the promo took off it. A code that could not be used
+// takes nothing off and has the reason it was Rejected
+type QuotedPromo struct {
+	UUID     string `json:"UUID,omitempty"`
+	Code     string `json:"code"`
+	Subtotal int    `json:"subtotal"`
+	Discount int    `json:"discount"`
+	Rejected string `json:"rejected,omitempty"`
+}
+
+// QuotedProduct is the product that priced a stay
+type QuotedProduct struct {
+	UUID  string `json:"UUID"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+// DaySubtotal is the price of the part of a quoted timespan that falls on one day
+type DaySubtotal struct {
+	Date  string `json:"date"`
+	Price int    `json:"price"`
+}
+
+// PriceAdjustment is a change made to a quote by a daily maximum ("dailyMax"), a minimum
+// charge ("minCharge"), an entry grace period ("entryGrace"), an exit grace period
+// ("exitGrace") or free minutes ("freeMinutes") of a rate or of the lot, by a
+// promo code ("promo"), or by a merchant validation ("validation") whose Code it has
+type PriceAdjustment struct {
+	Type     string `json:"type"`
+	Source   string `json:"source"`
+	RateUUID string `json:"rateUUID,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Date     string `json:"date"`
+	Amount   int    `json:"amount"`
+}
+
+// PriceSegment is the part of a quoted timespan that is covered by a single rate
+type PriceSegment struct {
+	RateUUID         string `json:"rateUUID"`
+	Start            string `json:"start"`
+	End              string `json:"end"`
+	Minutes          int    `json:"minutes"`
+	BillingIncrement int    `json:"billingIncrement"`
+	Rounding         string `json:"rounding"`
+	BillableUnits    int    `json:"billableUnits"`
+	Price            int    `json:"price"`
+	// Tiers breaks the price down by tier for rates that have tiers
+	Tiers []TierCharge `json:"tiers,omitempty"`
+}
+
+// TierCharge is the part of a segment's price charged at one tier of its rate. Tier is
+// the position of the tier in the rate's tiers starting at 1, or 0 for time that no tier
+// covers, and From and Until are the minutes of the stay that were charged
+type TierCharge struct {
+	Tier          int `json:"tier"`
+	From          int `json:"from"`
+	Until         int `json:"until"`
+	BillableUnits int `json:"billableUnits"`
+	Price         int `json:"price"`
 }
@@ -0,0 +1,51 @@
+package types
+
+// Receipt is the itemized receipt of a paid stay, issued when its session is closed.
+// Receipts are numbered in order within each lot, and sessions without a lot are
+// numbered together
+type Receipt struct {
+	Number     int    `dynamo:"Number" json:"number"`
+	LotID      string `dynamo:"LotID,omitempty" json:"lotID,omitempty"`
+	LotName    string `dynamo:"LotName,omitempty" json:"lotName,omitempty"`
+	LotAddress string `dynamo:"LotAddress,omitempty" json:"lotAddress,omitempty"`
+	Plate      string `dynamo:"Plate" json:"plate"`
+	// EntryTime, ExitTime and IssuedAt are in the format "2017-01-06T17:00:00-06:00"
+	EntryTime string `dynamo:"EntryTime" json:"entryTime"`
+	ExitTime  string `dynamo:"ExitTime" json:"exitTime"`
+	IssuedAt  string `dynamo:"IssuedAt" json:"issuedAt"`
+	// Charges are the rate segments or the product that priced the stay
+	Charges []ReceiptLine `dynamo:"Charges" json:"charges"`
+	// Adjustments are the changes that maximums, minimums, grace periods and promos made to the charges
+	Adjustments []ReceiptLine `dynamo:"Adjustments,omitempty" json:"adjustments,omitempty"`
+	// Subtotal is the part of the total that is not taxes
+	Subtotal int          `dynamo:"Subtotal" json:"subtotal"`
+	Taxes    []ReceiptTax `dynamo:"Taxes,omitempty" json:"taxes,omitempty"`
+	Total    int          `dynamo:"Total" json:"total"`
+}
+
+// ReceiptLine is one line of a receipt, in cents
+type ReceiptLine struct {
+	Description string `dynamo:"Description" json:"description"`
+	Amount      int    `dynamo:"Amount" json:"amount"`
+}
+
+// ReceiptTax is the part of a receipt's total that a lot's tax makes up, in cents
+type ReceiptTax struct {
+	Name   string `dynamo:"Name" json:"name"`
+	Rate   int    `dynamo:"Rate" json:"rate"`
+	Amount int    `dynamo:"Amount" json:"amount"`
+}
+
+// ReceiptCounter is the number of the last receipt issued in the lot with the given
+// LotID, or in no lot when LotID is "default"
+type ReceiptCounter struct {
+	LotID string `dynamo:"LotID,hash" json:"lotID"`
+	Last  int    `dynamo:"Last" json:"last"`
+}
+
+// ReceiptOutput is the output from the GetSessionReceiptRoute when the receipt is
+// asked for as JSON
+type ReceiptOutput struct {
+	BaseOutput
+	Receipt Receipt `json:"receipt"`
+}
@@ -0,0 +1,62 @@
+package types
+
+// Reservation is a space booked in a lot ahead of time, at a price that is locked when
+// it is booked. It is "booked" until it is "cancelled"
+type Reservation struct {
+	UUID         string `dynamo:"UUID,hash" json:"UUID"`
+	LotID        string `dynamo:"LotID" json:"lotID"`
+	Plate        string `dynamo:"Plate" json:"plate"`
+	VehicleClass string `dynamo:"VehicleClass,omitempty" json:"vehicleClass,omitempty"`
+	// Start and End are in the format "2017-01-06T17:00:00-06:00"
+	Start string `dynamo:"Start" json:"start"`
+	End   string `dynamo:"End" json:"end"`
+	State string `dynamo:"State" json:"state"`
+	// Price is what the stay was quoted when it was last booked or changed, in cents
+	Price int    `dynamo:"Price" json:"price"`
+	Quote *Quote `dynamo:"Quote,omitempty" json:"quote,omitempty"`
+	// Fees is what has been charged for cancelling or changing the reservation late, in cents
+	Fees int `dynamo:"Fees" json:"fees"`
+	// Version goes up by one every time the reservation is changed
+	Version   int   `dynamo:"Version" json:"version"`
+	CreatedAt int64 `dynamo:"CreatedAt" json:"createdAt"`
+	UpdatedAt int64 `dynamo:"UpdatedAt" json:"updatedAt"`
+}
+
+// ReservationSlotCount is the number of spaces held by reservations in one hour of a
+// lot. Slot is the lot's UUID and the hour in UTC, such as "<UUID>#2017-01-06T23:00:00Z"
+type ReservationSlotCount struct {
+	Slot  string `dynamo:"Slot,hash" json:"slot"`
+	Count int    `dynamo:"Count" json:"count"`
+}
+
+// GetReservationsOutput is the output from the GetReservationsRoute
+type GetReservationsOutput struct {
+	BaseOutput
+	Reservations []Reservation `json:"reservations"`
+}
+
+// CreateReservationInput is the input to the CreateReservationRoute
+type CreateReservationInput struct {
+	LotID        string `json:"lotID"`
+	Plate        string `json:"plate"`
+	VehicleClass string `json:"vehicleClass"`
+	Start        string `json:"start"`
+	End          string `json:"end"`
+}
+
+// ModifyReservationInput is the input to the ModifyReservationRoute. It replaces the
+// plate, vehicle class and times of the reservation
+type ModifyReservationInput struct {
+	Plate        string `json:"plate"`
+	VehicleClass string `json:"vehicleClass"`
+	Start        string `json:"start"`
+	End          string `json:"end"`
+}
+
+// ReservationOutput is the output from the CreateReservationRoute, GetReservationRoute,
+// ModifyReservationRoute, and CancelReservationRoute. Fee is what the request was charged
+type ReservationOutput struct {
+	BaseOutput
+	Reservation Reservation `json:"reservation"`
+	Fee         int         `json:"fee"`
+}
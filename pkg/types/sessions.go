@@ -0,0 +1,65 @@
+package types
+
+// Session is a stay of a vehicle that is actually parked. It is "active" from when it
+// starts until it is ended, when it is "closed" with the fee for the stay, or "voided"
+// when it should never be charged
+type Session struct {
+	UUID string `dynamo:"UUID,hash" json:"UUID"`
+	// LotID is the UUID of the lot the vehicle is parked in; sessions without one are priced from the default schedule
+	LotID string `dynamo:"LotID,omitempty" json:"lotID,omitempty"`
+	// Plate is the vehicle's license plate, in upper case without spaces
+	Plate        string `dynamo:"Plate" json:"plate"`
+	VehicleClass string `dynamo:"VehicleClass,omitempty" json:"vehicleClass,omitempty"`
+	State        string `dynamo:"State" json:"state"`
+	// EntryTime and ExitTime are in the format "2017-01-06T17:00:00-06:00"
+	EntryTime string `dynamo:"EntryTime" json:"entryTime"`
+	ExitTime  string `dynamo:"ExitTime,omitempty" json:"exitTime,omitempty"`
+	// Fee is what the stay was charged in cents, and Quote itemizes it, once the session is closed
+	Fee   int    `dynamo:"Fee" json:"fee"`
+	Quote *Quote `dynamo:"Quote,omitempty" json:"quote,omitempty"`
+	// Receipt is issued when a session is closed with a fee
+	Receipt *Receipt `dynamo:"Receipt,omitempty" json:"receipt,omitempty"`
+	// VoidReason says why a voided session was voided
+	VoidReason string `dynamo:"VoidReason,omitempty" json:"voidReason,omitempty"`
+	CreatedAt  int64  `dynamo:"CreatedAt" json:"createdAt"`
+	UpdatedAt  int64  `dynamo:"UpdatedAt" json:"updatedAt"`
+}
+
+// ActivePlate is held by the active session of a plate in a lot, so that a plate can
+// only have one. Key is the lot's UUID and the plate, such as "<UUID>#ABC123", or
+// "default#ABC123" for sessions without a lot
+type ActivePlate struct {
+	Key       string `dynamo:"Key,hash" json:"key"`
+	SessionID string `dynamo:"SessionID" json:"sessionID"`
+}
+
+// GetSessionsOutput is the output from the GetSessionsRoute
+type GetSessionsOutput struct {
+	BaseOutput
+	Sessions []Session `json:"sessions"`
+}
+
+// StartSessionInput is the input to the StartSessionRoute. EntryTime defaults to now
+type StartSessionInput struct {
+	LotID        string `json:"lotID"`
+	Plate        string `json:"plate"`
+	VehicleClass string `json:"vehicleClass"`
+	EntryTime    string `json:"entryTime"`
+}
+
+// EndSessionInput is the input to the EndSessionRoute. ExitTime defaults to now
+type EndSessionInput struct {
+	ExitTime  string `json:"exitTime"`
+	PromoCode string `json:"promoCode"`
+}
+
+// VoidSessionInput is the input to the VoidSessionRoute
+type VoidSessionInput struct {
+	Reason string `json:"reason"`
+}
+
+// SessionOutput is the output from the StartSessionRoute, GetSessionRoute, EndSessionRoute, and VoidSessionRoute
+type SessionOutput struct {
+	BaseOutput
+	Session Session `json:"session"`
+}